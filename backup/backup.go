@@ -0,0 +1,183 @@
+// Package backup exports a stack to a local directory and imports such an
+// export into another stack.
+//
+// The export uses a deterministic layout, so exports of the same stack can be
+// compared and kept in version control:
+//
+//	locales.json
+//	environments.json
+//	webhooks.json
+//	global_fields/<uid>.json
+//	content_types/<uid>.json
+//	assets/<uid>/asset.json
+//	assets/<uid>/<filename>
+//	entries/<content type>/<locale>/<uid>.json
+//
+// The import creates new entries and assets, so their uids differ from the
+// exported uids. References to entries and assets are rewritten to the new
+// uids. The progress of the import is kept in a state file, so a failed
+// import can be resumed by running it again.
+package backup
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/labd/contentstack-go-sdk/management"
+)
+
+const (
+	localesFile      = "locales.json"
+	environmentsFile = "environments.json"
+	webhooksFile     = "webhooks.json"
+	globalFieldsDir  = "global_fields"
+	contentTypesDir  = "content_types"
+	assetsDir        = "assets"
+	assetFile        = "asset.json"
+	entriesDir       = "entries"
+)
+
+// entryFile is the exported version of an entry in a single locale.
+type entryFile struct {
+	UID    string                 `json:"uid"`
+	Locale string                 `json:"locale"`
+	Tags   []string               `json:"tags,omitempty"`
+	Fields map[string]interface{} `json:"fields"`
+
+	contentType string
+}
+
+// export is the content of an export directory.
+type export struct {
+	locales      []management.Locale
+	environments []management.Environment
+	webhooks     []management.WebHook
+	globalFields []management.GlobalField
+	contentTypes []management.ContentType
+	assets       []management.Asset
+	entries      []entryFile
+}
+
+func writeJSON(path string, v interface{}) error {
+	data, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return fmt.Errorf("Unable to serialize %s: %w", path, err)
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	return os.WriteFile(path, append(data, '\n'), 0o644)
+}
+
+func readJSON(path string, v interface{}) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	if err := json.Unmarshal(data, v); err != nil {
+		return fmt.Errorf("Unable to parse %s: %w", path, err)
+	}
+	return nil
+}
+
+// readDir returns the sorted names of the entries in the directory, or nothing
+// when the directory doesn't exist.
+func readDir(path string, dirs bool) ([]string, error) {
+	items, err := os.ReadDir(path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	names := []string{}
+	for _, item := range items {
+		if item.IsDir() == dirs {
+			names = append(names, item.Name())
+		}
+	}
+	sort.Strings(names)
+	return names, nil
+}
+
+func readExport(dir string) (*export, error) {
+	result := &export{}
+	for path, v := range map[string]interface{}{
+		localesFile:      &result.locales,
+		environmentsFile: &result.environments,
+		webhooksFile:     &result.webhooks,
+	} {
+		if err := readJSON(filepath.Join(dir, path), v); err != nil && !os.IsNotExist(err) {
+			return nil, err
+		}
+	}
+
+	files, err := readDir(filepath.Join(dir, globalFieldsDir), false)
+	if err != nil {
+		return nil, err
+	}
+	for _, file := range files {
+		item := management.GlobalField{}
+		if err := readJSON(filepath.Join(dir, globalFieldsDir, file), &item); err != nil {
+			return nil, err
+		}
+		result.globalFields = append(result.globalFields, item)
+	}
+
+	files, err = readDir(filepath.Join(dir, contentTypesDir), false)
+	if err != nil {
+		return nil, err
+	}
+	for _, file := range files {
+		item := management.ContentType{}
+		if err := readJSON(filepath.Join(dir, contentTypesDir, file), &item); err != nil {
+			return nil, err
+		}
+		result.contentTypes = append(result.contentTypes, item)
+	}
+
+	uids, err := readDir(filepath.Join(dir, assetsDir), true)
+	if err != nil {
+		return nil, err
+	}
+	for _, uid := range uids {
+		item := management.Asset{}
+		if err := readJSON(filepath.Join(dir, assetsDir, uid, assetFile), &item); err != nil {
+			return nil, err
+		}
+		result.assets = append(result.assets, item)
+	}
+
+	contentTypes, err := readDir(filepath.Join(dir, entriesDir), true)
+	if err != nil {
+		return nil, err
+	}
+	for _, contentType := range contentTypes {
+		locales, err := readDir(filepath.Join(dir, entriesDir, contentType), true)
+		if err != nil {
+			return nil, err
+		}
+		for _, locale := range locales {
+			files, err := readDir(filepath.Join(dir, entriesDir, contentType, locale), false)
+			if err != nil {
+				return nil, err
+			}
+			for _, file := range files {
+				item := entryFile{contentType: contentType}
+				if err := readJSON(filepath.Join(dir, entriesDir, contentType, locale, file), &item); err != nil {
+					return nil, err
+				}
+				if item.Fields == nil {
+					item.Fields = map[string]interface{}{}
+				}
+				result.entries = append(result.entries, item)
+			}
+		}
+	}
+
+	return result, nil
+}
@@ -0,0 +1,493 @@
+package backup
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/labd/contentstack-go-sdk/management"
+	"github.com/labd/contentstack-go-sdk/management/managementtest"
+	"github.com/labd/contentstack-go-sdk/schema"
+)
+
+func TestUIDMap_Remap(t *testing.T) {
+	m := &uidMap{
+		assets:   map[string]string{"old_asset": "new_asset"},
+		entries:  map[string]string{"old_1": "new_1"},
+		exported: map[string]bool{"old_1": true, "old_2": true},
+	}
+
+	fields := map[string]interface{}{
+		"image": "old_asset",
+		"related": []interface{}{
+			map[string]interface{}{"uid": "old_1", "_content_type_uid": "blog"},
+			map[string]interface{}{"uid": "old_2", "_content_type_uid": "blog"},
+		},
+		"author": map[string]interface{}{"uid": "old_2", "_content_type_uid": "author"},
+	}
+
+	got := m.remap(fields, true)
+	want := map[string]interface{}{
+		"image": "new_asset",
+		"related": []interface{}{
+			map[string]interface{}{"uid": "new_1", "_content_type_uid": "blog"},
+		},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("remap(strip) = %v, want %v", got, want)
+	}
+
+	got = m.remap(fields, false)
+	want = map[string]interface{}{
+		"image": "new_asset",
+		"related": []interface{}{
+			map[string]interface{}{"uid": "new_1", "_content_type_uid": "blog"},
+			map[string]interface{}{"uid": "old_2", "_content_type_uid": "blog"},
+		},
+		"author": map[string]interface{}{"uid": "old_2", "_content_type_uid": "author"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("remap() = %v, want %v", got, want)
+	}
+
+	if !m.references(fields) {
+		t.Error("references() = false, want true")
+	}
+}
+
+// importServer is a minimal stack which returns no existing resources and
+// assigns new uids to created entries.
+type importServer struct {
+	mu       sync.Mutex
+	created  int
+	locales  []interface{}
+	failures map[string]bool
+	requests []string
+}
+
+func (s *importServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	body := map[string]interface{}{}
+	_ = json.NewDecoder(r.Body).Decode(&body)
+	if len(s.locales) == 0 {
+		// The master locale always exists
+		s.locales = append(s.locales, map[string]interface{}{"code": "en-us"})
+	}
+	entry, _ := json.Marshal(body["entry"])
+	request := fmt.Sprintf("%s %s?%s %s", r.Method, r.URL.Path, r.URL.Query().Get("locale"), entry)
+
+	if s.failures[request] {
+		delete(s.failures, request)
+		w.WriteHeader(http.StatusInternalServerError)
+		_, _ = w.Write([]byte(`{"error_message":"Something went wrong"}`))
+		return
+	}
+	s.requests = append(s.requests, request)
+
+	switch {
+	case r.Method == http.MethodGet && r.URL.Path == "/v3/locales":
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{"locales": s.locales, "count": len(s.locales)})
+	case r.Method == http.MethodGet:
+		key := r.URL.Path[strings.LastIndex(r.URL.Path, "/")+1:]
+		fmt.Fprintf(w, `{%q: [], "count": 0}`, key)
+	case r.Method == http.MethodPost && r.URL.Path == "/v3/locales/":
+		s.locales = append(s.locales, body["locale"])
+		_, _ = w.Write([]byte(`{}`))
+	case r.Method == http.MethodPost && strings.HasSuffix(r.URL.Path, "/entries"):
+		s.created++
+		fmt.Fprintf(w, `{"entry": {"uid": "new_%d"}}`, s.created)
+	case strings.Contains(r.URL.Path, "/entries/"):
+		_, _ = w.Write([]byte(`{"entry": {}}`))
+	default:
+		_, _ = w.Write([]byte(`{}`))
+	}
+}
+
+func TestImport(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string]interface{}{
+		localesFile: []management.Locale{{Code: "en-us"}, {Code: "nl-nl", FallbackLocale: "en-us"}},
+		"entries/blog/en-us/b1.json": entryFile{UID: "b1", Locale: "en-us", Fields: map[string]interface{}{
+			"title":   "First",
+			"related": []interface{}{map[string]interface{}{"uid": "b2", "_content_type_uid": "blog"}},
+		}},
+		"entries/blog/en-us/b2.json": entryFile{UID: "b2", Locale: "en-us", Fields: map[string]interface{}{
+			"title": "Second",
+		}},
+		"entries/blog/nl-nl/b1.json": entryFile{UID: "b1", Locale: "nl-nl", Fields: map[string]interface{}{
+			"title":   "Eerste",
+			"related": []interface{}{map[string]interface{}{"uid": "b2", "_content_type_uid": "blog"}},
+		}},
+	}
+	for path, v := range files {
+		if err := writeJSON(filepath.Join(dir, path), v); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+
+	handler := &importServer{failures: map[string]bool{
+		`POST /v3/content_types/blog/entries?en-us {"title":"Second"}`: true,
+	}}
+	server := httptest.NewServer(handler)
+	t.Cleanup(server.Close)
+
+	client, err := management.NewClient(management.ClientConfig{BaseURL: server.URL, AuthToken: "token"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	stack, err := client.Stack(&management.StackAuth{ApiKey: "api-key"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	ctx := context.Background()
+	if err := Import(ctx, stack, dir, ImportOptions{}); err == nil {
+		t.Fatal("expected the first import to fail")
+	}
+	if err := Import(ctx, stack, dir, ImportOptions{}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	writes := []string{}
+	for _, request := range handler.requests {
+		if !strings.HasPrefix(request, "GET") {
+			writes = append(writes, request)
+		}
+	}
+	want := []string{
+		`POST /v3/locales/? null`,
+		`POST /v3/content_types/blog/entries?en-us {"related":[],"title":"First"}`,
+		`POST /v3/content_types/blog/entries?en-us {"title":"Second"}`,
+		`PUT /v3/content_types/blog/entries/new_1?en-us {"related":[{"_content_type_uid":"blog","uid":"new_2"}],"title":"First"}`,
+		`PUT /v3/content_types/blog/entries/new_1?nl-nl {"related":[{"_content_type_uid":"blog","uid":"new_2"}],"title":"Eerste"}`,
+	}
+	if !reflect.DeepEqual(writes, want) {
+		t.Errorf("requests = %v, want %v", strings.Join(writes, "\n"), strings.Join(want, "\n"))
+	}
+}
+
+// newTestStack adds a stack to the server and returns a client for it.
+func newTestStack(t *testing.T, server *managementtest.Server, apiKey string) *management.StackInstance {
+	t.Helper()
+	server.AddStack(managementtest.StackOptions{APIKey: apiKey, ManagementToken: "token"})
+
+	client, err := management.NewClient(management.ClientConfig{BaseURL: server.URL})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	stack, err := client.Stack(&management.StackAuth{ApiKey: apiKey, ManagementToken: "token"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	return stack
+}
+
+// seedStack fills the stack with global fields and content types which depend
+// on each other in reverse alphabetical order, nested asset folders and
+// localized entries with tags and references.
+func seedStack(t *testing.T, ctx context.Context, stack *management.StackInstance) {
+	t.Helper()
+	must := func(_ interface{}, err error) {
+		t.Helper()
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+
+	must(stack.LocaleCreate(ctx, management.LocaleInput{Code: "nl-nl", FallbackLocale: "en-us"}))
+
+	for _, item := range []struct {
+		uid    string
+		schema schema.Schema
+	}{
+		{"seo", schema.Fields(schema.Text("meta_title"))},
+		{"meta", schema.Fields(schema.GlobalField("seo", "seo"))},
+	} {
+		input, err := management.NewGlobalFieldInput(item.uid, item.uid, item.schema, schema.ValidationOptions{})
+		must(nil, err)
+		must(stack.GlobalFieldCreate(ctx, input))
+	}
+
+	person, err := management.NewContentTypeInput("person", "Person", schema.Fields(
+		schema.Text("title").Mandatory(),
+	), schema.ValidationOptions{})
+	must(nil, err)
+	person.Options = &management.ContentTypeOptions{Title: "title", Singleton: true}
+	must(stack.ContentTypeCreate(ctx, person))
+
+	blog, err := management.NewContentTypeInput("blog", "Blog", schema.Fields(
+		schema.Text("title").Mandatory(),
+		schema.Reference("author", "person"),
+		schema.Image("image"),
+		schema.GlobalField("meta", "meta"),
+	), schema.ValidationOptions{})
+	must(nil, err)
+	blog.Options = &management.ContentTypeOptions{Title: "title", IsPage: true, UrlPattern: "/:title"}
+	must(stack.ContentTypeCreate(ctx, blog))
+
+	images, err := stack.AssetFolderCreate(ctx, management.AssetFolderInput{Name: "images"})
+	must(nil, err)
+	logos, err := stack.AssetFolderCreate(ctx, management.AssetFolderInput{Name: "logos", ParentUID: images.UID})
+	must(nil, err)
+	logo, err := stack.AssetCreate(ctx, management.AssetInput{
+		Filename:  "logo.svg",
+		Content:   strings.NewReader("<svg/>"),
+		Title:     "Logo",
+		Tags:      []string{"brand"},
+		ParentUID: logos.UID,
+	})
+	must(nil, err)
+
+	author, err := stack.EntryCreate(ctx, &management.EntryInput{
+		ContentTypeUID: "person",
+		Locale:         "en-us",
+		Fields:         map[string]interface{}{"title": "Jane"},
+	})
+	must(nil, err)
+	post, err := stack.EntryCreate(ctx, &management.EntryInput{
+		ContentTypeUID: "blog",
+		Locale:         "en-us",
+		Tags:           []string{"featured"},
+		Fields: map[string]interface{}{
+			"title":  "Hello",
+			"author": []interface{}{map[string]interface{}{"uid": author.UID, "_content_type_uid": "person"}},
+			"image":  logo.UID,
+			"meta":   map[string]interface{}{"seo": map[string]interface{}{"meta_title": "Hello"}},
+		},
+	})
+	must(nil, err)
+	must(stack.EntryLocalize(ctx, &management.EntryContextInput{ContentTypeUID: "blog", UID: post.UID, Locale: "nl-nl"}, map[string]interface{}{
+		"title":  "Hallo",
+		"author": []interface{}{map[string]interface{}{"uid": author.UID, "_content_type_uid": "person"}},
+		"tags":   []string{"uitgelicht"},
+	}))
+}
+
+func TestExport(t *testing.T) {
+	server := managementtest.NewServer()
+	t.Cleanup(server.Close)
+	stack := newTestStack(t, server, "source")
+	ctx := context.Background()
+	seedStack(t, ctx, stack)
+
+	dir := t.TempDir()
+	if err := Export(ctx, stack, dir, ExportOptions{}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	data, err := readExport(dir)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	uids := func(n int, uid func(i int) string) []string {
+		result := []string{}
+		for i := 0; i < n; i++ {
+			result = append(result, uid(i))
+		}
+		return result
+	}
+	if got := uids(len(data.globalFields), func(i int) string { return data.globalFields[i].UID }); !reflect.DeepEqual(got, []string{"meta", "seo"}) {
+		t.Errorf("global fields = %v", got)
+	}
+	if got := uids(len(data.contentTypes), func(i int) string { return data.contentTypes[i].UID }); !reflect.DeepEqual(got, []string{"blog", "person"}) {
+		t.Errorf("content types = %v", got)
+	}
+	if options := data.contentTypes[0].Options; options == nil || !options.IsPage || options.UrlPattern != "/:title" {
+		t.Errorf("options of blog = %+v", options)
+	}
+
+	folders := map[string]management.Asset{}
+	var logo *management.Asset
+	for i, asset := range data.assets {
+		if asset.IsDir {
+			folders[asset.Name] = asset
+		} else {
+			logo = &data.assets[i]
+		}
+	}
+	if len(folders) != 2 || folders["logos"].ParentUID != folders["images"].UID {
+		t.Fatalf("folders = %+v", folders)
+	}
+	if logo == nil || logo.ParentUID != folders["logos"].UID || logo.Title != "Logo" {
+		t.Fatalf("asset = %+v", logo)
+	}
+	content, err := os.ReadFile(filepath.Join(dir, assetsDir, logo.UID, "logo.svg"))
+	if err != nil || string(content) != "<svg/>" {
+		t.Errorf("content = %q, %v", content, err)
+	}
+
+	tags := map[string][]string{}
+	for _, entry := range data.entries {
+		tags[entry.contentType+"/"+entry.Locale] = entry.Tags
+	}
+	want := map[string][]string{"blog/en-us": {"featured"}, "blog/nl-nl": {"uitgelicht"}, "person/en-us": nil}
+	if !reflect.DeepEqual(tags, want) {
+		t.Errorf("tags = %v, want %v", tags, want)
+	}
+
+	// An export is never written over an existing export
+	if err := Export(ctx, stack, dir, ExportOptions{}); err == nil {
+		t.Error("expected an error exporting into a non-empty directory")
+	}
+}
+
+func TestExportImport(t *testing.T) {
+	server := managementtest.NewServer()
+	t.Cleanup(server.Close)
+	source := newTestStack(t, server, "source")
+	target := newTestStack(t, server, "target")
+	seedStack(t, context.Background(), source)
+
+	dir := t.TempDir()
+	if err := Export(context.Background(), source, dir, ExportOptions{}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// Stop the import after creating the first entry
+	ctx, cancel := context.WithCancel(context.Background())
+	stateFile := filepath.Join(t.TempDir(), "state.json")
+	created := 0
+	structure := []string{}
+	err := Import(ctx, target, dir, ImportOptions{
+		StateFile: stateFile,
+		Progress: func(message string) {
+			if strings.HasPrefix(message, "create global_field") || strings.HasPrefix(message, "create content_type") {
+				structure = append(structure, message)
+			}
+			if strings.HasPrefix(message, "create entry") {
+				created++
+				if created == 2 {
+					cancel()
+				}
+			}
+		},
+	})
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("error = %v, want context.Canceled", err)
+	}
+
+	state, err := loadState(stateFile)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(state.Assets) != 3 || len(state.Entries) != 1 || len(state.Resolved) != 0 {
+		t.Fatalf("state after partial import = %+v", state)
+	}
+
+	// The global fields and content types are created in dependency order
+	want := []string{
+		"create global_field seo",
+		"create global_field meta",
+		"create content_type person",
+		"create content_type blog",
+	}
+	if !reflect.DeepEqual(structure, want) {
+		t.Errorf("structure = %v, want %v", structure, want)
+	}
+
+	if err := Import(context.Background(), target, dir, ImportOptions{StateFile: stateFile}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	state, err = loadState(stateFile)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	result := t.TempDir()
+	if err := Export(context.Background(), target, result, ExportOptions{}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	compareExports(t, dir, result, state)
+}
+
+// compareExports compares the export of the imported stack with the original
+// export, using the uid mapping of the import state.
+func compareExports(t *testing.T, original string, imported string, state *importState) {
+	t.Helper()
+	want, err := readExport(original)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	got, err := readExport(imported)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(got.locales) != len(want.locales) {
+		t.Errorf("locales = %+v, want %+v", got.locales, want.locales)
+	}
+	for i, item := range want.globalFields {
+		if got.globalFields[i].UID != item.UID || !jsonEqual(got.globalFields[i].Schema, item.Schema) {
+			t.Errorf("global field %s = %+v, want %+v", item.UID, got.globalFields[i], item)
+		}
+	}
+	for i, item := range want.contentTypes {
+		if got.contentTypes[i].UID != item.UID || !jsonEqual(got.contentTypes[i].Schema, item.Schema) ||
+			!reflect.DeepEqual(got.contentTypes[i].Options, item.Options) {
+			t.Errorf("content type %s = %+v, want %+v", item.UID, got.contentTypes[i], item)
+		}
+	}
+
+	assets := map[string]management.Asset{}
+	for _, asset := range got.assets {
+		assets[asset.UID] = asset
+	}
+	for _, item := range want.assets {
+		asset, ok := assets[state.Assets[item.UID]]
+		if !ok {
+			t.Errorf("asset %s is not imported", item.UID)
+			continue
+		}
+		if asset.IsDir != item.IsDir || asset.Name != item.Name || asset.Title != item.Title ||
+			asset.ParentUID != state.Assets[item.ParentUID] || !reflect.DeepEqual(asset.Tags, item.Tags) {
+			t.Errorf("asset %s = %+v, want %+v", item.UID, asset, item)
+		}
+		if item.IsDir {
+			continue
+		}
+		wantContent, _ := os.ReadFile(filepath.Join(original, assetsDir, item.UID, assetFilename(&item)))
+		gotContent, _ := os.ReadFile(filepath.Join(imported, assetsDir, asset.UID, assetFilename(&asset)))
+		if string(gotContent) != string(wantContent) {
+			t.Errorf("content of asset %s = %q, want %q", item.UID, gotContent, wantContent)
+		}
+	}
+
+	uids := &uidMap{assets: state.Assets, entries: state.Entries}
+	entries := map[string]entryFile{}
+	for _, entry := range got.entries {
+		entries[entry.UID+"/"+entry.Locale] = entry
+	}
+	for _, item := range want.entries {
+		entry, ok := entries[state.Entries[item.UID]+"/"+item.Locale]
+		if !ok {
+			t.Errorf("entry %s in %s is not imported", item.UID, item.Locale)
+			continue
+		}
+		if !reflect.DeepEqual(entry.Tags, item.Tags) || !reflect.DeepEqual(entry.Fields, uids.remap(item.Fields, false)) {
+			t.Errorf("entry %s in %s = %+v, want %+v", item.UID, item.Locale, entry, item)
+		}
+	}
+	if len(got.entries) != len(want.entries) {
+		t.Errorf("imported %d entries, want %d", len(got.entries), len(want.entries))
+	}
+}
+
+func jsonEqual(a []byte, b []byte) bool {
+	var va, vb interface{}
+	if json.Unmarshal(a, &va) != nil || json.Unmarshal(b, &vb) != nil {
+		return false
+	}
+	return reflect.DeepEqual(va, vb)
+}
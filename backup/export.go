@@ -0,0 +1,206 @@
+package backup
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/labd/contentstack-go-sdk/management"
+)
+
+// ExportOptions configures what is exported.
+type ExportOptions struct {
+	// Locales limits the exported entries to the given locales. The entries
+	// of all locales are exported when empty.
+	Locales []string
+
+	// SkipAssets doesn't export the assets.
+	SkipAssets bool
+
+	// SkipEntries doesn't export the entries.
+	SkipEntries bool
+}
+
+// Export writes the content types, global fields, locales, environments,
+// webhooks, entries and assets of the stack to dir, which must be empty or
+// not exist yet.
+//
+// Webhooks are exported without the passwords of their destinations, since
+// these are not returned by the API.
+func Export(ctx context.Context, si *management.StackInstance, dir string, opts ExportOptions) error {
+	existing, err := os.ReadDir(dir)
+	if err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	if len(existing) > 0 {
+		return fmt.Errorf("directory %s is not empty", dir)
+	}
+
+	locales, err := si.LocaleFetchAll(ctx)
+	if err != nil {
+		return fmt.Errorf("fetching locales: %w", err)
+	}
+	sort.Slice(locales, func(i, j int) bool { return locales[i].Code < locales[j].Code })
+	if err := writeJSON(filepath.Join(dir, localesFile), locales); err != nil {
+		return err
+	}
+
+	environments, err := si.EnvironmentFetchAll(ctx, "")
+	if err != nil {
+		return fmt.Errorf("fetching environments: %w", err)
+	}
+	sort.Slice(environments, func(i, j int) bool { return environments[i].Name < environments[j].Name })
+	if err := writeJSON(filepath.Join(dir, environmentsFile), environments); err != nil {
+		return err
+	}
+
+	webhooks, err := si.WebHookFetchAll(ctx)
+	if err != nil {
+		return fmt.Errorf("fetching webhooks: %w", err)
+	}
+	sort.Slice(webhooks, func(i, j int) bool { return webhooks[i].Name < webhooks[j].Name })
+	if err := writeJSON(filepath.Join(dir, webhooksFile), webhooks); err != nil {
+		return err
+	}
+
+	globalFields, err := si.GlobalFieldFetchAll(ctx)
+	if err != nil {
+		return fmt.Errorf("fetching global fields: %w", err)
+	}
+	for _, item := range globalFields {
+		if err := writeJSON(filepath.Join(dir, globalFieldsDir, item.UID+".json"), item); err != nil {
+			return err
+		}
+	}
+
+	contentTypes, err := si.ContentTypeFetchAll(ctx)
+	if err != nil {
+		return fmt.Errorf("fetching content types: %w", err)
+	}
+	for _, item := range contentTypes {
+		if err := writeJSON(filepath.Join(dir, contentTypesDir, item.UID+".json"), item); err != nil {
+			return err
+		}
+	}
+
+	if !opts.SkipAssets {
+		if err := exportAssets(ctx, si, dir); err != nil {
+			return err
+		}
+	}
+
+	if !opts.SkipEntries {
+		codes := opts.Locales
+		if len(codes) == 0 {
+			for _, locale := range locales {
+				codes = append(codes, locale.Code)
+			}
+		}
+		for _, contentType := range contentTypes {
+			for _, code := range codes {
+				if err := exportEntries(ctx, si, dir, contentType.UID, code); err != nil {
+					return err
+				}
+			}
+		}
+	}
+
+	return nil
+}
+
+func exportAssets(ctx context.Context, si *management.StackInstance, dir string) error {
+	folders, err := si.AssetFolderFetchAll(ctx)
+	if err != nil {
+		return fmt.Errorf("fetching asset folders: %w", err)
+	}
+	for i := range folders {
+		folder := &folders[i]
+		if err := writeJSON(filepath.Join(dir, assetsDir, folder.UID, assetFile), folder); err != nil {
+			return err
+		}
+	}
+
+	assets, err := si.AssetFetchAll(ctx, "")
+	if err != nil {
+		return fmt.Errorf("fetching assets: %w", err)
+	}
+	for i := range assets {
+		asset := &assets[i]
+		// The folders are exported above
+		if asset.IsDir {
+			continue
+		}
+
+		path := filepath.Join(dir, assetsDir, asset.UID)
+		if err := writeJSON(filepath.Join(path, assetFile), asset); err != nil {
+			return err
+		}
+		if err := downloadAsset(ctx, si, asset, filepath.Join(path, assetFilename(asset))); err != nil {
+			return fmt.Errorf("downloading asset %s: %w", asset.UID, err)
+		}
+	}
+	return nil
+}
+
+func downloadAsset(ctx context.Context, si *management.StackInstance, asset *management.Asset, path string) error {
+	content, err := si.AssetDownload(ctx, asset)
+	if err != nil {
+		return err
+	}
+	defer content.Close()
+
+	file, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(file, content); err != nil {
+		file.Close()
+		return err
+	}
+	return file.Close()
+}
+
+// assetFilename returns the name of the file containing the content of the
+// asset. It never equals the name of the metadata file.
+func assetFilename(asset *management.Asset) string {
+	name := filepath.Base(asset.Filename)
+	if name == "." || name == string(filepath.Separator) || name == assetFile {
+		name = "content"
+	}
+	return name
+}
+
+func exportEntries(ctx context.Context, si *management.StackInstance, dir string, contentType string, locale string) error {
+	query := management.NewEntryQuery().Locale(locale)
+	it := si.EntryFindIterator(contentType, query, management.ListOptions{})
+	for it.Next(ctx) {
+		entry := it.Value()
+
+		// Only export the entries which are localized in the locale
+		if entry.Locale != "" && entry.Locale != locale {
+			continue
+		}
+
+		fields := map[string]interface{}{}
+		for key, value := range entry.Fields {
+			// Skip the system fields
+			if strings.HasPrefix(key, "_") {
+				continue
+			}
+			fields[key] = value
+		}
+
+		path := filepath.Join(dir, entriesDir, contentType, locale, entry.UID+".json")
+		if err := writeJSON(path, entryFile{UID: entry.UID, Locale: locale, Tags: entry.Tags, Fields: fields}); err != nil {
+			return err
+		}
+	}
+	if err := it.Err(); err != nil {
+		return fmt.Errorf("fetching entries of %s in %s: %w", contentType, locale, err)
+	}
+	return nil
+}
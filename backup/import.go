@@ -0,0 +1,373 @@
+package backup
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/labd/contentstack-go-sdk/management"
+	"github.com/labd/contentstack-go-sdk/stackplan"
+)
+
+// ImportOptions configures the import.
+type ImportOptions struct {
+	// StateFile is the file in which the progress of the import is kept.
+	// Defaults to import-state.json in the export directory.
+	StateFile string
+
+	// Progress is called with a description of every step of the import.
+	Progress func(message string)
+}
+
+// importState is the progress of an import, used to resume a failed import.
+type importState struct {
+	// Assets and Entries map the exported uids to the new uids.
+	Assets  map[string]string `json:"assets"`
+	Entries map[string]string `json:"entries"`
+
+	// Resolved contains the entries which are updated with all references.
+	Resolved map[string]bool `json:"resolved"`
+
+	// Localized contains the imported localized versions of entries, keyed
+	// by uid and locale.
+	Localized map[string]bool `json:"localized"`
+}
+
+func loadState(path string) (*importState, error) {
+	state := &importState{}
+	if err := readJSON(path, state); err != nil && !os.IsNotExist(err) {
+		return nil, err
+	}
+	if state.Assets == nil {
+		state.Assets = map[string]string{}
+	}
+	if state.Entries == nil {
+		state.Entries = map[string]string{}
+	}
+	if state.Resolved == nil {
+		state.Resolved = map[string]bool{}
+	}
+	if state.Localized == nil {
+		state.Localized = map[string]bool{}
+	}
+	return state, nil
+}
+
+type importer struct {
+	si    *management.StackInstance
+	dir   string
+	opts  ImportOptions
+	state *importState
+	data  *export
+	uids  *uidMap
+}
+
+// Import recreates the export in dir in the stack. Locales, environments,
+// global fields and content types are created or updated to match the export.
+// Assets and entries are always created, with references rewritten to the
+// new uids. Webhooks are created last, so they aren't triggered by the
+// import.
+//
+// When the import fails it can be resumed by calling Import again with the
+// same state file. The assets and entries which were already imported are
+// skipped.
+func Import(ctx context.Context, si *management.StackInstance, dir string, opts ImportOptions) error {
+	if opts.StateFile == "" {
+		opts.StateFile = filepath.Join(dir, "import-state.json")
+	}
+
+	data, err := readExport(dir)
+	if err != nil {
+		return err
+	}
+	state, err := loadState(opts.StateFile)
+	if err != nil {
+		return err
+	}
+
+	imp := &importer{
+		si:    si,
+		dir:   dir,
+		opts:  opts,
+		state: state,
+		data:  data,
+		uids: &uidMap{
+			assets:   state.Assets,
+			entries:  state.Entries,
+			exported: map[string]bool{},
+		},
+	}
+	for _, entry := range data.entries {
+		imp.uids.exported[entry.UID] = true
+	}
+
+	if err := imp.structure(ctx); err != nil {
+		return err
+	}
+	if err := imp.assets(ctx); err != nil {
+		return err
+	}
+	if err := imp.entries(ctx); err != nil {
+		return err
+	}
+	return imp.webhooks(ctx)
+}
+
+func (imp *importer) progress(format string, args ...interface{}) {
+	if imp.opts.Progress != nil {
+		imp.opts.Progress(fmt.Sprintf(format, args...))
+	}
+}
+
+func (imp *importer) save() error {
+	tmp := imp.opts.StateFile + ".tmp"
+	if err := writeJSON(tmp, imp.state); err != nil {
+		return err
+	}
+	return os.Rename(tmp, imp.opts.StateFile)
+}
+
+func (imp *importer) apply(ctx context.Context, desired stackplan.Stack) error {
+	plan, err := stackplan.NewPlan(ctx, imp.si, desired, stackplan.Options{})
+	if err != nil {
+		return err
+	}
+	return plan.Apply(ctx, imp.si, stackplan.ApplyOptions{
+		Progress: func(step stackplan.Step) {
+			imp.progress("%s", step)
+		},
+	})
+}
+
+// structure creates the locales, environments, global fields and content
+// types.
+func (imp *importer) structure(ctx context.Context) error {
+	desired := stackplan.Stack{}
+	for _, item := range imp.data.locales {
+		desired.Locales = append(desired.Locales, management.LocaleInput{
+			Name:           item.Name,
+			Code:           item.Code,
+			FallbackLocale: item.FallbackLocale,
+		})
+	}
+	for _, item := range imp.data.environments {
+		desired.Environments = append(desired.Environments, management.EnvironmentInput{
+			Name: item.Name,
+			URLs: item.URLs,
+		})
+	}
+	for _, item := range imp.data.globalFields {
+		desired.GlobalFields = append(desired.GlobalFields, management.GlobalFieldInput{
+			UID:               management.StringRef(item.UID),
+			Title:             management.StringRef(item.Title),
+			Description:       management.StringRef(item.Description),
+			MaintainRevisions: item.MaintainRevisions,
+			Schema:            item.Schema,
+		})
+	}
+	for _, item := range imp.data.contentTypes {
+		desired.ContentTypes = append(desired.ContentTypes, management.ContentTypeInput{
+			UID:         management.StringRef(item.UID),
+			Title:       management.StringRef(item.Title),
+			Description: management.StringRef(item.Description),
+			Schema:      item.Schema,
+			Options:     item.Options,
+		})
+	}
+	return imp.apply(ctx, desired)
+}
+
+func (imp *importer) webhooks(ctx context.Context) error {
+	desired := stackplan.Stack{}
+	for _, item := range imp.data.webhooks {
+		desired.WebHooks = append(desired.WebHooks, management.WebHookInput{
+			Name:           item.Name,
+			Branches:       item.Branches,
+			Channels:       item.Channels,
+			Destinations:   item.Destinations,
+			RetryPolicy:    item.RetryPolicy,
+			Disabled:       item.Disabled,
+			ConcisePayload: item.ConcisePayload,
+		})
+	}
+	return imp.apply(ctx, desired)
+}
+
+// assets uploads the assets. The folders are created first, parents before
+// their subfolders.
+func (imp *importer) assets(ctx context.Context) error {
+	parents := map[string]string{}
+	for _, asset := range imp.data.assets {
+		parents[asset.UID] = asset.ParentUID
+	}
+	assets := append([]management.Asset{}, imp.data.assets...)
+	sort.SliceStable(assets, func(i, j int) bool {
+		if assets[i].IsDir != assets[j].IsDir {
+			return assets[i].IsDir
+		}
+		return depth(assets[i].UID, parents) < depth(assets[j].UID, parents)
+	})
+
+	for i := range assets {
+		asset := &assets[i]
+		if _, ok := imp.state.Assets[asset.UID]; ok {
+			continue
+		}
+		imp.progress("create asset %s", asset.UID)
+
+		created, err := imp.createAsset(ctx, asset)
+		if err != nil {
+			return fmt.Errorf("unable to create asset %s: %w", asset.UID, err)
+		}
+		imp.state.Assets[asset.UID] = created.UID
+		if err := imp.save(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (imp *importer) createAsset(ctx context.Context, asset *management.Asset) (*management.Asset, error) {
+	parent := imp.state.Assets[asset.ParentUID]
+	if asset.IsDir {
+		return imp.si.AssetFolderCreate(ctx, management.AssetFolderInput{
+			Name:      asset.Name,
+			ParentUID: parent,
+		})
+	}
+
+	file, err := os.Open(filepath.Join(imp.dir, assetsDir, asset.UID, assetFilename(asset)))
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	return imp.si.AssetCreate(ctx, management.AssetInput{
+		Filename:    asset.Filename,
+		Content:     file,
+		Title:       asset.Title,
+		Description: asset.Description,
+		Tags:        asset.Tags,
+		ParentUID:   parent,
+	})
+}
+
+// entries imports the entries in three passes. First every entry is created
+// in the first locale it is exported in, without the references to entries
+// which don't exist yet. Then the entries with references are updated, and
+// finally the other locales are localized.
+func (imp *importer) entries(ctx context.Context) error {
+	parents := map[string]string{}
+	for _, locale := range imp.data.locales {
+		parents[locale.Code] = locale.FallbackLocale
+	}
+	entries := append([]entryFile{}, imp.data.entries...)
+	sort.SliceStable(entries, func(i, j int) bool {
+		return depth(entries[i].Locale, parents) < depth(entries[j].Locale, parents)
+	})
+
+	seen := map[string]bool{}
+	origins := []entryFile{}
+	localized := []entryFile{}
+	for _, entry := range entries {
+		if seen[entry.UID] {
+			localized = append(localized, entry)
+		} else {
+			seen[entry.UID] = true
+			origins = append(origins, entry)
+		}
+	}
+
+	for _, entry := range origins {
+		if _, ok := imp.state.Entries[entry.UID]; ok {
+			continue
+		}
+		imp.progress("create entry %s/%s", entry.contentType, entry.UID)
+
+		created, err := imp.si.EntryCreate(ctx, &management.EntryInput{
+			ContentTypeUID: entry.contentType,
+			Locale:         entry.Locale,
+			Fields:         imp.remapFields(entry.Fields, true),
+			Tags:           entry.Tags,
+		})
+		if err != nil {
+			return fmt.Errorf("unable to create entry %s: %w", entry.UID, err)
+		}
+		imp.state.Entries[entry.UID] = created.UID
+		if err := imp.save(); err != nil {
+			return err
+		}
+	}
+
+	for _, entry := range origins {
+		if imp.state.Resolved[entry.UID] {
+			continue
+		}
+
+		if imp.uids.references(entry.Fields) {
+			imp.progress("update references of entry %s/%s", entry.contentType, entry.UID)
+
+			_, err := imp.si.EntryUpdate(ctx, imp.state.Entries[entry.UID], &management.EntryInput{
+				ContentTypeUID: entry.contentType,
+				Locale:         entry.Locale,
+				Fields:         imp.remapFields(entry.Fields, false),
+				Tags:           entry.Tags,
+			})
+			if err != nil {
+				return fmt.Errorf("unable to update entry %s: %w", entry.UID, err)
+			}
+		}
+		imp.state.Resolved[entry.UID] = true
+		if err := imp.save(); err != nil {
+			return err
+		}
+	}
+
+	for _, entry := range localized {
+		key := entry.UID + "/" + entry.Locale
+		if imp.state.Localized[key] {
+			continue
+		}
+		imp.progress("localize entry %s/%s in %s", entry.contentType, entry.UID, entry.Locale)
+
+		fields := imp.remapFields(entry.Fields, false)
+		if entry.Tags != nil {
+			fields["tags"] = entry.Tags
+		}
+		_, err := imp.si.EntryLocalize(ctx, &management.EntryContextInput{
+			ContentTypeUID: entry.contentType,
+			UID:            imp.state.Entries[entry.UID],
+			Locale:         entry.Locale,
+		}, fields)
+		if err != nil {
+			return fmt.Errorf("unable to localize entry %s in %s: %w", entry.UID, entry.Locale, err)
+		}
+		imp.state.Localized[key] = true
+		if err := imp.save(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// remapFields returns a copy of the fields with the exported uids replaced by
+// the new uids.
+func (imp *importer) remapFields(fields map[string]interface{}, strip bool) map[string]interface{} {
+	result, ok := imp.uids.remap(fields, strip).(map[string]interface{})
+	if !ok {
+		return map[string]interface{}{}
+	}
+	return result
+}
+
+// depth returns the number of parents of the key, e.g. the number of fallback
+// locales of a locale or the number of parent folders of an asset.
+func depth(key string, parents map[string]string) int {
+	result := 0
+	for parent := parents[key]; parent != "" && result < len(parents); parent = parents[parent] {
+		result++
+	}
+	return result
+}
@@ -0,0 +1,84 @@
+package backup
+
+// uidMap maps the uids of the exported entries and assets to the uids in the
+// stack the export is imported into.
+type uidMap struct {
+	assets  map[string]string
+	entries map[string]string
+
+	// exported contains the uids of all exported entries.
+	exported map[string]bool
+}
+
+// remap returns a copy of the value with the exported uids replaced by the new
+// uids. When strip is set, references to entries which are not imported yet
+// are left out.
+func (m *uidMap) remap(value interface{}, strip bool) interface{} {
+	result, _ := m.walk(value, strip)
+	return result
+}
+
+func (m *uidMap) walk(value interface{}, strip bool) (interface{}, bool) {
+	switch v := value.(type) {
+	case string:
+		if uid, ok := m.assets[v]; ok {
+			return uid, true
+		}
+		if uid, ok := m.entries[v]; ok {
+			return uid, true
+		}
+		return v, true
+
+	case []interface{}:
+		result := make([]interface{}, 0, len(v))
+		for _, item := range v {
+			if item, keep := m.walk(item, strip); keep {
+				result = append(result, item)
+			}
+		}
+		return result, true
+
+	case map[string]interface{}:
+		if uid, ok := v["uid"].(string); ok && strip && isReference(v) && m.exported[uid] {
+			if _, ok := m.entries[uid]; !ok {
+				return nil, false
+			}
+		}
+
+		result := make(map[string]interface{}, len(v))
+		for key, item := range v {
+			if item, keep := m.walk(item, strip); keep {
+				result[key] = item
+			}
+		}
+		return result, true
+	}
+	return value, true
+}
+
+// references returns whether the value refers to one of the exported entries.
+func (m *uidMap) references(value interface{}) bool {
+	switch v := value.(type) {
+	case string:
+		return m.exported[v]
+	case []interface{}:
+		for _, item := range v {
+			if m.references(item) {
+				return true
+			}
+		}
+	case map[string]interface{}:
+		for _, item := range v {
+			if m.references(item) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// isReference returns whether the object is the value of a reference field.
+func isReference(v map[string]interface{}) bool {
+	_, ok := v["_content_type_uid"]
+	return ok
+}
@@ -0,0 +1,128 @@
+// Command contentstack-codegen generates Go types for the content types and
+// global fields of a stack.
+//
+//	contentstack-codegen -api-key blt123 -management-token cs123 -package content -output content.go
+//
+// The configuration can also be loaded from a profile file and the
+// CONTENTSTACK_* environment variables, see management.LoadProfile. This makes
+// the command suitable for go generate:
+//
+//	//go:generate go run github.com/labd/contentstack-go-sdk/cmd/contentstack-codegen -package content -output content.go
+//
+// The flags take precedence over the profile.
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/labd/contentstack-go-sdk/codegen"
+	"github.com/labd/contentstack-go-sdk/management"
+)
+
+func main() {
+	if err := run(); err != nil {
+		fmt.Fprintf(os.Stderr, "contentstack-codegen: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+func run() error {
+	profileName := flag.String("profile", "", "profile to load the configuration from")
+	region := flag.String("region", "", "region of the stack, e.g. eu or azure-na")
+	baseURL := flag.String("base-url", "", "base url of the management API, overrides the region")
+	apiKey := flag.String("api-key", "", "api key of the stack")
+	token := flag.String("management-token", "", "management token of the stack")
+	branch := flag.String("branch", "", "branch of the stack")
+	pkg := flag.String("package", "content", "package name of the generated file")
+	output := flag.String("output", "", "file to write the generated code to, defaults to stdout")
+	flag.Parse()
+
+	profile, err := management.LoadProfile(*profileName)
+	if err != nil {
+		return err
+	}
+	if *region != "" {
+		if profile.Region, err = management.ParseRegion(*region); err != nil {
+			return err
+		}
+	}
+	if *baseURL != "" {
+		profile.BaseURL = *baseURL
+	}
+	if *apiKey != "" {
+		profile.ApiKey = *apiKey
+	}
+	if *token != "" {
+		profile.ManagementToken = *token
+	}
+	if *branch != "" {
+		profile.Branch = *branch
+	}
+	if profile.Region == "" {
+		profile.Region = management.RegionNA
+	}
+
+	if profile.ApiKey == "" || profile.ManagementToken == "" {
+		return fmt.Errorf("the api key and management token are required")
+	}
+
+	cfg := profile.ClientConfig()
+	cfg.RetryPolicy = &management.DefaultRetryPolicy
+	client, err := management.NewClient(cfg)
+	if err != nil {
+		return err
+	}
+	stack, err := client.Stack(profile.StackAuth())
+	if err != nil {
+		return err
+	}
+
+	ctx := context.Background()
+	contentTypes, err := stack.ContentTypeFetchAll(ctx)
+	if err != nil {
+		return fmt.Errorf("fetching content types: %w", err)
+	}
+	globalFields, err := stack.GlobalFieldFetchAll(ctx)
+	if err != nil {
+		return fmt.Errorf("fetching global fields: %w", err)
+	}
+
+	contentTypeModels := []codegen.Model{}
+	for i := range contentTypes {
+		s, err := contentTypes[i].ParseSchema()
+		if err != nil {
+			return fmt.Errorf("content type %s: %w", contentTypes[i].UID, err)
+		}
+		contentTypeModels = append(contentTypeModels, codegen.Model{
+			UID:         contentTypes[i].UID,
+			Description: contentTypes[i].Description,
+			Schema:      s,
+		})
+	}
+	globalFieldModels := []codegen.Model{}
+	for i := range globalFields {
+		s, err := globalFields[i].ParseSchema()
+		if err != nil {
+			return fmt.Errorf("global field %s: %w", globalFields[i].UID, err)
+		}
+		globalFieldModels = append(globalFieldModels, codegen.Model{
+			UID:         globalFields[i].UID,
+			Description: globalFields[i].Description,
+			Schema:      s,
+		})
+	}
+
+	source, err := codegen.Generate(contentTypeModels, globalFieldModels, codegen.Options{Package: *pkg})
+	if err != nil {
+		return err
+	}
+
+	if *output == "" {
+		_, err = os.Stdout.Write(source)
+		return err
+	}
+	return os.WriteFile(*output, source, 0o644)
+}
@@ -0,0 +1,360 @@
+// Package codegen generates Go types for the content types and global fields
+// of a stack, so entries can be decoded into typed structs instead of
+// map[string]interface{}.
+//
+// Every content type and global field becomes a struct. Reference fields use
+// the generic Ref type, modular blocks become a slice of an interface with a
+// struct per block, and JSON rich text fields use RTEDocument. The generated
+// file is self contained and only depends on the standard library.
+package codegen
+
+import (
+	"bytes"
+	"fmt"
+	"go/format"
+	"sort"
+	"strings"
+	"unicode"
+
+	"github.com/labd/contentstack-go-sdk/schema"
+)
+
+// Model is a content type or global field to generate a struct for.
+type Model struct {
+	UID         string
+	Description string
+	Schema      schema.Schema
+}
+
+// Options configures the generated code.
+type Options struct {
+	// Package is the name of the package of the generated file. Defaults to
+	// "content".
+	Package string
+}
+
+// Generate returns the formatted source of the types for the content types
+// and global fields.
+func Generate(contentTypes []Model, globalFields []Model, opts Options) ([]byte, error) {
+	if opts.Package == "" {
+		opts.Package = "content"
+	}
+
+	g := &generator{
+		names:        map[string]bool{},
+		contentTypes: map[string]string{},
+		globalFields: map[string]string{},
+	}
+	for _, name := range supportTypes {
+		g.names[name] = true
+	}
+
+	contentTypes = sortedModels(contentTypes)
+	globalFields = sortedModels(globalFields)
+	for _, model := range globalFields {
+		g.globalFields[model.UID] = g.typeName(identifier(model.UID), "GlobalField")
+	}
+	for _, model := range contentTypes {
+		g.contentTypes[model.UID] = g.typeName(identifier(model.UID), "Entry")
+	}
+
+	g.printf("// Code generated by contentstack-codegen. DO NOT EDIT.\n\n")
+	g.printf("package %s\n\n", opts.Package)
+	g.printf("import (\n\t\"encoding/json\"\n\t\"time\"\n)\n\n")
+
+	for _, model := range contentTypes {
+		g.model(g.contentTypes[model.UID], model, true)
+	}
+	for _, model := range globalFields {
+		g.model(g.globalFields[model.UID], model, false)
+	}
+
+	g.printf("%s", supportCode)
+
+	source, err := format.Source(g.buf.Bytes())
+	if err != nil {
+		return nil, fmt.Errorf("unable to format the generated code: %w", err)
+	}
+	return source, nil
+}
+
+func sortedModels(models []Model) []Model {
+	result := append([]Model{}, models...)
+	sort.Slice(result, func(i, j int) bool { return result[i].UID < result[j].UID })
+	return result
+}
+
+type generator struct {
+	buf bytes.Buffer
+
+	// names contains the type names in use.
+	names map[string]bool
+
+	// contentTypes and globalFields map the uids to the type names.
+	contentTypes map[string]string
+	globalFields map[string]string
+
+	// queue contains the nested types to generate after the current type.
+	queue []func()
+}
+
+func (g *generator) printf(format string, args ...interface{}) {
+	fmt.Fprintf(&g.buf, format, args...)
+}
+
+// typeName returns an unused type name, adding the suffix (and a number) to
+// the name when it is already in use.
+func (g *generator) typeName(name string, suffix string) string {
+	candidate := name
+	for i := 1; g.names[candidate]; i++ {
+		candidate = name + suffix
+		if i > 1 {
+			candidate += fmt.Sprint(i)
+		}
+	}
+	g.names[candidate] = true
+	return candidate
+}
+
+func (g *generator) model(name string, model Model, contentType bool) {
+	if contentType {
+		g.printf("// %s is an entry of the %q content type.\n", name, model.UID)
+	} else {
+		g.printf("// %s contains the fields of the %q global field.\n", name, model.UID)
+	}
+	if model.Description != "" {
+		g.printf("//\n%s", comment(model.Description))
+	}
+
+	if contentType {
+		g.printf("type %s struct {\n", name)
+		g.printf("UID string `json:\"uid\"`\n")
+		g.printf("Locale string `json:\"locale\"`\n")
+		g.fields(name, model.Schema, "UID", "Locale", "ContentTypeUID")
+		g.printf("}\n\n")
+
+		g.printf("// ContentTypeUID returns the uid of the content type.\n")
+		g.printf("func (%s) ContentTypeUID() string {\nreturn %q\n}\n\n", name, model.UID)
+	} else {
+		g.printf("type %s struct {\n", name)
+		g.fields(name, model.Schema)
+		g.printf("}\n\n")
+	}
+
+	for len(g.queue) > 0 {
+		next := g.queue[0]
+		g.queue = g.queue[1:]
+		next()
+	}
+}
+
+// fields writes the fields of the schema. The reserved names are not used
+// for the fields.
+func (g *generator) fields(parent string, s schema.Schema, reserved ...string) {
+	used := map[string]bool{}
+	for _, name := range reserved {
+		used[name] = true
+	}
+
+	for i := range s {
+		field := &s[i]
+
+		name := identifier(field.UID)
+		for used[name] {
+			name += "Field"
+		}
+		used[name] = true
+
+		if field.FieldMetadata != nil && field.FieldMetadata.Description != "" {
+			g.printf("%s", comment(field.FieldMetadata.Description))
+		}
+		tag := fmt.Sprintf("json:%q", field.UID)
+		if field.Mandatory {
+			tag += ` contentstack:"required"`
+		}
+		g.printf("%s %s `%s`\n", name, g.fieldType(parent+name, field), tag)
+	}
+}
+
+// fieldType returns the Go type of the field. Nested types are named after
+// the given name.
+func (g *generator) fieldType(name string, field *schema.Field) string {
+	var result string
+	switch field.Kind() {
+	case schema.KindSingleLineText, schema.KindMultiLineText, schema.KindRichText, schema.KindMarkdown:
+		result = "string"
+	case schema.KindNumber:
+		result = "float64"
+	case schema.KindSelect:
+		result = "string"
+		if field.DataType == schema.TypeNumber {
+			result = "float64"
+		}
+	case schema.KindBoolean:
+		result = "bool"
+	case schema.KindDate:
+		result = "Date"
+	case schema.KindFile:
+		result = "AssetRef"
+	case schema.KindLink:
+		result = "Link"
+	case schema.KindJSONRTE:
+		result = "RTEDocument"
+
+	case schema.KindReference:
+		// Reference fields always contain a list of references
+		if len(field.ReferenceTo) == 1 {
+			if target, ok := g.contentTypes[field.ReferenceTo[0]]; ok {
+				return fmt.Sprintf("[]Ref[%s]", target)
+			}
+		}
+		return "[]EntryRef"
+
+	case schema.KindTaxonomy:
+		return "[]TaxonomyTerm"
+
+	case schema.KindGroup:
+		result = g.nestedStruct(name, field.Schema)
+
+	case schema.KindGlobalField:
+		if len(field.ReferenceTo) > 0 && g.globalFields[field.ReferenceTo[0]] != "" {
+			result = g.globalFields[field.ReferenceTo[0]]
+		} else if len(field.Schema) > 0 {
+			result = g.nestedStruct(name, field.Schema)
+		} else {
+			result = "json.RawMessage"
+		}
+
+	case schema.KindBlocks:
+		return g.blocks(name, field.Blocks)
+
+	case schema.KindExtension:
+		switch field.DataType {
+		case schema.TypeText:
+			result = "string"
+		case schema.TypeNumber:
+			result = "float64"
+		case schema.TypeBoolean:
+			result = "bool"
+		case schema.TypeDate:
+			result = "Date"
+		default:
+			return "json.RawMessage"
+		}
+
+	default:
+		return "json.RawMessage"
+	}
+
+	if field.Multiple {
+		return "[]" + result
+	}
+	return result
+}
+
+func (g *generator) nestedStruct(name string, s schema.Schema) string {
+	name = g.typeName(name, "Group")
+	g.queue = append(g.queue, func() {
+		g.printf("type %s struct {\n", name)
+		g.fields(name, s)
+		g.printf("}\n\n")
+	})
+	return name
+}
+
+// blocks generates a sum type for a modular blocks field: a slice of an
+// interface which is implemented by a struct per block.
+func (g *generator) blocks(name string, blocks []schema.Block) string {
+	name = g.typeName(name, "Blocks")
+	iface := g.typeName(name+"Block", "Type")
+
+	blockTypes := make([]string, len(blocks))
+	for i, block := range blocks {
+		blockTypes[i] = g.typeName(name+identifier(block.UID), "Block")
+	}
+
+	g.queue = append(g.queue, func() {
+		g.printf("// %s contains the blocks of a modular blocks field. Unknown blocks\n// are skipped when decoding.\n", name)
+		g.printf("type %s []%s\n\n", name, iface)
+		g.printf("// %s is implemented by the blocks which can be used in %s.\n", iface, name)
+		g.printf("type %s interface {\nblockUID() string\n}\n\n", iface)
+
+		for i, block := range blocks {
+			typeName := blockTypes[i]
+			if block.ReferenceTo != "" && g.globalFields[block.ReferenceTo] != "" {
+				g.printf("type %s struct {\n%s\n}\n\n", typeName, g.globalFields[block.ReferenceTo])
+			} else {
+				g.printf("type %s struct {\n", typeName)
+				g.fields(typeName, block.Schema)
+				g.printf("}\n\n")
+			}
+			g.printf("func (%s) blockUID() string {\nreturn %q\n}\n\n", typeName, block.UID)
+		}
+
+		g.printf("func (b *%s) UnmarshalJSON(data []byte) error {\n", name)
+		g.printf("items := []map[string]json.RawMessage{}\n")
+		g.printf("if err := json.Unmarshal(data, &items); err != nil {\nreturn err\n}\n\n")
+		g.printf("result := make(%s, 0, len(items))\n", name)
+		if len(blocks) > 0 {
+			g.printf("for _, item := range items {\nfor key, value := range item {\nswitch key {\n")
+			for i, block := range blocks {
+				g.printf("case %q:\n", block.UID)
+				g.printf("block := %s{}\n", blockTypes[i])
+				g.printf("if err := json.Unmarshal(value, &block); err != nil {\nreturn err\n}\n")
+				g.printf("result = append(result, block)\n")
+			}
+			g.printf("}\n}\n}\n")
+		}
+		g.printf("*b = result\nreturn nil\n}\n\n")
+
+		g.printf("func (b %s) MarshalJSON() ([]byte, error) {\n", name)
+		g.printf("items := make([]map[string]%s, len(b))\n", iface)
+		g.printf("for i, block := range b {\nitems[i] = map[string]%s{block.blockUID(): block}\n}\n", iface)
+		g.printf("return json.Marshal(items)\n}\n\n")
+	})
+	return name
+}
+
+// initialisms are written in upper case in identifiers.
+var initialisms = map[string]bool{
+	"api": true, "css": true, "html": true, "http": true, "https": true, "id": true,
+	"json": true, "rte": true, "seo": true, "sku": true, "uid": true, "uri": true,
+	"url": true, "uuid": true,
+}
+
+// identifier converts a uid to an exported Go identifier, e.g. "hero_image_url"
+// to "HeroImageURL".
+func identifier(uid string) string {
+	parts := strings.FieldsFunc(uid, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+
+	b := strings.Builder{}
+	for _, part := range parts {
+		if initialisms[strings.ToLower(part)] {
+			b.WriteString(strings.ToUpper(part))
+			continue
+		}
+		runes := []rune(part)
+		runes[0] = unicode.ToUpper(runes[0])
+		b.WriteString(string(runes))
+	}
+
+	result := b.String()
+	if result == "" {
+		return "Field"
+	}
+	if unicode.IsDigit([]rune(result)[0]) {
+		result = "X" + result
+	}
+	return result
+}
+
+// comment returns the text as a Go comment.
+func comment(text string) string {
+	b := strings.Builder{}
+	for _, line := range strings.Split(strings.TrimSpace(text), "\n") {
+		b.WriteString(strings.TrimRight("// "+strings.TrimSpace(line), " ") + "\n")
+	}
+	return b.String()
+}
@@ -0,0 +1,144 @@
+package codegen
+
+import (
+	"go/ast"
+	"go/importer"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"strings"
+	"testing"
+
+	"github.com/labd/contentstack-go-sdk/schema"
+)
+
+func TestGenerate(t *testing.T) {
+	contentTypes := []Model{
+		{
+			UID:         "blog_post",
+			Description: "A blog post",
+			Schema: schema.Fields(
+				schema.Text("title").Mandatory().Unique(),
+				schema.Text("url"),
+				schema.Text("tags").Multiple(),
+				schema.Number("rating"),
+				schema.Select("color", "red", "blue"),
+				schema.Date("published_at"),
+				schema.Image("hero_image"),
+				schema.Link("link"),
+				schema.JSONRTE("body"),
+				schema.Reference("author", "author"),
+				schema.Reference("related", "blog_post", "page"),
+				schema.GlobalField("seo", "seo"),
+				schema.Group("meta", schema.Number("reading_time"), schema.Boolean("featured")).Multiple(),
+				schema.Blocks("sections",
+					schema.NewBlock("hero", "Hero", schema.Text("heading")),
+					schema.NewGlobalFieldBlock("seo", "SEO", "seo"),
+				),
+				schema.Taxonomies("categories", "category"),
+			),
+		},
+		{
+			UID:    "author",
+			Schema: schema.Fields(schema.Text("title").Mandatory().Unique(), schema.Text("uid")),
+		},
+		{
+			// Clashes with the Link support type
+			UID:    "link",
+			Schema: schema.Fields(schema.Text("title").Mandatory().Unique()),
+		},
+	}
+	globalFields := []Model{
+		{UID: "seo", Schema: schema.Fields(schema.Text("meta_title"), schema.Text("meta_description").Description("Shown in search results"))},
+	}
+
+	source, err := Generate(contentTypes, globalFields, Options{Package: "content"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	fset := token.NewFileSet()
+	file, err := parser.ParseFile(fset, "content.go", source, parser.ParseComments)
+	if err != nil {
+		t.Fatalf("generated code doesn't parse: %v\n%s", err, source)
+	}
+	conf := types.Config{Importer: importer.ForCompiler(fset, "source", nil)}
+	pkg, err := conf.Check("content", fset, []*ast.File{file}, nil)
+	if err != nil {
+		t.Fatalf("generated code doesn't compile: %v\n%s", err, source)
+	}
+
+	fields := map[string]string{
+		"BlogPost.Title":        "string",
+		"BlogPost.URL":          "string",
+		"BlogPost.Tags":         "[]string",
+		"BlogPost.Rating":       "float64",
+		"BlogPost.Color":        "string",
+		"BlogPost.PublishedAt":  "content.Date",
+		"BlogPost.HeroImage":    "content.AssetRef",
+		"BlogPost.Link":         "content.Link",
+		"BlogPost.Body":         "content.RTEDocument",
+		"BlogPost.Author":       "[]content.Ref[content.Author]",
+		"BlogPost.Related":      "[]content.EntryRef",
+		"BlogPost.SEO":          "content.SEO",
+		"BlogPost.Meta":         "[]content.BlogPostMeta",
+		"BlogPost.Sections":     "content.BlogPostSections",
+		"BlogPost.Categories":   "[]content.TaxonomyTerm",
+		"BlogPostMeta.Featured": "bool",
+		"Author.UIDField":       "string",
+		"LinkEntry.Title":       "string",
+	}
+	for path, want := range fields {
+		typeName, fieldName, _ := strings.Cut(path, ".")
+		obj := pkg.Scope().Lookup(typeName)
+		if obj == nil {
+			t.Errorf("type %s is not generated", typeName)
+			continue
+		}
+		field, _, _ := types.LookupFieldOrMethod(obj.Type(), false, pkg, fieldName)
+		if field == nil {
+			t.Errorf("%s is not generated", path)
+			continue
+		}
+		if got := field.Type().String(); got != want {
+			t.Errorf("type of %s = %s, want %s", path, got, want)
+		}
+	}
+
+	block := pkg.Scope().Lookup("BlogPostSectionsBlock")
+	if block == nil {
+		t.Fatal("BlogPostSectionsBlock is not generated")
+	}
+	iface := block.Type().Underlying().(*types.Interface)
+	for _, name := range []string{"BlogPostSectionsHero", "BlogPostSectionsSEO"} {
+		obj := pkg.Scope().Lookup(name)
+		if obj == nil || !types.Implements(obj.Type(), iface) {
+			t.Errorf("%s doesn't implement BlogPostSectionsBlock", name)
+		}
+	}
+
+	if !strings.Contains(string(source), "// Shown in search results\n") {
+		t.Error("field description is not generated")
+	}
+	if !strings.Contains(string(source), "`json:\"title\" contentstack:\"required\"`") {
+		t.Error("mandatory field is not tagged as required")
+	}
+	if !strings.Contains(string(source), "`json:\"url\"`") {
+		t.Error("optional field is tagged as required")
+	}
+}
+
+func TestIdentifier(t *testing.T) {
+	tests := map[string]string{
+		"title":          "Title",
+		"hero_image_url": "HeroImageURL",
+		"seo-settings":   "SEOSettings",
+		"2col":           "X2col",
+		"":               "Field",
+	}
+	for uid, want := range tests {
+		if got := identifier(uid); got != want {
+			t.Errorf("identifier(%q) = %q, want %q", uid, got, want)
+		}
+	}
+}
@@ -0,0 +1,14 @@
+package codegen
+
+import (
+	_ "embed"
+)
+
+// supportTypes are the types used by the generated structs. They are part of
+// the generated file, so it has no dependencies outside the standard library.
+var supportTypes = []string{"Ref", "EntryRef", "AssetRef", "Link", "Date", "RTEDocument", "RTENode", "TaxonomyTerm"}
+
+// supportCode contains the definitions of the support types.
+//
+//go:embed support.go.txt
+var supportCode string
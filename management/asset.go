@@ -0,0 +1,382 @@
+package management
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+type AssetResponse struct {
+	Asset Asset `json:"asset"`
+}
+
+// Asset represents an asset or an asset folder in contentstack.
+type Asset struct {
+	UID            string         `json:"uid"`
+	CreatedAt      time.Time      `json:"created_at"`
+	UpdatedAt      time.Time      `json:"updated_at"`
+	CreatedBy      string         `json:"created_by"`
+	UpdatedBy      string         `json:"updated_by"`
+	ContentType    string         `json:"content_type"`
+	FileSize       string         `json:"file_size"`
+	Filename       string         `json:"filename"`
+	Name           string         `json:"name"`
+	Title          string         `json:"title"`
+	Description    string         `json:"description"`
+	URL            string         `json:"url"`
+	Tags           []string       `json:"tags"`
+	ParentUID      string         `json:"parent_uid"`
+	IsDir          bool           `json:"is_dir"`
+	Version        int            `json:"_version"`
+	PublishDetails PublishDetails `json:"publish_details"`
+}
+
+// AssetInput is used to upload or replace an asset
+type AssetInput struct {
+	// Filename is the name of the uploaded file, the content type of the
+	// asset is derived from it.
+	Filename string
+	Content  io.Reader
+
+	Title       string
+	Description string
+	Tags        []string
+
+	// ParentUID is the folder to store the asset in. The asset is stored in
+	// the root folder when empty.
+	ParentUID string
+}
+
+// errUploadDone stops writing the multipart body once the attempt is done.
+var errUploadDone = errors.New("upload done")
+
+// serialize returns the input as a multipart body and the matching content
+// type. The content is streamed instead of loaded in memory; the request is
+// only retried when the content implements io.Seeker.
+func (a *AssetInput) serialize() (requestBody, string, error) {
+	if a.Content == nil {
+		return requestBody{}, "", fmt.Errorf("the content of the asset is required")
+	}
+
+	// Use the same boundary for every attempt
+	boundary := multipart.NewWriter(io.Discard).Boundary()
+	contentType := "multipart/form-data; boundary=" + boundary
+
+	seeker, replayable := a.Content.(io.Seeker)
+	var offset int64
+	if replayable {
+		var err error
+		if offset, err = seeker.Seek(0, io.SeekCurrent); err != nil {
+			replayable = false
+		}
+	}
+
+	// The writer of an attempt reads the content, so it is stopped before the
+	// content is rewound for the next attempt
+	var (
+		opened bool
+		reader *io.PipeReader
+		done   chan struct{}
+	)
+	release := func() {
+		if reader == nil {
+			return
+		}
+		reader.CloseWithError(errUploadDone)
+		<-done
+		reader = nil
+	}
+	open := func() (io.Reader, error) {
+		release()
+		if opened {
+			if _, err := seeker.Seek(offset, io.SeekStart); err != nil {
+				return nil, err
+			}
+		}
+		opened = true
+
+		var writer *io.PipeWriter
+		reader, writer = io.Pipe()
+		done = make(chan struct{})
+		go func() {
+			defer close(done)
+			writer.CloseWithError(a.write(writer, boundary))
+		}()
+		return reader, nil
+	}
+
+	return requestBody{open: open, close: release, replayable: replayable}, contentType, nil
+}
+
+// write writes the multipart body to w.
+func (a *AssetInput) write(w io.Writer, boundary string) error {
+	writer := multipart.NewWriter(w)
+	if err := writer.SetBoundary(boundary); err != nil {
+		return err
+	}
+
+	fields := []struct{ name, value string }{
+		{"asset[title]", a.Title},
+		{"asset[description]", a.Description},
+		{"asset[parent_uid]", a.ParentUID},
+		{"asset[tags]", strings.Join(a.Tags, ",")},
+	}
+	for _, field := range fields {
+		if field.value == "" {
+			continue
+		}
+		if err := writer.WriteField(field.name, field.value); err != nil {
+			return err
+		}
+	}
+
+	part, err := writer.CreateFormFile("asset[upload]", a.Filename)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(part, a.Content); err != nil {
+		return fmt.Errorf("Unable to read asset content: %w", err)
+	}
+	return writer.Close()
+}
+
+// AssetCreate uploads a new asset.
+func (si *StackInstance) AssetCreate(ctx context.Context, input AssetInput) (*Asset, error) {
+	data, contentType, err := input.serialize()
+	if err != nil {
+		return nil, err
+	}
+
+	headers := si.headers()
+	headers.Set("Content-Type", contentType)
+
+	resp, err := si.client.do(
+		ctx,
+		http.MethodPost,
+		"/v3/assets",
+		url.Values{},
+		headers,
+		data,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &AssetResponse{}
+	if err = si.client.processResponse(resp, &result); err != nil {
+		return nil, err
+	}
+
+	return &result.Asset, nil
+}
+
+// AssetReplace replaces the file and the details of an existing asset.
+func (si *StackInstance) AssetReplace(ctx context.Context, uid string, input AssetInput) (*Asset, error) {
+	data, contentType, err := input.serialize()
+	if err != nil {
+		return nil, err
+	}
+
+	headers := si.headers()
+	headers.Set("Content-Type", contentType)
+
+	resp, err := si.client.do(
+		ctx,
+		http.MethodPut,
+		fmt.Sprintf("/v3/assets/%s", uid),
+		url.Values{},
+		headers,
+		data,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &AssetResponse{}
+	if err = si.client.processResponse(resp, &result); err != nil {
+		return nil, err
+	}
+
+	return &result.Asset, nil
+}
+
+func (si *StackInstance) AssetDelete(ctx context.Context, uid string) error {
+	resp, err := si.client.delete(
+		ctx,
+		fmt.Sprintf("/v3/assets/%s", uid),
+		url.Values{},
+		si.headers(),
+		nil,
+	)
+	if err != nil {
+		return err
+	}
+
+	result := &AssetResponse{}
+	if err = si.client.processResponse(resp, &result); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (si *StackInstance) AssetFetch(ctx context.Context, uid string) (*Asset, error) {
+	resp, err := si.client.get(
+		ctx,
+		fmt.Sprintf("/v3/assets/%s", uid),
+		url.Values{},
+		si.headers(),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &AssetResponse{}
+	if err = si.client.processResponse(resp, &result); err != nil {
+		return nil, err
+	}
+
+	return &result.Asset, nil
+}
+
+// AssetFetchAll returns all assets in the given folder, or all assets of the
+// stack when folderUID is empty.
+func (si *StackInstance) AssetFetchAll(ctx context.Context, folderUID string) ([]Asset, error) {
+	return fetchAll(ctx, ListOptions{}, func(ctx context.Context, opts ListOptions) (*Page[Asset], error) {
+		return si.AssetFetchPage(ctx, folderUID, opts)
+	})
+}
+
+func (si *StackInstance) AssetIterator(folderUID string, opts ListOptions) *Iterator[Asset] {
+	return newIterator(opts, func(ctx context.Context, opts ListOptions) (*Page[Asset], error) {
+		return si.AssetFetchPage(ctx, folderUID, opts)
+	})
+}
+
+func (si *StackInstance) AssetFetchPage(ctx context.Context, folderUID string, opts ListOptions) (*Page[Asset], error) {
+	params := opts.values()
+	if folderUID != "" {
+		params.Set("folder", folderUID)
+	}
+
+	resp, err := si.client.get(
+		ctx,
+		"/v3/assets",
+		params,
+		si.headers(),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	result := struct {
+		Assets []Asset `json:"assets"`
+		Count  int     `json:"count"`
+	}{}
+
+	if err = si.client.processResponse(resp, &result); err != nil {
+		return nil, err
+	}
+
+	return newPage(result.Assets, result.Count, opts), nil
+}
+
+// AssetDownload returns the content of the asset. The caller must close the
+// returned reader.
+func (si *StackInstance) AssetDownload(ctx context.Context, asset *Asset) (io.ReadCloser, error) {
+	if asset.URL == "" {
+		return nil, fmt.Errorf("asset %s has no url", asset.UID)
+	}
+
+	// The asset is served from the CDN, so don't send the credentials
+	resp, err := si.client.get(
+		ctx,
+		asset.URL,
+		nil,
+		http.Header{},
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return nil, si.client.processResponse(resp, nil)
+	}
+
+	return resp.Body, nil
+}
+
+// AssetPublish publishes the asset to the given environments and locales.
+func (si *StackInstance) AssetPublish(ctx context.Context, uid string, input PublishInput) (*PublishResult, error) {
+	return si.assetPublishAction(ctx, fmt.Sprintf("/v3/assets/%s/publish", uid), input)
+}
+
+// AssetUnpublish unpublishes the asset from the given environments and
+// locales.
+func (si *StackInstance) AssetUnpublish(ctx context.Context, uid string, input PublishInput) (*PublishResult, error) {
+	return si.assetPublishAction(ctx, fmt.Sprintf("/v3/assets/%s/unpublish", uid), input)
+}
+
+func (si *StackInstance) assetPublishAction(ctx context.Context, endpoint string, input PublishInput) (*PublishResult, error) {
+	body, err := input.serialize("asset", "")
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := serializeInput(body)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := si.client.post(
+		ctx,
+		endpoint,
+		url.Values{},
+		si.headers(),
+		data,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &PublishResult{}
+	if err = si.client.processResponse(resp, &result); err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+func (si *StackInstance) AssetVersionFetchAll(ctx context.Context, uid string) ([]VersionInfo, error) {
+	return fetchAll(ctx, ListOptions{}, func(ctx context.Context, opts ListOptions) (*Page[VersionInfo], error) {
+		return si.AssetVersionFetchPage(ctx, uid, opts)
+	})
+}
+
+func (si *StackInstance) AssetVersionFetchPage(ctx context.Context, uid string, opts ListOptions) (*Page[VersionInfo], error) {
+	resp, err := si.client.get(
+		ctx,
+		fmt.Sprintf("/v3/assets/%s/versions", uid),
+		opts.values(),
+		si.headers(),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	result := struct {
+		Versions []VersionInfo `json:"versions"`
+		Count    int           `json:"count"`
+	}{}
+	if err = si.client.processResponse(resp, &result); err != nil {
+		return nil, err
+	}
+
+	return newPage(result.Versions, result.Count, opts), nil
+}
@@ -0,0 +1,163 @@
+package management
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+)
+
+// AssetFolderInput is used to create or update an asset folder
+type AssetFolderInput struct {
+	Name      string `json:"name,omitempty"`
+	ParentUID string `json:"parent_uid,omitempty"`
+}
+
+type AssetFolderRequest struct {
+	Asset AssetFolderInput `json:"asset"`
+}
+
+// AssetFolderCreate creates a folder. The folder is created in the root
+// folder when input.ParentUID is empty.
+func (si *StackInstance) AssetFolderCreate(ctx context.Context, input AssetFolderInput) (*Asset, error) {
+	data, err := serializeInput(AssetFolderRequest{Asset: input})
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := si.client.post(
+		ctx,
+		"/v3/assets/folders",
+		url.Values{},
+		si.headers(),
+		data,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &AssetResponse{}
+	if err = si.client.processResponse(resp, &result); err != nil {
+		return nil, err
+	}
+
+	return &result.Asset, nil
+}
+
+// AssetFolderUpdate renames the folder and/or moves it to another parent
+// folder.
+func (si *StackInstance) AssetFolderUpdate(ctx context.Context, uid string, input AssetFolderInput) (*Asset, error) {
+	return si.assetFolderUpdate(ctx, uid, AssetFolderRequest{Asset: input})
+}
+
+func (si *StackInstance) assetFolderUpdate(ctx context.Context, uid string, input interface{}) (*Asset, error) {
+	data, err := serializeInput(input)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := si.client.put(
+		ctx,
+		fmt.Sprintf("/v3/assets/folders/%s", uid),
+		url.Values{},
+		si.headers(),
+		data,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &AssetResponse{}
+	if err = si.client.processResponse(resp, &result); err != nil {
+		return nil, err
+	}
+
+	return &result.Asset, nil
+}
+
+// AssetFolderRename renames the folder.
+func (si *StackInstance) AssetFolderRename(ctx context.Context, uid string, name string) (*Asset, error) {
+	return si.AssetFolderUpdate(ctx, uid, AssetFolderInput{Name: name})
+}
+
+// AssetFolderMove moves the folder to the given parent folder, or to the root
+// folder when parentUID is empty.
+func (si *StackInstance) AssetFolderMove(ctx context.Context, uid string, parentUID string) (*Asset, error) {
+	// An empty parent is sent as null, as AssetFolderInput leaves it out
+	var parent *string
+	if parentUID != "" {
+		parent = &parentUID
+	}
+	return si.assetFolderUpdate(ctx, uid, map[string]interface{}{
+		"asset": map[string]*string{"parent_uid": parent},
+	})
+}
+
+func (si *StackInstance) AssetFolderDelete(ctx context.Context, uid string) error {
+	resp, err := si.client.delete(
+		ctx,
+		fmt.Sprintf("/v3/assets/folders/%s", uid),
+		url.Values{},
+		si.headers(),
+		nil,
+	)
+	if err != nil {
+		return err
+	}
+
+	result := &AssetResponse{}
+	if err = si.client.processResponse(resp, &result); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (si *StackInstance) AssetFolderFetch(ctx context.Context, uid string) (*Asset, error) {
+	resp, err := si.client.get(
+		ctx,
+		fmt.Sprintf("/v3/assets/folders/%s", uid),
+		url.Values{},
+		si.headers(),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &AssetResponse{}
+	if err = si.client.processResponse(resp, &result); err != nil {
+		return nil, err
+	}
+
+	return &result.Asset, nil
+}
+
+// AssetFolderFetchAll returns all asset folders of the stack.
+func (si *StackInstance) AssetFolderFetchAll(ctx context.Context) ([]Asset, error) {
+	return fetchAll(ctx, ListOptions{}, si.AssetFolderFetchPage)
+}
+
+func (si *StackInstance) AssetFolderFetchPage(ctx context.Context, opts ListOptions) (*Page[Asset], error) {
+	params := opts.values()
+	params.Set("include_folders", "true")
+	params.Set("query", `{"is_dir": true}`)
+
+	resp, err := si.client.get(
+		ctx,
+		"/v3/assets",
+		params,
+		si.headers(),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	result := struct {
+		Assets []Asset `json:"assets"`
+		Count  int     `json:"count"`
+	}{}
+	if err = si.client.processResponse(resp, &result); err != nil {
+		return nil, err
+	}
+
+	return newPage(result.Assets, result.Count, opts), nil
+}
@@ -0,0 +1,219 @@
+package management
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"sync/atomic"
+	"testing"
+)
+
+func TestStackInstance_AssetCreate(t *testing.T) {
+	var form map[string][]string
+	var file string
+	stack := newTestStack(t, func(w http.ResponseWriter, r *http.Request) {
+		if err := r.ParseMultipartForm(1 << 20); err != nil {
+			t.Errorf("invalid multipart body: %v", err)
+			return
+		}
+		form = r.MultipartForm.Value
+
+		f, header, err := r.FormFile("asset[upload]")
+		if err != nil {
+			t.Errorf("missing file: %v", err)
+			return
+		}
+		data, _ := io.ReadAll(f)
+		file = header.Filename + ":" + string(data)
+
+		_, _ = w.Write([]byte(`{"asset": {"uid": "asset_uid", "filename": "logo.svg", "title": "Logo"}}`))
+	})
+
+	asset, err := stack.AssetCreate(context.Background(), AssetInput{
+		Filename:  "logo.svg",
+		Content:   strings.NewReader("<svg/>"),
+		Title:     "Logo",
+		Tags:      []string{"brand", "logo"},
+		ParentUID: "folder_uid",
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if asset.UID != "asset_uid" {
+		t.Errorf("UID = %q, want %q", asset.UID, "asset_uid")
+	}
+	if file != "logo.svg:<svg/>" {
+		t.Errorf("file = %q", file)
+	}
+	if form["asset[title]"][0] != "Logo" || form["asset[tags]"][0] != "brand,logo" || form["asset[parent_uid]"][0] != "folder_uid" {
+		t.Errorf("unexpected form values: %v", form)
+	}
+	if _, ok := form["asset[description]"]; ok {
+		t.Errorf("empty description should not be sent")
+	}
+}
+
+func TestStackInstance_AssetCreateRetry(t *testing.T) {
+	tests := []struct {
+		name     string
+		content  io.Reader
+		requests int
+		wantErr  bool
+	}{
+		{"seekable content is replayed", strings.NewReader("<svg/>"), 2, false},
+		{"streamed content is sent once", io.MultiReader(strings.NewReader("<svg/>")), 1, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			requests := 0
+			client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+				requests++
+				f, _, err := r.FormFile("asset[upload]")
+				if err != nil {
+					t.Errorf("missing file: %v", err)
+					return
+				}
+				if data, _ := io.ReadAll(f); string(data) != "<svg/>" {
+					t.Errorf("file = %q, want %q", data, "<svg/>")
+				}
+				if requests == 1 {
+					w.WriteHeader(http.StatusTooManyRequests)
+					return
+				}
+				_, _ = w.Write([]byte(`{"asset": {"uid": "asset_uid"}}`))
+			}, testRetryPolicy)
+			stack, err := client.Stack(&StackAuth{ApiKey: "api-key"})
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			_, err = stack.AssetCreate(context.Background(), AssetInput{
+				Filename: "logo.svg",
+				Content:  tt.content,
+			})
+			if (err != nil) != tt.wantErr {
+				t.Errorf("error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if requests != tt.requests {
+				t.Errorf("requests = %d, want %d", requests, tt.requests)
+			}
+		})
+	}
+}
+
+func TestStackInstance_AssetCreateRetryEarlyResponse(t *testing.T) {
+	content := strings.Repeat("x", 16<<20)
+	var requests int32
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		// Respond to the first attempt before the upload is read
+		if atomic.AddInt32(&requests, 1) == 1 {
+			w.WriteHeader(http.StatusTooManyRequests)
+			return
+		}
+		f, _, err := r.FormFile("asset[upload]")
+		if err != nil {
+			t.Errorf("missing file: %v", err)
+			return
+		}
+		if data, _ := io.ReadAll(f); string(data) != content {
+			t.Errorf("file has %d bytes, want %d", len(data), len(content))
+		}
+		w.WriteHeader(http.StatusCreated)
+		_, _ = w.Write([]byte(`{"asset": {"uid": "asset_uid"}}`))
+	}, testRetryPolicy)
+	stack, err := client.Stack(&StackAuth{ApiKey: "api-key"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	asset, err := stack.AssetCreate(context.Background(), AssetInput{
+		Filename: "large.txt",
+		// Without WriteTo, so the content is copied in chunks
+		Content: io.NewSectionReader(strings.NewReader(content), 0, int64(len(content))),
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if asset.UID != "asset_uid" {
+		t.Errorf("UID = %q, want %q", asset.UID, "asset_uid")
+	}
+	if n := atomic.LoadInt32(&requests); n != 2 {
+		t.Errorf("requests = %d, want 2", n)
+	}
+}
+
+func TestStackInstance_AssetDownload(t *testing.T) {
+	tests := []struct {
+		name    string
+		status  int
+		want    string
+		wantErr bool
+	}{
+		{"ok", http.StatusOK, "<svg/>", false},
+		{"partial content", http.StatusPartialContent, "<svg/>", false},
+		{"not found", http.StatusNotFound, "", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cdn := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(tt.status)
+				_, _ = w.Write([]byte("<svg/>"))
+			}))
+			defer cdn.Close()
+
+			stack := newTestStack(t, func(w http.ResponseWriter, r *http.Request) {
+				t.Errorf("unexpected request to %s", r.URL.Path)
+			})
+			body, err := stack.AssetDownload(context.Background(), &Asset{UID: "asset_uid", URL: cdn.URL + "/logo.svg"})
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err != nil {
+				return
+			}
+			defer body.Close()
+
+			data, _ := io.ReadAll(body)
+			if string(data) != tt.want {
+				t.Errorf("body = %q, want %q", data, tt.want)
+			}
+		})
+	}
+}
+
+func TestStackInstance_AssetFolderMove(t *testing.T) {
+	tests := []struct {
+		name      string
+		parentUID string
+		want      map[string]interface{}
+	}{
+		{"to a folder", "folder_uid", map[string]interface{}{"parent_uid": "folder_uid"}},
+		{"to the root", "", map[string]interface{}{"parent_uid": nil}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var body map[string]map[string]interface{}
+			stack := newTestStack(t, func(w http.ResponseWriter, r *http.Request) {
+				if r.Method != http.MethodPut || r.URL.Path != "/v3/assets/folders/child_uid" {
+					t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+				}
+				_ = json.NewDecoder(r.Body).Decode(&body)
+				_, _ = w.Write([]byte(`{"asset": {"uid": "child_uid", "is_dir": true}}`))
+			})
+
+			if _, err := stack.AssetFolderMove(context.Background(), "child_uid", tt.parentUID); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !reflect.DeepEqual(body["asset"], tt.want) {
+				t.Errorf("asset = %v, want %v", body["asset"], tt.want)
+			}
+		})
+	}
+}
@@ -1,6 +1,7 @@
 package management
 
 import (
+	"bytes"
 	"context"
 	"encoding/json"
 	"fmt"
@@ -8,6 +9,12 @@ import (
 	"io/ioutil"
 	"net/http"
 	"net/url"
+	"strings"
+	"sync"
+
+	"go.opentelemetry.io/otel/metric"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
 )
 
 type Auth struct {
@@ -15,39 +22,69 @@ type Auth struct {
 }
 
 type ClientConfig struct {
-	BaseURL         string
+	// BaseURL is the base URL of the management API. When empty the URL of
+	// the Region is used.
+	BaseURL string
+
+	// Region is the Contentstack region of the organization.
+	Region Region
+
 	HTTPClient      *http.Client
 	AuthToken       string
 	OrganizationUID string
-}
 
-type UserCredentials struct {
-	Email    string `json:"email"`
-	Password string `json:"password"`
+	// Authenticator authenticates the requests when no auth token or
+	// management token is used, e.g. with an OAuthAuthenticator.
+	Authenticator Authenticator
+
+	// RetryPolicy configures retries of rate limited and failed requests.
+	// When nil requests are not retried.
+	RetryPolicy *RetryPolicy
+
+	// RateLimit limits the number of requests sent per second. When nil
+	// requests are not limited.
+	RateLimit *RateLimit
+
+	// Logging enables structured logging of all requests. When nil requests
+	// are not logged.
+	Logging *LogOptions
+
+	// TracerProvider, MeterProvider and Propagator are used to trace the API
+	// calls and record their metrics. They default to the global providers
+	// and propagator of the otel package.
+	TracerProvider trace.TracerProvider
+	MeterProvider  metric.MeterProvider
+	Propagator     propagation.TextMapPropagator
 }
 
 type Client struct {
-	authToken  string
-	baseURL    *url.URL
-	httpClient *http.Client
-}
+	// mu guards the auth token, which is set when logging in
+	mu        sync.RWMutex
+	authToken string
 
-type ErrorMessage struct {
-	ErrorMessage string              `json:"error_message"`
-	ErrorCode    int                 `json:"error_code"`
-	Errors       map[string][]string `json:"errors"`
-}
+	authenticator Authenticator
 
-func (e *ErrorMessage) Error() string {
-	return e.ErrorMessage
+	baseURL     *url.URL
+	httpClient  *http.Client
+	retryPolicy *RetryPolicy
+	telemetry   *telemetry
+	rateLimiter *rateLimiter
 }
 
 func NewClient(cfg ClientConfig) (*Client, error) {
-	if cfg.BaseURL == "" {
-		return nil, fmt.Errorf("missing BaseURL")
+	baseURL := cfg.BaseURL
+	if baseURL == "" {
+		if cfg.Region == "" {
+			return nil, fmt.Errorf("missing BaseURL or Region")
+		}
+		endpoints, err := cfg.Region.Endpoints()
+		if err != nil {
+			return nil, err
+		}
+		baseURL = endpoints.Management
 	}
 
-	url, err := url.Parse(cfg.BaseURL)
+	url, err := url.Parse(baseURL)
 	if err != nil {
 		return nil, err
 	}
@@ -60,15 +97,55 @@ func NewClient(cfg ClientConfig) (*Client, error) {
 		httpClient = &http.Client{}
 	}
 
+	// Wrap the transport of a copy, to leave the passed client untouched
+	if cfg.Logging != nil {
+		logged := *httpClient
+		logged.Transport = NewLogTransport(httpClient.Transport, *cfg.Logging)
+		httpClient = &logged
+	}
+
+	telemetry, err := newTelemetry(cfg)
+	if err != nil {
+		return nil, err
+	}
+
 	client := &Client{
-		baseURL:    url,
-		authToken:  cfg.AuthToken,
-		httpClient: httpClient,
+		baseURL:       url,
+		authToken:     cfg.AuthToken,
+		authenticator: cfg.Authenticator,
+		httpClient:    httpClient,
+		retryPolicy:   cfg.RetryPolicy,
+		telemetry:     telemetry,
+		rateLimiter:   newRateLimiter(cfg.RateLimit),
 	}
 
 	return client, nil
 }
 
+// token returns the auth token of the client, which changes when logging in
+// or out.
+func (c *Client) token() string {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.authToken
+}
+
+func (c *Client) setAuthToken(token string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.authToken = token
+}
+
+// userHeaders returns the headers to authenticate requests on behalf of the
+// user, which aren't scoped to a stack.
+func (c *Client) userHeaders() http.Header {
+	header := http.Header{}
+	if token := c.token(); token != "" {
+		header.Add("authtoken", token)
+	}
+	return header
+}
+
 func NewClientWithToken(auth *Auth) *Client {
 	return &Client{}
 }
@@ -102,6 +179,46 @@ func (c *Client) createEndpoint(p string) (*url.URL, error) {
 }
 
 func (c *Client) execute(ctx context.Context, method string, path string, params url.Values, headers http.Header, body io.Reader) (*http.Response, error) {
+	rb, err := bufferBody(body)
+	if err != nil {
+		return nil, err
+	}
+	return c.do(ctx, method, path, params, headers, rb)
+}
+
+// requestBody creates the body of a request for every attempt. A body which
+// can't be created again is only sent once, so the request isn't retried.
+// When set, close is called when an attempt is done to release the body.
+type requestBody struct {
+	open       func() (io.Reader, error)
+	close      func()
+	replayable bool
+}
+
+func (b requestBody) release() {
+	if b.close != nil {
+		b.close()
+	}
+}
+
+// bufferBody reads the body upfront so it can be replayed when the request
+// is retried.
+func bufferBody(body io.Reader) (requestBody, error) {
+	if body == nil {
+		return requestBody{open: func() (io.Reader, error) { return nil, nil }, replayable: true}, nil
+	}
+
+	payload, err := io.ReadAll(body)
+	if err != nil {
+		return requestBody{}, fmt.Errorf("Reading request body: %w", err)
+	}
+	return requestBody{
+		open:       func() (io.Reader, error) { return bytes.NewReader(payload), nil },
+		replayable: true,
+	}, nil
+}
+
+func (c *Client) do(ctx context.Context, method string, path string, params url.Values, headers http.Header, body requestBody) (*http.Response, error) {
 	endpoint, err := c.createEndpoint(path)
 	if err != nil {
 		return nil, err
@@ -111,67 +228,107 @@ func (c *Client) execute(ctx context.Context, method string, path string, params
 		endpoint.RawQuery = params.Encode()
 	}
 
-	req, err := http.NewRequestWithContext(ctx, method, endpoint.String(), body)
+	ctx, span := c.telemetry.start(ctx, method, endpoint.Path, headers)
+	resp, err := c.send(ctx, method, endpoint.String(), headers, body, span)
+	span.end(resp, err)
+	return resp, err
+}
+
+// send sends the request, retrying it according to the retry policy.
+func (c *Client) send(ctx context.Context, method string, endpoint string, headers http.Header, body requestBody, span *requestSpan) (*http.Response, error) {
+	for attempt := 1; ; attempt++ {
+		attemptCtx := withAttempt(ctx, attempt)
+		prepared, err := newRequest(attemptCtx, method, endpoint, headers, nil)
+		if err != nil {
+			return nil, err
+		}
+		span.inject(prepared.Header)
+
+		// Authenticate every attempt, so an expired token is refreshed
+		if c.authenticates(prepared) {
+			if err := c.authenticator.Authenticate(ctx, prepared.Header); err != nil {
+				return nil, fmt.Errorf("Unable to authenticate request: %w", err)
+			}
+		}
+
+		if err := c.rateLimiter.wait(ctx, method); err != nil {
+			return nil, err
+		}
+
+		// Only open the body right before sending it, so it is always
+		// released when the attempt is done
+		data, err := body.open()
+		if err != nil {
+			return nil, fmt.Errorf("Reading request body: %w", err)
+		}
+		req, err := newRequest(attemptCtx, method, endpoint, prepared.Header, data)
+		if err != nil {
+			body.release()
+			return nil, err
+		}
+		resp, err := c.httpClient.Do(req)
+		body.release()
+		c.rateLimiter.update(method, resp)
+		delay, retry := c.retryPolicy.shouldRetry(ctx, method, attempt, resp, err)
+		if !retry || !body.replayable {
+			return resp, err
+		}
+
+		drainBody(resp)
+		if err := sleep(ctx, delay); err != nil {
+			return nil, err
+		}
+	}
+}
+
+// authenticates returns whether the authenticator adds its credentials to the
+// request. Requests which already have credentials, requests to log in and out
+// and requests to other hosts than the API, like asset downloads from the CDN,
+// are sent without them.
+func (c *Client) authenticates(req *http.Request) bool {
+	if c.authenticator == nil || req.Header.Get("authorization") != "" || req.Header.Get("authtoken") != "" {
+		return false
+	}
+	if req.URL.Scheme != c.baseURL.Scheme || !strings.EqualFold(req.URL.Host, c.baseURL.Host) {
+		return false
+	}
+	return !strings.HasSuffix(req.URL.Path, "/user-session")
+}
+
+func newRequest(ctx context.Context, method string, endpoint string, headers http.Header, body io.Reader) (*http.Request, error) {
+	req, err := http.NewRequestWithContext(ctx, method, endpoint, body)
 	if err != nil {
 		return nil, fmt.Errorf("Creating new request: %w", err)
 	}
 
 	if headers != nil {
-		req.Header = headers
+		req.Header = headers.Clone()
 	}
 	req.Header.Set("Accept", "application/json; charset=utf-8")
-	req.Header.Set("Content-Type", "application/json; charset=utf-8")
-
-	resp, err := c.httpClient.Do(req)
-	if err != nil {
-		return nil, err
+	if req.Header.Get("Content-Type") == "" {
+		req.Header.Set("Content-Type", "application/json; charset=utf-8")
 	}
 
-	return resp, nil
+	return req, nil
 }
 
 func (c *Client) processResponse(r *http.Response, dst interface{}) error {
 	content, err := ioutil.ReadAll(r.Body)
 	defer r.Body.Close()
+	if err != nil {
+		return fmt.Errorf("Reading response body: %w", err)
+	}
 
-	switch r.StatusCode {
-	case 200, 201:
-		if err = json.Unmarshal(content, &dst); err != nil {
-			return err
-		}
+	if r.StatusCode < 200 || r.StatusCode > 299 {
+		return newAPIError(r, content)
+	}
+
+	if len(content) == 0 || r.StatusCode == http.StatusNoContent {
 		return nil
-	case 401:
-		result := make(map[string]interface{})
-		if err = json.Unmarshal(content, &result); err != nil {
-			return err
-		}
-		return fmt.Errorf("Not authorized")
-	case 404:
-		return &ErrorMessage{
-			ErrorMessage: "Resource not found",
-			ErrorCode:    404,
-		}
-	case 422:
-		result := ErrorMessage{}
-		if err = json.Unmarshal(content, &result); err != nil {
-
-			// Contentstack can return 'invalid' json, so try to process that
-			// {"error_message":"xx","error_code":115,"errors":[{}]}
-			tmp := struct {
-				ErrorMessage string `json:"error_message"`
-				ErrorCode    int    `json:"error_code"`
-			}{}
-
-			if err = json.Unmarshal(content, &tmp); err != nil {
-				return err
-			}
-			return &ErrorMessage{
-				ErrorMessage: tmp.ErrorMessage,
-				ErrorCode:    tmp.ErrorCode,
-			}
-		}
-		return &result
-	default:
-		return fmt.Errorf("Unhandled StatusCode: %d", r.StatusCode)
 	}
+
+	if err = json.Unmarshal(content, &dst); err != nil {
+		return err
+	}
+	return nil
 }
@@ -1,54 +1,181 @@
 package management
 
 import (
-	"log"
+	"bytes"
+	"context"
+	"io"
+	"log/slog"
+	"mime"
 	"net/http"
-	"net/http/httputil"
+	"strings"
+	"time"
 )
 
+// defaultMaxBodySize is the number of bytes of a body logged when
+// LogOptions.MaxBodySize is not set.
+const defaultMaxBodySize = 4096
+
+// LogOptions configures the logging of requests. Credentials are always
+// redacted from the logged headers, query parameters and bodies.
+type LogOptions struct {
+	// Logger receives a record for every request. Defaults to slog.Default().
+	Logger *slog.Logger
+
+	// Level is the level of the logged records. Failed requests are logged at
+	// least at the warning level.
+	Level slog.Level
+
+	// Headers adds the request and response headers to the records.
+	Headers bool
+
+	// Bodies adds the request and response bodies to the records. Only JSON
+	// bodies are logged.
+	Bodies bool
+
+	// MaxBodySize is the maximum number of bytes of a logged body, longer
+	// bodies are truncated. Defaults to 4096, use a negative value to log
+	// complete bodies.
+	MaxBodySize int
+}
+
+// LogTransport is a http.RoundTripper logging all requests as structured
+// slog records with the method, path, status, duration, attempt and request
+// ID.
 type LogTransport struct {
 	transport http.RoundTripper
+	options   LogOptions
 }
 
-var DebugTransport = &LogTransport{
-	transport: http.DefaultTransport,
+// DebugTransport logs all requests, including their headers and bodies, to
+// the default logger.
+var DebugTransport = NewLogTransport(http.DefaultTransport, LogOptions{
+	Headers: true,
+	Bodies:  true,
+})
+
+// NewLogTransport returns a LogTransport sending requests with the given
+// transport, which defaults to http.DefaultTransport.
+func NewLogTransport(transport http.RoundTripper, opts LogOptions) *LogTransport {
+	if transport == nil {
+		transport = http.DefaultTransport
+	}
+	return &LogTransport{
+		transport: transport,
+		options:   opts,
+	}
 }
 
 func (c *LogTransport) RoundTrip(request *http.Request) (*http.Response, error) {
-	logRequest(request)
-	response, err := c.transport.RoundTrip(request)
-	logResponse(response, err)
-	return response, err
-}
+	ctx := request.Context()
+	logger := c.options.Logger
+	if logger == nil {
+		logger = slog.Default()
+	}
 
-const logRequestTemplate = `DEBUG:
----[ REQUEST ]--------------------------------------------------------
-%s
-----------------------------------------------------------------------
-`
+	level := c.options.Level
+	if !logger.Enabled(ctx, level) && !logger.Enabled(ctx, slog.LevelWarn) {
+		return c.transport.RoundTrip(request)
+	}
+
+	attrs := []slog.Attr{
+		slog.String("method", request.Method),
+		slog.String("path", request.URL.Path),
+	}
+	if request.URL.RawQuery != "" {
+		attrs = append(attrs, slog.String("query", redactQuery(request.URL.Query()).Encode()))
+	}
+	if attempt := attemptFromContext(ctx); attempt > 0 {
+		attrs = append(attrs, slog.Int("attempt", attempt))
+	}
+	if c.options.Headers {
+		attrs = append(attrs, slog.Any("request_headers", redactHeader(request.Header)))
+	}
+	// Only read JSON bodies, so streamed uploads aren't loaded in memory
+	if c.options.Bodies && isJSON(request.Header) {
+		body, err := readRequestBody(request)
+		if err != nil {
+			return nil, err
+		}
+		if value, ok := c.body(request.Header, body); ok {
+			attrs = append(attrs, slog.String("request_body", value))
+		}
+	}
 
-const logResponseTemplate = `DEBUG:
----[ RESPONSE ]-------------------------------------------------------
-%s
-----------------------------------------------------------------------
-`
+	start := time.Now()
+	response, err := c.transport.RoundTrip(request)
+	attrs = append(attrs, slog.Duration("duration", time.Since(start)))
 
-func logRequest(r *http.Request) {
-	body, err := httputil.DumpRequestOut(r, true)
 	if err != nil {
-		return
+		attrs = append(attrs, slog.Any("error", err))
+		logger.LogAttrs(ctx, maxLevel(level, slog.LevelWarn), "Contentstack request failed", attrs...)
+		return response, err
 	}
-	log.Printf(logRequestTemplate, body)
+
+	attrs = append(attrs, slog.Int("status", response.StatusCode))
+	if id := requestID(response.Header); id != "" {
+		attrs = append(attrs, slog.String("request_id", id))
+	}
+	if c.options.Headers {
+		attrs = append(attrs, slog.Any("response_headers", redactHeader(response.Header)))
+	}
+	if c.options.Bodies && isJSON(response.Header) {
+		body, err := io.ReadAll(response.Body)
+		response.Body.Close()
+		if err != nil {
+			return nil, err
+		}
+		response.Body = io.NopCloser(bytes.NewReader(body))
+		if value, ok := c.body(response.Header, body); ok {
+			attrs = append(attrs, slog.String("response_body", value))
+		}
+	}
+
+	if response.StatusCode >= 400 {
+		level = maxLevel(level, slog.LevelWarn)
+	}
+	logger.LogAttrs(ctx, level, "Contentstack request", attrs...)
+	return response, nil
 }
 
-func logResponse(r *http.Response, err error) {
-	if err != nil {
-		log.Printf(logResponseTemplate, err)
-		return
+// body returns the redacted and truncated body for logging. Bodies which are
+// not JSON are not logged since they can't be redacted.
+func (c *LogTransport) body(header http.Header, body []byte) (string, bool) {
+	if len(body) == 0 || !isJSON(header) {
+		return "", false
 	}
-	body, err := httputil.DumpResponse(r, true)
-	if err != nil {
-		return
+
+	result := redactBody(body)
+	limit := c.options.MaxBodySize
+	if limit == 0 {
+		limit = defaultMaxBodySize
+	}
+	if limit > 0 && len(result) > limit {
+		return string(result[:limit]) + "...(truncated)", true
 	}
-	log.Printf(logResponseTemplate, body)
+	return string(result), true
+}
+
+func isJSON(header http.Header) bool {
+	mediaType, _, err := mime.ParseMediaType(header.Get("Content-Type"))
+	return err == nil && (mediaType == "application/json" || strings.HasSuffix(mediaType, "+json"))
+}
+
+func maxLevel(a, b slog.Level) slog.Level {
+	if a > b {
+		return a
+	}
+	return b
+}
+
+type attemptKey struct{}
+
+// withAttempt stores the attempt number of the request in the context, so it
+// can be logged by the transport.
+func withAttempt(ctx context.Context, attempt int) context.Context {
+	return context.WithValue(ctx, attemptKey{}, attempt)
+}
+
+func attemptFromContext(ctx context.Context) int {
+	attempt, _ := ctx.Value(attemptKey{}).(int)
+	return attempt
 }
@@ -0,0 +1,85 @@
+package management
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestLogTransport(t *testing.T) {
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("X-Request-Id", "request-id")
+		if requests == 1 {
+			w.WriteHeader(http.StatusTooManyRequests)
+			_, _ = w.Write([]byte(`{"error_message": "Too many requests"}`))
+			return
+		}
+		_, _ = w.Write([]byte(`{"user": {"email": "john@example.com", "authtoken": "secret-token"}}`))
+	}))
+	t.Cleanup(server.Close)
+
+	var output bytes.Buffer
+	client, err := NewClient(ClientConfig{
+		BaseURL:     server.URL,
+		AuthToken:   "secret-authtoken",
+		RetryPolicy: &RetryPolicy{MaxAttempts: 2, MinBackoff: time.Millisecond, MaxBackoff: time.Millisecond},
+		Logging: &LogOptions{
+			Logger:      slog.New(slog.NewJSONHandler(&output, &slog.HandlerOptions{Level: slog.LevelDebug})),
+			Level:       slog.LevelDebug,
+			Headers:     true,
+			Bodies:      true,
+			MaxBodySize: 40,
+		},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if err := client.Login(context.Background(), UserCredentials{Email: "john@example.com", Password: "hunter2"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	for _, secret := range []string{"hunter2", "secret-token", "secret-authtoken"} {
+		if strings.Contains(output.String(), secret) {
+			t.Errorf("log contains %s", secret)
+		}
+	}
+
+	lines := strings.Split(strings.TrimSpace(output.String()), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("got %d records, want 2:\n%s", len(lines), output.String())
+	}
+
+	tests := []struct {
+		level   string
+		attempt float64
+		status  float64
+	}{
+		{level: "WARN", attempt: 1, status: 429},
+		{level: "DEBUG", attempt: 2, status: 200},
+	}
+	for i, tt := range tests {
+		record := map[string]interface{}{}
+		if err := json.Unmarshal([]byte(lines[i]), &record); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if record["level"] != tt.level || record["attempt"] != tt.attempt || record["status"] != tt.status {
+			t.Errorf("record %d = %v", i, record)
+		}
+		if record["method"] != "POST" || record["path"] != "/v3/user-session" || record["request_id"] != "request-id" {
+			t.Errorf("record %d = %v", i, record)
+		}
+		if body, _ := record["response_body"].(string); i == 1 && !strings.HasSuffix(body, "...(truncated)") {
+			t.Errorf("response_body = %q, want truncated body", body)
+		}
+	}
+}
@@ -6,6 +6,8 @@ import (
 	"fmt"
 	"net/url"
 	"time"
+
+	"github.com/labd/contentstack-go-sdk/schema"
 )
 
 type ContentTypeResponse struct {
@@ -30,10 +32,11 @@ type ContentType struct {
 
 // ContentTypeInput is used to create or update a content type
 type ContentTypeInput struct {
-	Title       *string         `json:"title,omitempty"`
-	UID         *string         `json:"uid,omitempty"`
-	Description *string         `json:"description,omitempty"`
-	Schema      json.RawMessage `json:"schema,omitempty"`
+	Title       *string             `json:"title,omitempty"`
+	UID         *string             `json:"uid,omitempty"`
+	Description *string             `json:"description,omitempty"`
+	Schema      json.RawMessage     `json:"schema,omitempty"`
+	Options     *ContentTypeOptions `json:"options,omitempty"`
 }
 
 type ContentTypeOptions struct {
@@ -65,6 +68,30 @@ func (f *FlexString) UnmarshalJSON(data []byte) error {
 	return fmt.Errorf("FlexString: cannot unmarshal %s into string or bool", data)
 }
 
+// ParseSchema returns the typed schema of the content type.
+func (ct *ContentType) ParseSchema() (schema.Schema, error) {
+	return schema.Parse(ct.Schema)
+}
+
+// NewContentTypeInput validates the schema and returns the input to create or
+// update the content type.
+func NewContentTypeInput(uid string, title string, s schema.Schema, opts schema.ValidationOptions) (ContentTypeInput, error) {
+	if err := s.ValidateContentType(opts); err != nil {
+		return ContentTypeInput{}, err
+	}
+
+	raw, err := s.Raw()
+	if err != nil {
+		return ContentTypeInput{}, err
+	}
+
+	return ContentTypeInput{
+		UID:    StringRef(uid),
+		Title:  StringRef(title),
+		Schema: raw,
+	}, nil
+}
+
 func (si *StackInstance) ContentTypeCreate(ctx context.Context, input ContentTypeInput) (*ContentType, error) {
 	data, err := serializeInput(ContentTypeRequest{ContentType: input})
 	if err != nil {
@@ -156,10 +183,14 @@ func (si *StackInstance) ContentTypeFetch(ctx context.Context, uid string) (*Con
 }
 
 func (si *StackInstance) ContentTypeFetchAll(ctx context.Context) ([]ContentType, error) {
+	return fetchAll(ctx, ListOptions{}, si.ContentTypeFetchPage)
+}
+
+func (si *StackInstance) ContentTypeFetchPage(ctx context.Context, opts ListOptions) (*Page[ContentType], error) {
 	resp, err := si.client.get(
 		ctx,
 		"/v3/content_types",
-		url.Values{},
+		opts.values(),
 		si.headers(),
 	)
 	if err != nil {
@@ -168,11 +199,12 @@ func (si *StackInstance) ContentTypeFetchAll(ctx context.Context) ([]ContentType
 
 	result := struct {
 		ContentTypes []ContentType `json:"content_types"`
+		Count        int           `json:"count"`
 	}{}
 
 	if err = si.client.processResponse(resp, &result); err != nil {
 		return nil, err
 	}
 
-	return result.ContentTypes, nil
+	return newPage(result.ContentTypes, result.Count, opts), nil
 }
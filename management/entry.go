@@ -26,6 +26,9 @@ type Entry struct {
 	UpdatedBy string    `json:"updated_by"`
 	Locale    string    `json:"locale"`
 	Version   int       `json:"_version"`
+	Tags      []string  `json:"tags"`
+
+	PublishDetails PublishDetails `json:"publish_details"`
 
 	Fields map[string]interface{} `json:"-"`
 }
@@ -34,15 +37,32 @@ func (e *EntryResponse) deserialize() (*Entry, error) {
 	return deserializeEntry(e.Entry)
 }
 
+// systemFields are the properties of an entry which are not part of its
+// fields.
+var systemFields = []string{"tags", "locale", "uid", "created_by", "updated_by", "created_at", "updated_at", "ACL", "_version", "_in_progress", "publish_details"}
+
 // EntryInput is used to create or update a entry
 type EntryInput struct {
 	ContentTypeUID string `json:"-"`
 	Locale         string `json:"-"`
 	Fields         map[string]interface{}
+
+	// Tags replaces the tags of the entry. The tags are left unchanged when
+	// nil.
+	Tags []string
 }
 
 func (e *EntryInput) serialize() (json.RawMessage, url.Values, error) {
-	data, err := json.MarshalIndent(e.Fields, "", "  ")
+	fields := e.Fields
+	if e.Tags != nil {
+		fields = make(map[string]interface{}, len(e.Fields)+1)
+		for key, value := range e.Fields {
+			fields[key] = value
+		}
+		fields["tags"] = e.Tags
+	}
+
+	data, err := json.MarshalIndent(fields, "", "  ")
 	if err != nil {
 		return nil, nil, err
 	}
@@ -54,11 +74,26 @@ func (e *EntryInput) serialize() (json.RawMessage, url.Values, error) {
 	return json.RawMessage(data), params, nil
 }
 
-// EntryDeleteInput is used to delete an entry
+// EntryContextInput identifies the entry to act on
 type EntryContextInput struct {
 	ContentTypeUID string
 	Locale         string
 	UID            string
+
+	// IncludeFallback returns the entry in the fallback locale when it is
+	// not localized in the requested locale.
+	IncludeFallback bool
+}
+
+func (e *EntryContextInput) values() url.Values {
+	params := url.Values{}
+	if e.Locale != "" {
+		params.Set("locale", e.Locale)
+	}
+	if e.IncludeFallback {
+		params.Set("include_fallback", "true")
+	}
+	return params
 }
 
 func (si *StackInstance) EntryCreate(ctx context.Context, input *EntryInput) (*Entry, error) {
@@ -154,7 +189,7 @@ func (si *StackInstance) EntryFetch(ctx context.Context, input *EntryContextInpu
 	resp, err := si.client.get(
 		ctx,
 		endpoint,
-		url.Values{},
+		input.values(),
 		si.headers(),
 	)
 	if err != nil {
@@ -170,34 +205,13 @@ func (si *StackInstance) EntryFetch(ctx context.Context, input *EntryContextInpu
 }
 
 func (si *StackInstance) EntryFetchAll(ctx context.Context, contentTypeUID string) ([]Entry, error) {
-	endpoint := fmt.Sprintf("/v3/content_types/%s/entries", contentTypeUID)
-	resp, err := si.client.get(
-		ctx,
-		endpoint,
-		url.Values{},
-		si.headers(),
-	)
-	if err != nil {
-		return nil, err
-	}
-
-	response := struct {
-		Entries []json.RawMessage `json:"entries"`
-	}{}
-	if err = si.client.processResponse(resp, &response); err != nil {
-		return nil, err
-	}
-
-	result := make([]Entry, len(response.Entries))
-	for i := range response.Entries {
-		entry, err := deserializeEntry(response.Entries[i])
-		if err != nil {
-			return nil, err
-		}
-		result[i] = *entry
-	}
+	return fetchAll(ctx, ListOptions{}, func(ctx context.Context, opts ListOptions) (*Page[Entry], error) {
+		return si.EntryFetchPage(ctx, contentTypeUID, opts)
+	})
+}
 
-	return result, nil
+func (si *StackInstance) EntryFetchPage(ctx context.Context, contentTypeUID string, opts ListOptions) (*Page[Entry], error) {
+	return si.EntryFindPage(ctx, contentTypeUID, nil, opts)
 }
 
 func deserializeEntry(data json.RawMessage) (*Entry, error) {
@@ -214,8 +228,7 @@ func deserializeEntry(data json.RawMessage) (*Entry, error) {
 	}
 
 	// Delete internal fields
-	known_fields := []string{"tags", "locale", "uid", "created_by", "updated_by", "created_at", "updated_at", "ACL", "_version", "_in_progress", "publish_details"}
-	for _, field := range known_fields {
+	for _, field := range systemFields {
 		delete(result.Fields, field)
 	}
 	return result, nil
@@ -0,0 +1,104 @@
+package management
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+)
+
+// EntryLocale describes whether an entry is localized in a locale.
+type EntryLocale struct {
+	Code      string `json:"code"`
+	Localized bool   `json:"localized"`
+}
+
+// EntryLocalize creates or updates the localized version of the entry in
+// input.Locale with the given fields. The fields which are not localized are
+// inherited from the fallback locale until they are changed.
+func (si *StackInstance) EntryLocalize(ctx context.Context, input *EntryContextInput, fields map[string]interface{}) (*Entry, error) {
+	if input.Locale == "" {
+		return nil, fmt.Errorf("the locale is required to localize an entry")
+	}
+
+	data, err := json.MarshalIndent(fields, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+
+	body, err := json.MarshalIndent(&EntryRequest{
+		Entry: data,
+	}, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+
+	endpoint := fmt.Sprintf("/v3/content_types/%s/entries/%s", input.ContentTypeUID, input.UID)
+	resp, err := si.client.put(
+		ctx,
+		endpoint,
+		url.Values{"locale": []string{input.Locale}},
+		si.headers(),
+		bytes.NewReader(body),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	result := EntryResponse{}
+	if err = si.client.processResponse(resp, &result); err != nil {
+		return nil, err
+	}
+
+	return result.deserialize()
+}
+
+// EntryUnlocalize removes the localized version of the entry in input.Locale,
+// after which the entry is inherited from the fallback locale again.
+func (si *StackInstance) EntryUnlocalize(ctx context.Context, input *EntryContextInput) error {
+	if input.Locale == "" {
+		return fmt.Errorf("the locale is required to unlocalize an entry")
+	}
+
+	endpoint := fmt.Sprintf("/v3/content_types/%s/entries/%s/unlocalize", input.ContentTypeUID, input.UID)
+	resp, err := si.client.post(
+		ctx,
+		endpoint,
+		url.Values{"locale": []string{input.Locale}},
+		si.headers(),
+		nil,
+	)
+	if err != nil {
+		return err
+	}
+
+	result := struct {
+		Notice string `json:"notice"`
+	}{}
+	return si.client.processResponse(resp, &result)
+}
+
+// EntryLanguages returns the locales of the stack and whether the entry is
+// localized in each of them.
+func (si *StackInstance) EntryLanguages(ctx context.Context, input *EntryContextInput) ([]EntryLocale, error) {
+	endpoint := fmt.Sprintf("/v3/content_types/%s/entries/%s/locales", input.ContentTypeUID, input.UID)
+	resp, err := si.client.get(
+		ctx,
+		endpoint,
+		url.Values{},
+		si.headers(),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	result := struct {
+		Locales []EntryLocale `json:"locales"`
+	}{}
+	if err = si.client.processResponse(resp, &result); err != nil {
+		return nil, err
+	}
+
+	return result.Locales, nil
+}
@@ -0,0 +1,54 @@
+package management
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"testing"
+)
+
+func TestStackInstance_EntryFetchLocale(t *testing.T) {
+	var query url.Values
+	stack := newTestStack(t, func(w http.ResponseWriter, r *http.Request) {
+		query = r.URL.Query()
+		_, _ = w.Write([]byte(`{"entry": {"uid": "entry_uid", "locale": "en-us", "title": "Hello"}}`))
+	})
+
+	entry, err := stack.EntryFetch(context.Background(), &EntryContextInput{
+		ContentTypeUID:  "blog",
+		UID:             "entry_uid",
+		Locale:          "nl-nl",
+		IncludeFallback: true,
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if entry.Fields["title"] != "Hello" {
+		t.Errorf("Fields[title] = %v, want Hello", entry.Fields["title"])
+	}
+	if query.Get("locale") != "nl-nl" || query.Get("include_fallback") != "true" {
+		t.Errorf("unexpected query parameters: %v", query)
+	}
+}
+
+func TestStackInstance_EntryUnlocalize(t *testing.T) {
+	var path string
+	var query url.Values
+	stack := newTestStack(t, func(w http.ResponseWriter, r *http.Request) {
+		path = r.URL.Path
+		query = r.URL.Query()
+		_, _ = w.Write([]byte(`{"notice": "Entry unlocalized successfully."}`))
+	})
+
+	err := stack.EntryUnlocalize(context.Background(), &EntryContextInput{
+		ContentTypeUID: "blog",
+		UID:            "entry_uid",
+		Locale:         "nl-nl",
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if path != "/v3/content_types/blog/entries/entry_uid/unlocalize" || query.Get("locale") != "nl-nl" {
+		t.Errorf("unexpected request: %s?%s", path, query.Encode())
+	}
+}
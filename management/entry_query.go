@@ -0,0 +1,323 @@
+package management
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+)
+
+// EntryQuery is used to filter, project and sort entries. The conditions are
+// combined with AND and serialized to the query parameter as expected by the
+// Contentstack API.
+//
+//	q := management.NewEntryQuery().
+//		Where("category", "news").
+//		GreaterThan("rating", 3).
+//		Or(
+//			management.NewEntryQuery().Where("featured", true),
+//			management.NewEntryQuery().Exists("promoted_until", true),
+//		).
+//		Descending("updated_at")
+type EntryQuery struct {
+	conditions []map[string]interface{}
+
+	locale          string
+	includeFallback bool
+	only            []string
+	except          []string
+	references      []string
+	asc             string
+	desc            string
+}
+
+func NewEntryQuery() *EntryQuery {
+	return &EntryQuery{}
+}
+
+func (q *EntryQuery) add(field string, value interface{}) *EntryQuery {
+	q.conditions = append(q.conditions, map[string]interface{}{field: value})
+	return q
+}
+
+// Where matches entries where the field equals the value.
+func (q *EntryQuery) Where(field string, value interface{}) *EntryQuery {
+	return q.add(field, value)
+}
+
+// NotEqual matches entries where the field does not equal the value.
+func (q *EntryQuery) NotEqual(field string, value interface{}) *EntryQuery {
+	return q.add(field, map[string]interface{}{"$ne": value})
+}
+
+// In matches entries where the field equals one of the values.
+func (q *EntryQuery) In(field string, values ...interface{}) *EntryQuery {
+	return q.add(field, map[string]interface{}{"$in": values})
+}
+
+// NotIn matches entries where the field equals none of the values.
+func (q *EntryQuery) NotIn(field string, values ...interface{}) *EntryQuery {
+	return q.add(field, map[string]interface{}{"$nin": values})
+}
+
+// Exists matches entries where the field is (or is not) set.
+func (q *EntryQuery) Exists(field string, exists bool) *EntryQuery {
+	return q.add(field, map[string]interface{}{"$exists": exists})
+}
+
+// Regex matches entries where the field matches the regular expression. The
+// options are passed as is, e.g. "i" for a case insensitive match.
+func (q *EntryQuery) Regex(field string, pattern string, options string) *EntryQuery {
+	value := map[string]interface{}{"$regex": pattern}
+	if options != "" {
+		value["$options"] = options
+	}
+	return q.add(field, value)
+}
+
+func (q *EntryQuery) LessThan(field string, value interface{}) *EntryQuery {
+	return q.add(field, map[string]interface{}{"$lt": value})
+}
+
+func (q *EntryQuery) LessThanOrEqual(field string, value interface{}) *EntryQuery {
+	return q.add(field, map[string]interface{}{"$lte": value})
+}
+
+func (q *EntryQuery) GreaterThan(field string, value interface{}) *EntryQuery {
+	return q.add(field, map[string]interface{}{"$gt": value})
+}
+
+func (q *EntryQuery) GreaterThanOrEqual(field string, value interface{}) *EntryQuery {
+	return q.add(field, map[string]interface{}{"$gte": value})
+}
+
+// And matches entries matching all the given queries. Only the conditions of
+// the given queries are used.
+func (q *EntryQuery) And(queries ...*EntryQuery) *EntryQuery {
+	return q.add("$and", subQueries(queries))
+}
+
+// Or matches entries matching at least one of the given queries. Only the
+// conditions of the given queries are used.
+func (q *EntryQuery) Or(queries ...*EntryQuery) *EntryQuery {
+	return q.add("$or", subQueries(queries))
+}
+
+// ReferenceIn matches entries where the reference field refers to an entry
+// matching the given query.
+func (q *EntryQuery) ReferenceIn(field string, query *EntryQuery) *EntryQuery {
+	return q.add(field, map[string]interface{}{"$in_query": query.query()})
+}
+
+// ReferenceNotIn matches entries where the reference field does not refer to
+// an entry matching the given query.
+func (q *EntryQuery) ReferenceNotIn(field string, query *EntryQuery) *EntryQuery {
+	return q.add(field, map[string]interface{}{"$nin_query": query.query()})
+}
+
+// Tags matches entries having at least one of the given tags.
+func (q *EntryQuery) Tags(tags ...string) *EntryQuery {
+	return q.add("tags", map[string]interface{}{"$in": tags})
+}
+
+// Locale sets the locale of the entries to return.
+func (q *EntryQuery) Locale(locale string) *EntryQuery {
+	q.locale = locale
+	return q
+}
+
+// IncludeFallback returns the entry in the fallback locale when it is not
+// localized in the requested locale.
+func (q *EntryQuery) IncludeFallback() *EntryQuery {
+	q.includeFallback = true
+	return q
+}
+
+// Only limits the returned fields to the given fields.
+func (q *EntryQuery) Only(fields ...string) *EntryQuery {
+	q.only = append(q.only, fields...)
+	return q
+}
+
+// Except excludes the given fields from the returned entries.
+func (q *EntryQuery) Except(fields ...string) *EntryQuery {
+	q.except = append(q.except, fields...)
+	return q
+}
+
+// IncludeReference includes the referenced entries of the given reference
+// fields in the response.
+func (q *EntryQuery) IncludeReference(fields ...string) *EntryQuery {
+	q.references = append(q.references, fields...)
+	return q
+}
+
+// Ascending sorts the entries on the field in ascending order.
+func (q *EntryQuery) Ascending(field string) *EntryQuery {
+	q.asc = field
+	q.desc = ""
+	return q
+}
+
+// Descending sorts the entries on the field in descending order.
+func (q *EntryQuery) Descending(field string) *EntryQuery {
+	q.desc = field
+	q.asc = ""
+	return q
+}
+
+// MarshalJSON returns the query as sent in the query parameter.
+func (q *EntryQuery) MarshalJSON() ([]byte, error) {
+	return json.Marshal(q.query())
+}
+
+// query combines the conditions into a single object. When a field is used in
+// more than one condition the conditions are combined with $and.
+func (q *EntryQuery) query() map[string]interface{} {
+	result := map[string]interface{}{}
+	if q == nil {
+		return result
+	}
+
+	for _, condition := range q.conditions {
+		for field, value := range condition {
+			if _, ok := result[field]; ok {
+				return map[string]interface{}{"$and": q.conditions}
+			}
+			result[field] = value
+		}
+	}
+	return result
+}
+
+func subQueries(queries []*EntryQuery) []map[string]interface{} {
+	result := make([]map[string]interface{}, len(queries))
+	for i, query := range queries {
+		result[i] = query.query()
+	}
+	return result
+}
+
+// values adds the query parameters to the given parameters. Sorting set on the
+// query takes precedence over the sorting of the list options.
+func (q *EntryQuery) values(params url.Values) (url.Values, error) {
+	if q == nil {
+		return params, nil
+	}
+
+	if len(q.conditions) > 0 {
+		data, err := q.MarshalJSON()
+		if err != nil {
+			return nil, fmt.Errorf("Unable to serialize query: %w", err)
+		}
+		params.Set("query", string(data))
+	}
+
+	if q.locale != "" {
+		params.Set("locale", q.locale)
+	}
+	if q.includeFallback {
+		params.Set("include_fallback", "true")
+	}
+	for _, field := range q.only {
+		params.Add("only[BASE][]", field)
+	}
+	for _, field := range q.except {
+		params.Add("except[BASE][]", field)
+	}
+	for _, field := range q.references {
+		params.Add("include[]", field)
+	}
+	if q.asc != "" {
+		params.Del("desc")
+		params.Set("asc", q.asc)
+	}
+	if q.desc != "" {
+		params.Del("asc")
+		params.Set("desc", q.desc)
+	}
+	return params, nil
+}
+
+// EntryFind returns all entries of the content type matching the query.
+func (si *StackInstance) EntryFind(ctx context.Context, contentTypeUID string, query *EntryQuery) ([]Entry, error) {
+	return fetchAll(ctx, ListOptions{}, func(ctx context.Context, opts ListOptions) (*Page[Entry], error) {
+		return si.EntryFindPage(ctx, contentTypeUID, query, opts)
+	})
+}
+
+// EntryFindIterator iterates over the entries of the content type matching
+// the query.
+func (si *StackInstance) EntryFindIterator(contentTypeUID string, query *EntryQuery, opts ListOptions) *Iterator[Entry] {
+	return newIterator(opts, func(ctx context.Context, opts ListOptions) (*Page[Entry], error) {
+		return si.EntryFindPage(ctx, contentTypeUID, query, opts)
+	})
+}
+
+// EntryFindPage returns a single page of entries of the content type matching
+// the query.
+func (si *StackInstance) EntryFindPage(ctx context.Context, contentTypeUID string, query *EntryQuery, opts ListOptions) (*Page[Entry], error) {
+	params, err := query.values(opts.values())
+	if err != nil {
+		return nil, err
+	}
+
+	endpoint := fmt.Sprintf("/v3/content_types/%s/entries", contentTypeUID)
+	resp, err := si.client.get(
+		ctx,
+		endpoint,
+		params,
+		si.headers(),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	response := struct {
+		Entries []json.RawMessage `json:"entries"`
+		Count   int               `json:"count"`
+	}{}
+	if err = si.client.processResponse(resp, &response); err != nil {
+		return nil, err
+	}
+
+	result := make([]Entry, len(response.Entries))
+	for i := range response.Entries {
+		entry, err := deserializeEntry(response.Entries[i])
+		if err != nil {
+			return nil, err
+		}
+		result[i] = *entry
+	}
+
+	return newPage(result, response.Count, opts), nil
+}
+
+// EntryCount returns the number of entries of the content type matching the
+// query.
+func (si *StackInstance) EntryCount(ctx context.Context, contentTypeUID string, query *EntryQuery) (int, error) {
+	params, err := query.values(url.Values{"count": []string{"true"}})
+	if err != nil {
+		return 0, err
+	}
+
+	endpoint := fmt.Sprintf("/v3/content_types/%s/entries", contentTypeUID)
+	resp, err := si.client.get(
+		ctx,
+		endpoint,
+		params,
+		si.headers(),
+	)
+	if err != nil {
+		return 0, err
+	}
+
+	result := struct {
+		Entries int `json:"entries"`
+	}{}
+	if err = si.client.processResponse(resp, &result); err != nil {
+		return 0, err
+	}
+
+	return result.Entries, nil
+}
@@ -0,0 +1,132 @@
+package management
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"reflect"
+	"testing"
+)
+
+func TestEntryQuery_MarshalJSON(t *testing.T) {
+	tests := []struct {
+		name  string
+		query *EntryQuery
+		want  string
+	}{
+		{
+			name:  "empty",
+			query: NewEntryQuery(),
+			want:  `{}`,
+		},
+		{
+			name: "operators",
+			query: NewEntryQuery().
+				Where("category", "news").
+				In("color", "red", "blue").
+				NotIn("size", 1, 2).
+				Exists("image", true).
+				Regex("title", "^hello", "i").
+				GreaterThan("rating", 3),
+			want: `{
+				"category": "news",
+				"color": {"$in": ["red", "blue"]},
+				"size": {"$nin": [1, 2]},
+				"image": {"$exists": true},
+				"title": {"$regex": "^hello", "$options": "i"},
+				"rating": {"$gt": 3}
+			}`,
+		},
+		{
+			name: "same field used twice",
+			query: NewEntryQuery().
+				GreaterThanOrEqual("rating", 1).
+				LessThan("rating", 5),
+			want: `{"$and": [{"rating": {"$gte": 1}}, {"rating": {"$lt": 5}}]}`,
+		},
+		{
+			name: "or and references",
+			query: NewEntryQuery().
+				Or(
+					NewEntryQuery().Where("featured", true),
+					NewEntryQuery().Tags("promoted"),
+				).
+				ReferenceIn("author", NewEntryQuery().Where("name", "John")),
+			want: `{
+				"$or": [{"featured": true}, {"tags": {"$in": ["promoted"]}}],
+				"author": {"$in_query": {"name": "John"}}
+			}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data, err := json.Marshal(tt.query)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			var got, want interface{}
+			if err := json.Unmarshal(data, &got); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if err := json.Unmarshal([]byte(tt.want), &want); err != nil {
+				t.Fatalf("invalid expected json: %v", err)
+			}
+			if !reflect.DeepEqual(got, want) {
+				t.Errorf("MarshalJSON() = %s, want %s", data, tt.want)
+			}
+		})
+	}
+}
+
+func TestEntryQuery_Values(t *testing.T) {
+	query := NewEntryQuery().
+		Where("title", "Hello").
+		Locale("nl-nl").
+		IncludeFallback().
+		Only("title", "url").
+		Except("body").
+		IncludeReference("author").
+		Descending("updated_at")
+
+	params, err := query.values(ListOptions{Limit: 10, Asc: "title"}.values())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	want := url.Values{
+		"query":            []string{`{"title":"Hello"}`},
+		"include_count":    []string{"true"},
+		"limit":            []string{"10"},
+		"locale":           []string{"nl-nl"},
+		"include_fallback": []string{"true"},
+		"only[BASE][]":     []string{"title", "url"},
+		"except[BASE][]":   []string{"body"},
+		"include[]":        []string{"author"},
+		"desc":             []string{"updated_at"},
+	}
+	if !reflect.DeepEqual(params, want) {
+		t.Errorf("values() = %v, want %v", params, want)
+	}
+}
+
+func TestStackInstance_EntryCount(t *testing.T) {
+	var query url.Values
+	stack := newTestStack(t, func(w http.ResponseWriter, r *http.Request) {
+		query = r.URL.Query()
+		_, _ = w.Write([]byte(`{"entries": 42}`))
+	})
+
+	count, err := stack.EntryCount(context.Background(), "blog", NewEntryQuery().Where("title", "Hello"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if count != 42 {
+		t.Errorf("count = %d, want 42", count)
+	}
+	if query.Get("count") != "true" || query.Get("query") != `{"title":"Hello"}` {
+		t.Errorf("unexpected query parameters: %v", query)
+	}
+}
@@ -0,0 +1,63 @@
+package management
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+)
+
+func TestEntryInput_Serialize(t *testing.T) {
+	tests := []struct {
+		name  string
+		input EntryInput
+		want  map[string]interface{}
+	}{
+		{
+			name:  "without tags",
+			input: EntryInput{Fields: map[string]interface{}{"title": "Hello"}},
+			want:  map[string]interface{}{"title": "Hello"},
+		},
+		{
+			name:  "with tags",
+			input: EntryInput{Fields: map[string]interface{}{"title": "Hello"}, Tags: []string{"featured"}},
+			want:  map[string]interface{}{"title": "Hello", "tags": []interface{}{"featured"}},
+		},
+		{
+			name:  "clear tags",
+			input: EntryInput{Fields: map[string]interface{}{"title": "Hello"}, Tags: []string{}},
+			want:  map[string]interface{}{"title": "Hello", "tags": []interface{}{}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data, _, err := tt.input.serialize()
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			got := map[string]interface{}{}
+			if err := json.Unmarshal(data, &got); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("serialize() = %v, want %v", got, tt.want)
+			}
+			if _, ok := tt.input.Fields["tags"]; ok {
+				t.Error("the fields of the input are modified")
+			}
+		})
+	}
+}
+
+func TestDeserializeEntry_Tags(t *testing.T) {
+	entry, err := deserializeEntry([]byte(`{"uid": "entry_uid", "title": "Hello", "tags": ["featured"]}`))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !reflect.DeepEqual(entry.Tags, []string{"featured"}) {
+		t.Errorf("Tags = %v, want %v", entry.Tags, []string{"featured"})
+	}
+	if _, ok := entry.Fields["tags"]; ok {
+		t.Error("tags are part of the fields")
+	}
+}
@@ -0,0 +1,322 @@
+package management
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// TypedEntry is an entry of which the fields are mapped onto a Go struct using
+// its json tags. The embedded Entry contains the system metadata (UID,
+// version, locale, publish details) and the raw fields.
+type TypedEntry[T any] struct {
+	Entry
+	Data T
+}
+
+// TypedEntryInput is used to create or update an entry from a Go struct.
+type TypedEntryInput[T any] struct {
+	ContentTypeUID string
+	Locale         string
+	Data           T
+}
+
+// EntryDecodeError is returned when the fields of an entry cannot be mapped
+// onto the Go struct.
+type EntryDecodeError struct {
+	UID   string
+	Field string
+	Err   error
+}
+
+func (e *EntryDecodeError) Error() string {
+	if e.Field != "" {
+		return fmt.Sprintf("entry %s: field %q does not match the struct: %s", e.UID, e.Field, e.Err)
+	}
+	return fmt.Sprintf("entry %s does not match the struct: %s", e.UID, e.Err)
+}
+
+func (e *EntryDecodeError) Unwrap() error {
+	return e.Err
+}
+
+// EntryFetchAs fetches an entry and maps its fields onto T.
+func EntryFetchAs[T any](ctx context.Context, si *StackInstance, input *EntryContextInput) (*TypedEntry[T], error) {
+	entry, err := si.EntryFetch(ctx, input)
+	if err != nil {
+		return nil, err
+	}
+	return NewTypedEntry[T](entry)
+}
+
+// EntryFetchAllAs fetches all entries of the content type and maps their
+// fields onto T.
+func EntryFetchAllAs[T any](ctx context.Context, si *StackInstance, contentTypeUID string) ([]TypedEntry[T], error) {
+	return EntryFindAs[T](ctx, si, contentTypeUID, nil)
+}
+
+// EntryFindAs fetches all entries of the content type matching the query and
+// maps their fields onto T.
+func EntryFindAs[T any](ctx context.Context, si *StackInstance, contentTypeUID string, query *EntryQuery) ([]TypedEntry[T], error) {
+	entries, err := si.EntryFind(ctx, contentTypeUID, query)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]TypedEntry[T], len(entries))
+	for i := range entries {
+		typed, err := NewTypedEntry[T](&entries[i])
+		if err != nil {
+			return nil, err
+		}
+		result[i] = *typed
+	}
+	return result, nil
+}
+
+// EntryCreateFrom creates an entry with the fields of input.Data. The tags of
+// input.Data are set on the entry, other system fields like the uid are
+// ignored.
+func EntryCreateFrom[T any](ctx context.Context, si *StackInstance, input TypedEntryInput[T]) (*TypedEntry[T], error) {
+	fields, tags, err := encodeEntryFields(input.Data)
+	if err != nil {
+		return nil, err
+	}
+
+	entry, err := si.EntryCreate(ctx, &EntryInput{
+		ContentTypeUID: input.ContentTypeUID,
+		Locale:         input.Locale,
+		Fields:         fields,
+		Tags:           tags,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return NewTypedEntry[T](entry)
+}
+
+// EntryUpdateFrom updates the entry with the fields of input.Data.
+func EntryUpdateFrom[T any](ctx context.Context, si *StackInstance, uid string, input TypedEntryInput[T]) (*TypedEntry[T], error) {
+	fields, tags, err := encodeEntryFields(input.Data)
+	if err != nil {
+		return nil, err
+	}
+
+	entry, err := si.EntryUpdate(ctx, uid, &EntryInput{
+		ContentTypeUID: input.ContentTypeUID,
+		Locale:         input.Locale,
+		Fields:         fields,
+		Tags:           tags,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return NewTypedEntry[T](entry)
+}
+
+// NewTypedEntry maps the fields of the entry onto T. The uid, locale and tags
+// of the entry are available to T as well. An EntryDecodeError is returned
+// when:
+//   - the type of a field doesn't match the struct
+//   - the entry has a field which doesn't exist in the struct. System fields,
+//     starting with an underscore, are ignored.
+//   - a field tagged with `contentstack:"required"` is missing or null
+func NewTypedEntry[T any](entry *Entry) (*TypedEntry[T], error) {
+	result := &TypedEntry[T]{Entry: *entry}
+	typ := reflect.TypeOf(result.Data)
+
+	fields := make(map[string]interface{}, len(entry.Fields)+3)
+	for key, value := range entry.Fields {
+		fields[key] = value
+	}
+	metadata := map[string]interface{}{"uid": entry.UID, "locale": entry.Locale, "tags": entry.Tags}
+	for key, value := range metadata {
+		if _, ok := structFields(typ)[key]; ok {
+			fields[key] = value
+		}
+	}
+
+	if err := checkEntryFields(typ, fields, ""); err != nil {
+		err.UID = entry.UID
+		return nil, err
+	}
+
+	data, err := json.Marshal(fields)
+	if err != nil {
+		return nil, err
+	}
+	if err := json.NewDecoder(bytes.NewReader(data)).Decode(&result.Data); err != nil {
+		decodeErr := &EntryDecodeError{UID: entry.UID, Err: err}
+
+		var typeErr *json.UnmarshalTypeError
+		if errors.As(err, &typeErr) {
+			decodeErr.Field = typeErr.Field
+		}
+		return nil, decodeErr
+	}
+	return result, nil
+}
+
+var (
+	// ErrUnknownField is returned when the entry has a field which doesn't
+	// exist in the struct.
+	ErrUnknownField = errors.New("unknown field")
+
+	// ErrRequiredField is returned when a required field is missing.
+	ErrRequiredField = errors.New("required field is missing")
+)
+
+var unmarshalerType = reflect.TypeOf((*json.Unmarshaler)(nil)).Elem()
+
+// checkEntryFields checks the value against the struct type, descending into
+// nested structs and slices. Types with a custom UnmarshalJSON are not
+// checked.
+func checkEntryFields(typ reflect.Type, value interface{}, path string) *EntryDecodeError {
+	for typ != nil && typ.Kind() == reflect.Pointer {
+		typ = typ.Elem()
+	}
+	if typ == nil || reflect.PointerTo(typ).Implements(unmarshalerType) {
+		return nil
+	}
+
+	switch typ.Kind() {
+	case reflect.Slice, reflect.Array:
+		items, ok := value.([]interface{})
+		if !ok {
+			return nil
+		}
+		for i, item := range items {
+			if err := checkEntryFields(typ.Elem(), item, joinFieldPath(path, strconv.Itoa(i))); err != nil {
+				return err
+			}
+		}
+
+	case reflect.Struct:
+		values, ok := value.(map[string]interface{})
+		if !ok {
+			return nil
+		}
+
+		fields := structFields(typ)
+		for _, key := range sortedKeys(values) {
+			if strings.HasPrefix(key, "_") {
+				continue
+			}
+			// Match the names like encoding/json, case insensitive
+			if !hasFoldedKey(fields, key) {
+				return &EntryDecodeError{Field: joinFieldPath(path, key), Err: ErrUnknownField}
+			}
+		}
+		for _, key := range sortedKeys(fields) {
+			field := fields[key]
+			fieldValue, ok := values[key]
+			if field.Tag.Get("contentstack") == "required" && (!ok || fieldValue == nil) {
+				return &EntryDecodeError{Field: joinFieldPath(path, key), Err: ErrRequiredField}
+			}
+			if err := checkEntryFields(field.Type, fieldValue, joinFieldPath(path, key)); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// structFields returns the fields of the struct by their json name, including
+// the fields of embedded structs.
+func structFields(typ reflect.Type) map[string]reflect.StructField {
+	for typ != nil && typ.Kind() == reflect.Pointer {
+		typ = typ.Elem()
+	}
+	result := map[string]reflect.StructField{}
+	if typ == nil || typ.Kind() != reflect.Struct {
+		return result
+	}
+
+	for i := 0; i < typ.NumField(); i++ {
+		field := typ.Field(i)
+		name, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+		if name == "-" || (!field.IsExported() && !field.Anonymous) {
+			continue
+		}
+		if field.Anonymous && name == "" {
+			for key, embedded := range structFields(field.Type) {
+				if _, ok := result[key]; !ok {
+					result[key] = embedded
+				}
+			}
+			continue
+		}
+		if name == "" {
+			name = field.Name
+		}
+		result[name] = field
+	}
+	return result
+}
+
+func hasFoldedKey(fields map[string]reflect.StructField, key string) bool {
+	if _, ok := fields[key]; ok {
+		return true
+	}
+	for name := range fields {
+		if strings.EqualFold(name, key) {
+			return true
+		}
+	}
+	return false
+}
+
+func sortedKeys[V any](values map[string]V) []string {
+	keys := make([]string, 0, len(values))
+	for key := range values {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func joinFieldPath(path string, key string) string {
+	if path == "" {
+		return key
+	}
+	return path + "." + key
+}
+
+// encodeEntryFields converts the struct to the fields and the tags of an
+// entry. System fields like the uid and locale are left out, as they can't be
+// set via the fields.
+func encodeEntryFields(data interface{}) (map[string]interface{}, []string, error) {
+	encoded, err := json.Marshal(data)
+	if err != nil {
+		return nil, nil, fmt.Errorf("Unable to serialize entry: %w", err)
+	}
+
+	fields := map[string]interface{}{}
+	if err := json.Unmarshal(encoded, &fields); err != nil {
+		return nil, nil, fmt.Errorf("Unable to serialize entry, %T is not a struct or map: %w", data, err)
+	}
+
+	var tags []string
+	if value, ok := fields["tags"]; ok && value != nil {
+		list, ok := value.([]interface{})
+		if !ok {
+			return nil, nil, fmt.Errorf("Unable to serialize entry, the tags are not a list of strings")
+		}
+		tags = make([]string, len(list))
+		for i, item := range list {
+			if tags[i], ok = item.(string); !ok {
+				return nil, nil, fmt.Errorf("Unable to serialize entry, the tags are not a list of strings")
+			}
+		}
+	}
+	for _, field := range systemFields {
+		delete(fields, field)
+	}
+	return fields, tags, nil
+}
@@ -0,0 +1,183 @@
+package management
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"reflect"
+	"testing"
+)
+
+type testArticle struct {
+	UID    string   `json:"uid"`
+	Locale string   `json:"locale"`
+	Title  string   `json:"title" contentstack:"required"`
+	Rating int      `json:"rating"`
+	Tags   []string `json:"tags"`
+	SEO    *struct {
+		MetaTitle string `json:"meta_title" contentstack:"required"`
+	} `json:"seo"`
+	Sections []struct {
+		Heading string `json:"heading"`
+	} `json:"sections"`
+}
+
+func TestNewTypedEntry(t *testing.T) {
+	entry, err := deserializeEntry([]byte(`{
+		"uid": "entry_uid",
+		"locale": "en-us",
+		"_version": 4,
+		"title": "Hello",
+		"rating": 5,
+		"tags": ["a", "b"],
+		"_metadata": {"uid": "cs123"},
+		"sections": [{"heading": "Intro", "_metadata": {"uid": "cs456"}}]
+	}`))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	typed, err := NewTypedEntry[testArticle](entry)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if typed.UID != "entry_uid" || typed.Version != 4 || typed.Locale != "en-us" {
+		t.Errorf("metadata not set: %+v", typed.Entry)
+	}
+	if typed.Data.UID != "entry_uid" || typed.Data.Title != "Hello" || typed.Data.Rating != 5 || len(typed.Data.Tags) != 2 {
+		t.Errorf("Data = %+v", typed.Data)
+	}
+}
+
+func TestNewTypedEntry_Mismatch(t *testing.T) {
+	tests := []struct {
+		name      string
+		entry     string
+		wantField string
+		wantErr   error
+	}{
+		{
+			name:      "wrong type",
+			entry:     `{"uid": "entry_uid", "title": "Hello", "rating": "five"}`,
+			wantField: "rating",
+		},
+		{
+			name:      "nested wrong shape",
+			entry:     `{"uid": "entry_uid", "title": "Hello", "sections": [{"heading": {"text": "Intro"}}]}`,
+			wantField: "sections.0.heading",
+		},
+		{
+			name:      "group instead of list",
+			entry:     `{"uid": "entry_uid", "title": "Hello", "sections": {"heading": "Intro"}}`,
+			wantField: "sections",
+		},
+		{
+			name:      "unknown field",
+			entry:     `{"uid": "entry_uid", "title": "Hello", "subtitle": "World"}`,
+			wantField: "subtitle",
+			wantErr:   ErrUnknownField,
+		},
+		{
+			name:      "unknown nested field",
+			entry:     `{"uid": "entry_uid", "title": "Hello", "sections": [{"heading": "Intro"}, {"body": "Text"}]}`,
+			wantField: "sections.1.body",
+			wantErr:   ErrUnknownField,
+		},
+		{
+			name:      "missing required field",
+			entry:     `{"uid": "entry_uid", "rating": 5}`,
+			wantField: "title",
+			wantErr:   ErrRequiredField,
+		},
+		{
+			name:      "null required nested field",
+			entry:     `{"uid": "entry_uid", "title": "Hello", "seo": {"meta_title": null}}`,
+			wantField: "seo.meta_title",
+			wantErr:   ErrRequiredField,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			entry, err := deserializeEntry([]byte(tt.entry))
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			_, err = NewTypedEntry[testArticle](entry)
+			var decodeErr *EntryDecodeError
+			if !errors.As(err, &decodeErr) {
+				t.Fatalf("expected an EntryDecodeError, got %v", err)
+			}
+			if decodeErr.UID != "entry_uid" || decodeErr.Field != tt.wantField {
+				t.Errorf("error = %+v, want field %q", decodeErr, tt.wantField)
+			}
+			if tt.wantErr != nil && !errors.Is(err, tt.wantErr) {
+				t.Errorf("error = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestEntryCreateFrom(t *testing.T) {
+	var body map[string]map[string]interface{}
+	stack := newTestStack(t, func(w http.ResponseWriter, r *http.Request) {
+		_ = json.NewDecoder(r.Body).Decode(&body)
+		_, _ = w.Write([]byte(`{"entry": {"uid": "new_uid", "title": "Hello", "rating": 3}}`))
+	})
+
+	entry, err := EntryCreateFrom(context.Background(), stack, TypedEntryInput[testArticle]{
+		ContentTypeUID: "article",
+		Locale:         "en-us",
+		Data:           testArticle{UID: "old_uid", Locale: "nl-nl", Title: "Hello", Rating: 3, Tags: []string{"featured"}},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if entry.UID != "new_uid" || entry.Data.Rating != 3 {
+		t.Errorf("unexpected entry: %+v", entry)
+	}
+
+	// System fields are not sent, the tags are sent as tags of the entry
+	want := map[string]interface{}{
+		"title":    "Hello",
+		"rating":   float64(3),
+		"tags":     []interface{}{"featured"},
+		"seo":      nil,
+		"sections": nil,
+	}
+	if !reflect.DeepEqual(body["entry"], want) {
+		t.Errorf("entry = %v, want %v", body["entry"], want)
+	}
+}
+
+func TestEncodeEntryFields_Tags(t *testing.T) {
+	tests := []struct {
+		name string
+		data interface{}
+		want []string
+	}{
+		{"no tags field", struct {
+			Title string `json:"title"`
+		}{"Hello"}, nil},
+		{"nil tags", testArticle{}, nil},
+		{"empty tags", testArticle{Tags: []string{}}, []string{}},
+		{"tags", testArticle{Tags: []string{"featured"}}, []string{"featured"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fields, tags, err := encodeEntryFields(tt.data)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !reflect.DeepEqual(tags, tt.want) {
+				t.Errorf("tags = %#v, want %#v", tags, tt.want)
+			}
+			if _, ok := fields["tags"]; ok {
+				t.Error("tags are part of the fields")
+			}
+		})
+	}
+}
@@ -0,0 +1,287 @@
+package management
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"reflect"
+	"sort"
+	"strconv"
+	"time"
+)
+
+// VersionInfo describes a single version of an entry or asset.
+type VersionInfo struct {
+	Version int          `json:"_version"`
+	Name    *VersionName `json:"_version_name,omitempty"`
+}
+
+// VersionName is the name given to a version.
+type VersionName struct {
+	Title     string    `json:"title"`
+	UpdatedBy string    `json:"updated_by"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+func (si *StackInstance) EntryVersionFetchAll(ctx context.Context, input *EntryContextInput) ([]VersionInfo, error) {
+	return fetchAll(ctx, ListOptions{}, func(ctx context.Context, opts ListOptions) (*Page[VersionInfo], error) {
+		return si.EntryVersionFetchPage(ctx, input, opts)
+	})
+}
+
+func (si *StackInstance) EntryVersionFetchPage(ctx context.Context, input *EntryContextInput, opts ListOptions) (*Page[VersionInfo], error) {
+	params := opts.values()
+	if input.Locale != "" {
+		params.Set("locale", input.Locale)
+	}
+
+	endpoint := fmt.Sprintf("/v3/content_types/%s/entries/%s/versions", input.ContentTypeUID, input.UID)
+	resp, err := si.client.get(
+		ctx,
+		endpoint,
+		params,
+		si.headers(),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	result := struct {
+		Versions []VersionInfo `json:"versions"`
+		Count    int           `json:"count"`
+	}{}
+	if err = si.client.processResponse(resp, &result); err != nil {
+		return nil, err
+	}
+
+	return newPage(result.Versions, result.Count, opts), nil
+}
+
+// EntryVersionFetch returns the given version of the entry.
+func (si *StackInstance) EntryVersionFetch(ctx context.Context, input *EntryContextInput, version int) (*Entry, error) {
+	params := input.values()
+	params.Set("version", strconv.Itoa(version))
+
+	endpoint := fmt.Sprintf("/v3/content_types/%s/entries/%s", input.ContentTypeUID, input.UID)
+	resp, err := si.client.get(
+		ctx,
+		endpoint,
+		params,
+		si.headers(),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &EntryResponse{}
+	if err = si.client.processResponse(resp, &result); err != nil {
+		return nil, err
+	}
+
+	return result.deserialize()
+}
+
+// EntryVersionName gives the version of the entry a name.
+func (si *StackInstance) EntryVersionName(ctx context.Context, input *EntryContextInput, version int, name string) error {
+	entry := map[string]interface{}{
+		"_version_name": name,
+		"force":         true,
+	}
+	if input.Locale != "" {
+		entry["locale"] = input.Locale
+	}
+
+	data, err := serializeInput(map[string]interface{}{
+		"entry": entry,
+	})
+	if err != nil {
+		return err
+	}
+
+	endpoint := fmt.Sprintf("/v3/content_types/%s/entries/%s/versions/%d/name", input.ContentTypeUID, input.UID, version)
+	resp, err := si.client.post(
+		ctx,
+		endpoint,
+		url.Values{},
+		si.headers(),
+		data,
+	)
+	if err != nil {
+		return err
+	}
+
+	result := struct {
+		Notice string `json:"notice"`
+	}{}
+	return si.client.processResponse(resp, &result)
+}
+
+// EntryVersionNameDelete removes the name of the version of the entry.
+func (si *StackInstance) EntryVersionNameDelete(ctx context.Context, input *EntryContextInput, version int) error {
+	params := url.Values{}
+	if input.Locale != "" {
+		params.Set("locale", input.Locale)
+	}
+
+	endpoint := fmt.Sprintf("/v3/content_types/%s/entries/%s/versions/%d/name", input.ContentTypeUID, input.UID, version)
+	resp, err := si.client.delete(
+		ctx,
+		endpoint,
+		params,
+		si.headers(),
+		nil,
+	)
+	if err != nil {
+		return err
+	}
+
+	result := struct {
+		Notice string `json:"notice"`
+	}{}
+	return si.client.processResponse(resp, &result)
+}
+
+// EntryRollback restores the fields of the given version of the entry. This
+// creates a new version of the entry.
+func (si *StackInstance) EntryRollback(ctx context.Context, input *EntryContextInput, version int) (*Entry, error) {
+	previous, err := si.EntryVersionFetch(ctx, input, version)
+	if err != nil {
+		return nil, err
+	}
+
+	locale := input.Locale
+	if locale == "" {
+		locale = previous.Locale
+	}
+
+	// Don't copy the name of the previous version to the new version
+	fields := make(map[string]interface{}, len(previous.Fields))
+	for key, value := range previous.Fields {
+		if key != "_version_name" {
+			fields[key] = value
+		}
+	}
+
+	return si.EntryUpdate(ctx, input.UID, &EntryInput{
+		ContentTypeUID: input.ContentTypeUID,
+		Locale:         locale,
+		Fields:         fields,
+		Tags:           previous.Tags,
+	})
+}
+
+// EntryVersionDiff returns the changes to the fields between two versions of
+// the entry.
+func (si *StackInstance) EntryVersionDiff(ctx context.Context, input *EntryContextInput, from int, to int) ([]FieldChange, error) {
+	old, err := si.EntryVersionFetch(ctx, input, from)
+	if err != nil {
+		return nil, err
+	}
+
+	updated, err := si.EntryVersionFetch(ctx, input, to)
+	if err != nil {
+		return nil, err
+	}
+
+	return DiffEntries(old, updated), nil
+}
+
+type FieldChangeType string
+
+const (
+	FieldAdded    FieldChangeType = "added"
+	FieldRemoved  FieldChangeType = "removed"
+	FieldModified FieldChangeType = "modified"
+)
+
+// FieldChange describes the change of a single field between two entries.
+// Path is the location of the field, e.g. "sections[1].title".
+type FieldChange struct {
+	Path string
+	Type FieldChangeType
+	Old  interface{}
+	New  interface{}
+}
+
+func (c FieldChange) String() string {
+	switch c.Type {
+	case FieldAdded:
+		return fmt.Sprintf("+ %s: %v", c.Path, c.New)
+	case FieldRemoved:
+		return fmt.Sprintf("- %s: %v", c.Path, c.Old)
+	default:
+		return fmt.Sprintf("~ %s: %v => %v", c.Path, c.Old, c.New)
+	}
+}
+
+// DiffEntries returns the changes to the fields between the old and updated
+// entry. Nested objects and lists are compared element by element.
+func DiffEntries(old *Entry, updated *Entry) []FieldChange {
+	changes := []FieldChange{}
+	diffValues("", old.Fields, updated.Fields, &changes)
+	return changes
+}
+
+func diffValues(path string, old interface{}, updated interface{}, changes *[]FieldChange) {
+	switch o := old.(type) {
+	case map[string]interface{}:
+		if n, ok := updated.(map[string]interface{}); ok {
+			diffMaps(path, o, n, changes)
+			return
+		}
+	case []interface{}:
+		if n, ok := updated.([]interface{}); ok {
+			diffLists(path, o, n, changes)
+			return
+		}
+	}
+
+	if !reflect.DeepEqual(old, updated) {
+		*changes = append(*changes, FieldChange{Path: path, Type: FieldModified, Old: old, New: updated})
+	}
+}
+
+func diffMaps(path string, old map[string]interface{}, updated map[string]interface{}, changes *[]FieldChange) {
+	keys := make([]string, 0, len(old)+len(updated))
+	for key := range old {
+		keys = append(keys, key)
+	}
+	for key := range updated {
+		if _, ok := old[key]; !ok {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		fieldPath := key
+		if path != "" {
+			fieldPath = path + "." + key
+		}
+
+		o, inOld := old[key]
+		n, inNew := updated[key]
+		switch {
+		case !inOld:
+			*changes = append(*changes, FieldChange{Path: fieldPath, Type: FieldAdded, New: n})
+		case !inNew:
+			*changes = append(*changes, FieldChange{Path: fieldPath, Type: FieldRemoved, Old: o})
+		default:
+			diffValues(fieldPath, o, n, changes)
+		}
+	}
+}
+
+func diffLists(path string, old []interface{}, updated []interface{}, changes *[]FieldChange) {
+	for i := 0; i < len(old) || i < len(updated); i++ {
+		itemPath := fmt.Sprintf("%s[%d]", path, i)
+		switch {
+		case i >= len(old):
+			*changes = append(*changes, FieldChange{Path: itemPath, Type: FieldAdded, New: updated[i]})
+		case i >= len(updated):
+			*changes = append(*changes, FieldChange{Path: itemPath, Type: FieldRemoved, Old: old[i]})
+		default:
+			diffValues(itemPath, old[i], updated[i], changes)
+		}
+	}
+}
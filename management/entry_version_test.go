@@ -0,0 +1,116 @@
+package management
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"reflect"
+	"testing"
+)
+
+func TestDiffEntries(t *testing.T) {
+	old := &Entry{Fields: map[string]interface{}{
+		"title":  "Hello",
+		"rating": float64(3),
+		"tags":   []interface{}{"a", "b"},
+		"seo": map[string]interface{}{
+			"description": "old",
+			"keywords":    "x",
+		},
+		"removed": true,
+	}}
+	updated := &Entry{Fields: map[string]interface{}{
+		"title":  "Hello",
+		"rating": float64(4),
+		"tags":   []interface{}{"a", "c", "d"},
+		"seo": map[string]interface{}{
+			"description": "new",
+			"keywords":    "x",
+		},
+		"added": "yes",
+	}}
+
+	want := []FieldChange{
+		{Path: "added", Type: FieldAdded, New: "yes"},
+		{Path: "rating", Type: FieldModified, Old: float64(3), New: float64(4)},
+		{Path: "removed", Type: FieldRemoved, Old: true},
+		{Path: "seo.description", Type: FieldModified, Old: "old", New: "new"},
+		{Path: "tags[1]", Type: FieldModified, Old: "b", New: "c"},
+		{Path: "tags[2]", Type: FieldAdded, New: "d"},
+	}
+
+	got := DiffEntries(old, updated)
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("DiffEntries() = %v, want %v", got, want)
+	}
+
+	if changes := DiffEntries(old, old); len(changes) != 0 {
+		t.Errorf("DiffEntries() of same entry = %v, want no changes", changes)
+	}
+}
+
+func TestStackInstance_EntryVersionName(t *testing.T) {
+	tests := []struct {
+		name   string
+		locale string
+		want   map[string]interface{}
+	}{
+		{
+			name:   "with locale",
+			locale: "nl-nl",
+			want:   map[string]interface{}{"_version_name": "Launch", "force": true, "locale": "nl-nl"},
+		},
+		{
+			name: "without locale",
+			want: map[string]interface{}{"_version_name": "Launch", "force": true},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var path string
+			var body map[string]map[string]interface{}
+			stack := newTestStack(t, func(w http.ResponseWriter, r *http.Request) {
+				path = r.URL.Path
+				_ = json.NewDecoder(r.Body).Decode(&body)
+				_, _ = w.Write([]byte(`{"notice": "Version name assigned successfully."}`))
+			})
+
+			input := &EntryContextInput{ContentTypeUID: "blog", UID: "entry_uid", Locale: tt.locale}
+			if err := stack.EntryVersionName(context.Background(), input, 2, "Launch"); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if path != "/v3/content_types/blog/entries/entry_uid/versions/2/name" {
+				t.Errorf("path = %q", path)
+			}
+			if !reflect.DeepEqual(body["entry"], tt.want) {
+				t.Errorf("body = %v, want %v", body["entry"], tt.want)
+			}
+		})
+	}
+}
+
+func TestStackInstance_EntryRollback(t *testing.T) {
+	var body map[string]map[string]interface{}
+	stack := newTestStack(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodGet {
+			if version := r.URL.Query().Get("version"); version != "2" {
+				t.Errorf("version = %q, want %q", version, "2")
+			}
+			_, _ = w.Write([]byte(`{"entry": {"uid": "entry_uid", "locale": "en-us", "title": "Hello", "_version_name": "Launch", "tags": ["featured"]}}`))
+			return
+		}
+		_ = json.NewDecoder(r.Body).Decode(&body)
+		_, _ = w.Write([]byte(`{"entry": {"uid": "entry_uid", "locale": "en-us", "title": "Hello", "tags": ["featured"]}}`))
+	})
+
+	input := &EntryContextInput{ContentTypeUID: "blog", UID: "entry_uid"}
+	if _, err := stack.EntryRollback(context.Background(), input, 2); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	want := map[string]interface{}{"title": "Hello", "tags": []interface{}{"featured"}}
+	if !reflect.DeepEqual(body["entry"], want) {
+		t.Errorf("entry = %v, want %v", body["entry"], want)
+	}
+}
@@ -125,10 +125,14 @@ func (si *StackInstance) EnvironmentFetch(ctx context.Context, name string) (*En
 }
 
 func (si *StackInstance) EnvironmentFetchAll(ctx context.Context, name string) ([]Environment, error) {
+	return fetchAll(ctx, ListOptions{}, si.EnvironmentFetchPage)
+}
+
+func (si *StackInstance) EnvironmentFetchPage(ctx context.Context, opts ListOptions) (*Page[Environment], error) {
 	resp, err := si.client.get(
 		ctx,
 		"/v3/environments",
-		url.Values{},
+		opts.values(),
 		si.headers(),
 	)
 	if err != nil {
@@ -137,11 +141,12 @@ func (si *StackInstance) EnvironmentFetchAll(ctx context.Context, name string) (
 
 	result := struct {
 		Environments []Environment `json:"environments"`
+		Count        int           `json:"count"`
 	}{}
 
 	if err = si.client.processResponse(resp, &result); err != nil {
 		return nil, err
 	}
 
-	return result.Environments, nil
+	return newPage(result.Environments, result.Count, opts), nil
 }
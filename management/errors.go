@@ -0,0 +1,189 @@
+package management
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+)
+
+// Sentinel errors which can be matched against errors returned by the client
+// with errors.Is.
+var (
+	ErrUnauthorized = errors.New("not authorized")
+	ErrForbidden    = errors.New("forbidden")
+	ErrNotFound     = errors.New("resource not found")
+	ErrConflict     = errors.New("conflict")
+	ErrRateLimited  = errors.New("rate limited")
+
+	// ErrTwoFactorRequired is returned when logging in without a two-factor
+	// authentication token to an account which requires one.
+	ErrTwoFactorRequired = errors.New("two-factor authentication token required")
+)
+
+// APIError is returned for every non successful response of the Contentstack
+// API. Use errors.As to inspect the details, or errors.Is with one of the
+// sentinel errors to check for a specific class of error.
+type APIError struct {
+	// StatusCode is the HTTP status code of the response.
+	StatusCode int `json:"-"`
+
+	// ErrorMessage and ErrorCode are the message and error code as returned
+	// by Contentstack. When the response has no error code the HTTP status
+	// code is used.
+	ErrorMessage string `json:"error_message"`
+	ErrorCode    int    `json:"error_code"`
+
+	// Errors contains the validation errors per field.
+	Errors map[string][]string `json:"errors"`
+
+	Method    string `json:"-"`
+	URL       string `json:"-"`
+	RequestID string `json:"-"`
+}
+
+// ErrorMessage is the previous name of APIError.
+//
+// Deprecated: use APIError instead.
+type ErrorMessage = APIError
+
+func (e *APIError) Error() string {
+	b := strings.Builder{}
+	if e.Method != "" {
+		fmt.Fprintf(&b, "%s %s: ", e.Method, e.URL)
+	}
+
+	msg := e.ErrorMessage
+	if msg == "" {
+		msg = http.StatusText(e.StatusCode)
+	}
+	b.WriteString(msg)
+
+	if e.ErrorCode != 0 && e.ErrorCode != e.StatusCode {
+		fmt.Fprintf(&b, " (status %d, error code %d)", e.StatusCode, e.ErrorCode)
+	} else if e.StatusCode != 0 {
+		fmt.Fprintf(&b, " (status %d)", e.StatusCode)
+	}
+
+	if len(e.Errors) > 0 {
+		fields := make([]string, 0, len(e.Errors))
+		for field := range e.Errors {
+			fields = append(fields, field)
+		}
+		sort.Strings(fields)
+
+		for _, field := range fields {
+			fmt.Fprintf(&b, "; %s: %s", field, strings.Join(e.Errors[field], ", "))
+		}
+	}
+
+	return b.String()
+}
+
+// Is reports whether the error matches one of the sentinel errors based on
+// the HTTP status code.
+func (e *APIError) Is(target error) bool {
+	switch target {
+	case ErrUnauthorized:
+		return e.StatusCode == http.StatusUnauthorized
+	case ErrForbidden:
+		return e.StatusCode == http.StatusForbidden
+	case ErrNotFound:
+		return e.StatusCode == http.StatusNotFound
+	case ErrConflict:
+		return e.StatusCode == http.StatusConflict
+	case ErrRateLimited:
+		return e.StatusCode == http.StatusTooManyRequests
+	case ErrTwoFactorRequired:
+		return e.StatusCode == statusTwoFactorRequired
+	}
+	return false
+}
+
+// newAPIError creates an APIError from the response. The body is parsed
+// leniently since Contentstack doesn't always return a consistent structure
+// for the errors, e.g. {"error_message":"xx","error_code":115,"errors":[{}]}
+func newAPIError(r *http.Response, content []byte) *APIError {
+	result := &APIError{
+		StatusCode: r.StatusCode,
+	}
+
+	if r.Request != nil {
+		result.Method = r.Request.Method
+		result.URL = r.Request.URL.Redacted()
+	}
+
+	result.RequestID = requestID(r.Header)
+
+	body := struct {
+		ErrorMessage string          `json:"error_message"`
+		ErrorCode    json.Number     `json:"error_code"`
+		Errors       json.RawMessage `json:"errors"`
+	}{}
+	if err := json.Unmarshal(content, &body); err == nil {
+		result.ErrorMessage = body.ErrorMessage
+		if code, err := body.ErrorCode.Int64(); err == nil {
+			result.ErrorCode = int(code)
+		}
+		result.Errors = parseFieldErrors(body.Errors)
+	}
+
+	// Fall back to the status code when the body has no error code
+	if result.ErrorCode == 0 {
+		result.ErrorCode = r.StatusCode
+	}
+
+	return result
+}
+
+// parseFieldErrors converts the errors returned by Contentstack to a map of
+// field names to messages.
+func parseFieldErrors(data json.RawMessage) map[string][]string {
+	if len(data) == 0 {
+		return nil
+	}
+
+	strict := map[string][]string{}
+	if err := json.Unmarshal(data, &strict); err == nil {
+		if len(strict) == 0 {
+			return nil
+		}
+		return strict
+	}
+
+	loose := map[string]interface{}{}
+	if err := json.Unmarshal(data, &loose); err != nil {
+		return nil
+	}
+
+	result := map[string][]string{}
+	for field, value := range loose {
+		switch v := value.(type) {
+		case string:
+			result[field] = []string{v}
+		case []interface{}:
+			for _, item := range v {
+				result[field] = append(result[field], fmt.Sprint(item))
+			}
+		default:
+			encoded, _ := json.Marshal(v)
+			result[field] = []string{string(encoded)}
+		}
+	}
+	if len(result) == 0 {
+		return nil
+	}
+	return result
+}
+
+// requestID returns the ID Contentstack assigned to the request.
+func requestID(header http.Header) string {
+	for _, h := range []string{"X-Request-Id", "X-Contentstack-Request-Id"} {
+		if v := header.Get(h); v != "" {
+			return v
+		}
+	}
+	return ""
+}
@@ -0,0 +1,152 @@
+package management
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func newTestResponse(status int, body string) *http.Response {
+	req, _ := http.NewRequest(http.MethodGet, "https://api.contentstack.io/v3/content_types/blog", nil)
+	return &http.Response{
+		StatusCode: status,
+		Header:     http.Header{"X-Request-Id": []string{"req-1"}},
+		Body:       io.NopCloser(strings.NewReader(body)),
+		Request:    req,
+	}
+}
+
+func TestClient_ProcessResponseErrors(t *testing.T) {
+	tests := []struct {
+		name       string
+		status     int
+		body       string
+		sentinel   error
+		wantCode   int
+		wantErrors map[string][]string
+	}{
+		{
+			name:     "not found",
+			status:   404,
+			body:     `{"error_message":"The Content Type 'blog' was not found.","error_code":118}`,
+			sentinel: ErrNotFound,
+			wantCode: 118,
+		},
+		{
+			name:     "unauthorized",
+			status:   401,
+			body:     `{"error_message":"You're not allowed in here unless you're logged in.","error_code":105}`,
+			sentinel: ErrUnauthorized,
+			wantCode: 105,
+		},
+		{
+			name:     "not found without body",
+			status:   404,
+			body:     ``,
+			sentinel: ErrNotFound,
+			wantCode: 404,
+		},
+		{
+			name:     "forbidden without body",
+			status:   403,
+			body:     ``,
+			sentinel: ErrForbidden,
+			wantCode: 403,
+		},
+		{
+			name:     "rate limited",
+			status:   429,
+			body:     `{"error_message":"Too many requests","error_code":429}`,
+			sentinel: ErrRateLimited,
+			wantCode: 429,
+		},
+		{
+			name:     "conflict",
+			status:   409,
+			body:     `{"error_message":"Conflict","error_code":409}`,
+			sentinel: ErrConflict,
+			wantCode: 409,
+		},
+		{
+			name:       "validation errors",
+			status:     422,
+			body:       `{"error_message":"Content Type creation failed.","error_code":115,"errors":{"uid":["is not unique."]}}`,
+			wantCode:   115,
+			wantErrors: map[string][]string{"uid": {"is not unique."}},
+		},
+		{
+			name:     "invalid errors structure",
+			status:   422,
+			body:     `{"error_message":"xx","error_code":115,"errors":[{}]}`,
+			wantCode: 115,
+		},
+		{
+			name:       "nested errors structure",
+			status:     422,
+			body:       `{"error_message":"xx","error_code":119,"errors":{"title":"is required","schema":{"uid":"missing"}}}`,
+			wantCode:   119,
+			wantErrors: map[string][]string{"title": {"is required"}, "schema": {`{"uid":"missing"}`}},
+		},
+		{
+			name:     "server error",
+			status:   503,
+			body:     `<html>Service Unavailable</html>`,
+			wantCode: 503,
+		},
+	}
+
+	client := &Client{}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := client.processResponse(newTestResponse(tt.status, tt.body), nil)
+			if err == nil {
+				t.Fatal("expected an error")
+			}
+
+			var apiErr *APIError
+			if !errors.As(err, &apiErr) {
+				t.Fatalf("expected an APIError, got %T", err)
+			}
+			if apiErr.StatusCode != tt.status {
+				t.Errorf("StatusCode = %d, want %d", apiErr.StatusCode, tt.status)
+			}
+			if apiErr.ErrorCode != tt.wantCode {
+				t.Errorf("ErrorCode = %d, want %d", apiErr.ErrorCode, tt.wantCode)
+			}
+			if apiErr.Method != http.MethodGet || apiErr.RequestID != "req-1" {
+				t.Errorf("request details not set: %+v", apiErr)
+			}
+			if !reflect.DeepEqual(apiErr.Errors, tt.wantErrors) {
+				t.Errorf("Errors = %v, want %v", apiErr.Errors, tt.wantErrors)
+			}
+			if tt.sentinel != nil && !errors.Is(err, tt.sentinel) {
+				t.Errorf("errors.Is(%v) = false, want true", tt.sentinel)
+			}
+			if tt.sentinel != ErrNotFound && errors.Is(err, ErrNotFound) {
+				t.Error("errors.Is(ErrNotFound) = true, want false")
+			}
+		})
+	}
+}
+
+func TestClient_ProcessResponseSuccess(t *testing.T) {
+	client := &Client{}
+
+	result := struct {
+		Notice string `json:"notice"`
+	}{}
+	err := client.processResponse(newTestResponse(200, `{"notice":"ok"}`), &result)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result.Notice != "ok" {
+		t.Errorf("Notice = %q, want %q", result.Notice, "ok")
+	}
+
+	if err := client.processResponse(newTestResponse(204, ``), &result); err != nil {
+		t.Errorf("unexpected error for empty body: %v", err)
+	}
+}
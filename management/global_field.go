@@ -6,6 +6,8 @@ import (
 	"fmt"
 	"net/url"
 	"time"
+
+	"github.com/labd/contentstack-go-sdk/schema"
 )
 
 type GlobalFieldResponse struct {
@@ -36,6 +38,30 @@ type GlobalFieldInput struct {
 	Schema            json.RawMessage `json:"schema,omitempty"`
 }
 
+// ParseSchema returns the typed schema of the global field.
+func (gf *GlobalField) ParseSchema() (schema.Schema, error) {
+	return schema.Parse(gf.Schema)
+}
+
+// NewGlobalFieldInput validates the schema and returns the input to create or
+// update the global field.
+func NewGlobalFieldInput(uid string, title string, s schema.Schema, opts schema.ValidationOptions) (GlobalFieldInput, error) {
+	if err := s.ValidateGlobalField(opts); err != nil {
+		return GlobalFieldInput{}, err
+	}
+
+	raw, err := s.Raw()
+	if err != nil {
+		return GlobalFieldInput{}, err
+	}
+
+	return GlobalFieldInput{
+		UID:    StringRef(uid),
+		Title:  StringRef(title),
+		Schema: raw,
+	}, nil
+}
+
 func (si *StackInstance) GlobalFieldCreate(ctx context.Context, input GlobalFieldInput) (*GlobalField, error) {
 	data, err := serializeInput(GlobalFieldRequest{GlobalField: input})
 	if err != nil {
@@ -127,10 +153,14 @@ func (si *StackInstance) GlobalFieldFetch(ctx context.Context, uid string) (*Glo
 }
 
 func (si *StackInstance) GlobalFieldFetchAll(ctx context.Context) ([]GlobalField, error) {
+	return fetchAll(ctx, ListOptions{}, si.GlobalFieldFetchPage)
+}
+
+func (si *StackInstance) GlobalFieldFetchPage(ctx context.Context, opts ListOptions) (*Page[GlobalField], error) {
 	resp, err := si.client.get(
 		ctx,
 		"/v3/global_fields",
-		url.Values{},
+		opts.values(),
 		si.headers(),
 	)
 	if err != nil {
@@ -139,11 +169,12 @@ func (si *StackInstance) GlobalFieldFetchAll(ctx context.Context) ([]GlobalField
 
 	result := struct {
 		GlobalFields []GlobalField `json:"global_fields"`
+		Count        int           `json:"count"`
 	}{}
 
 	if err = si.client.processResponse(resp, &result); err != nil {
 		return nil, err
 	}
 
-	return result.GlobalFields, nil
+	return newPage(result.GlobalFields, result.Count, opts), nil
 }
@@ -0,0 +1,125 @@
+package management
+
+import (
+	"context"
+)
+
+// Iterator iterates over the items of a collection, fetching the pages lazily
+// when they are needed. Iteration can be stopped at any time by no longer
+// calling Next.
+//
+//	it := stack.EntryIterator("blog", management.ListOptions{})
+//	for it.Next(ctx) {
+//		entry := it.Value()
+//		...
+//	}
+//	if err := it.Err(); err != nil {
+//		...
+//	}
+type Iterator[T any] struct {
+	fetch pageFetcher[T]
+	opts  ListOptions
+
+	items   []T
+	index   int
+	current T
+	count   int
+	done    bool
+	err     error
+}
+
+func newIterator[T any](opts ListOptions, fetch pageFetcher[T]) *Iterator[T] {
+	if opts.Limit <= 0 || opts.Limit > maxPageSize {
+		opts.Limit = maxPageSize
+	}
+	return &Iterator[T]{
+		fetch: fetch,
+		opts:  opts,
+	}
+}
+
+// Next advances the iterator to the next item, fetching the next page when
+// needed. It returns false when there are no more items, when fetching a page
+// failed or when the context is done.
+func (it *Iterator[T]) Next(ctx context.Context) bool {
+	if it.err != nil {
+		return false
+	}
+	if err := ctx.Err(); err != nil {
+		it.err = err
+		return false
+	}
+
+	for it.index >= len(it.items) {
+		if it.done {
+			return false
+		}
+
+		page, err := it.fetch(ctx, it.opts)
+		if err != nil {
+			it.err = err
+			return false
+		}
+
+		it.items = page.Items
+		it.index = 0
+		it.count = page.Count
+		it.opts.Skip += len(page.Items)
+		it.done = !page.HasMore()
+	}
+
+	it.current = it.items[it.index]
+	it.index++
+	return true
+}
+
+// Value returns the current item.
+func (it *Iterator[T]) Value() T {
+	return it.current
+}
+
+// Err returns the error which stopped the iteration, if any.
+func (it *Iterator[T]) Err() error {
+	return it.err
+}
+
+// Count returns the total number of items in the collection as reported by
+// the last fetched page.
+func (it *Iterator[T]) Count() int {
+	return it.count
+}
+
+func (si *StackInstance) EntryIterator(contentTypeUID string, opts ListOptions) *Iterator[Entry] {
+	return newIterator(opts, func(ctx context.Context, opts ListOptions) (*Page[Entry], error) {
+		return si.EntryFetchPage(ctx, contentTypeUID, opts)
+	})
+}
+
+func (si *StackInstance) ContentTypeIterator(opts ListOptions) *Iterator[ContentType] {
+	return newIterator(opts, si.ContentTypeFetchPage)
+}
+
+func (si *StackInstance) GlobalFieldIterator(opts ListOptions) *Iterator[GlobalField] {
+	return newIterator(opts, si.GlobalFieldFetchPage)
+}
+
+func (si *StackInstance) WebHookIterator(opts ListOptions) *Iterator[WebHook] {
+	return newIterator(opts, si.WebHookFetchPage)
+}
+
+func (si *StackInstance) LocaleIterator(opts ListOptions) *Iterator[Locale] {
+	return newIterator(opts, si.LocaleFetchPage)
+}
+
+func (si *StackInstance) EnvironmentIterator(opts ListOptions) *Iterator[Environment] {
+	return newIterator(opts, si.EnvironmentFetchPage)
+}
+
+// StackIterator iterates over all stacks, starting at input.Skip.
+func (c *Client) StackIterator(input StacksInput) *Iterator[Stack] {
+	return newIterator(input.listOptions(), func(ctx context.Context, opts ListOptions) (*Page[Stack], error) {
+		input.Limit = opts.Limit
+		input.Skip = opts.Skip
+		return c.StacksPage(ctx, input)
+	})
+}
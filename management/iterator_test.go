@@ -0,0 +1,69 @@
+package management
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"sync/atomic"
+	"testing"
+)
+
+func TestIterator_FetchesPagesLazily(t *testing.T) {
+	var requests int32
+	handler := pagedHandler(t, "entries", 250)
+	stack := newTestStack(t, func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&requests, 1)
+		handler(w, r)
+	})
+
+	ctx := context.Background()
+	it := stack.EntryIterator("blog", ListOptions{Limit: 50})
+
+	seen := 0
+	for it.Next(ctx) {
+		seen++
+		if seen == 60 {
+			break
+		}
+	}
+	if err := it.Err(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if it.Value().UID != "item_59" {
+		t.Errorf("Value().UID = %q, want %q", it.Value().UID, "item_59")
+	}
+	if it.Count() != 250 {
+		t.Errorf("Count() = %d, want 250", it.Count())
+	}
+	if n := atomic.LoadInt32(&requests); n != 2 {
+		t.Errorf("requests = %d, want 2", n)
+	}
+
+	for it.Next(ctx) {
+		seen++
+	}
+	if seen != 250 {
+		t.Errorf("seen = %d, want 250", seen)
+	}
+	if n := atomic.LoadInt32(&requests); n != 5 {
+		t.Errorf("requests = %d, want 5", n)
+	}
+}
+
+func TestIterator_ContextCanceled(t *testing.T) {
+	stack := newTestStack(t, pagedHandler(t, "webhooks", 10))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	it := stack.WebHookIterator(ListOptions{})
+	if !it.Next(ctx) {
+		t.Fatalf("Next() = false, err: %v", it.Err())
+	}
+
+	cancel()
+	if it.Next(ctx) {
+		t.Error("Next() = true after cancel, want false")
+	}
+	if !errors.Is(it.Err(), context.Canceled) {
+		t.Errorf("Err() = %v, want context.Canceled", it.Err())
+	}
+}
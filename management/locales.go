@@ -123,10 +123,14 @@ func (si *StackInstance) LocaleFetch(ctx context.Context, code string) (*Locale,
 }
 
 func (si *StackInstance) LocaleFetchAll(ctx context.Context) ([]Locale, error) {
+	return fetchAll(ctx, ListOptions{}, si.LocaleFetchPage)
+}
+
+func (si *StackInstance) LocaleFetchPage(ctx context.Context, opts ListOptions) (*Page[Locale], error) {
 	resp, err := si.client.get(
 		ctx,
 		"/v3/locales",
-		url.Values{},
+		opts.values(),
 		si.headers(),
 	)
 	if err != nil {
@@ -135,11 +139,12 @@ func (si *StackInstance) LocaleFetchAll(ctx context.Context) ([]Locale, error) {
 
 	result := struct {
 		Locales []Locale `json:"locales"`
+		Count   int      `json:"count"`
 	}{}
 
 	if err = si.client.processResponse(resp, &result); err != nil {
 		return nil, err
 	}
 
-	return result.Locales, nil
+	return newPage(result.Locales, result.Count, opts), nil
 }
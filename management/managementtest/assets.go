@@ -0,0 +1,325 @@
+package managementtest
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"net/url"
+	"path"
+	"strconv"
+	"strings"
+)
+
+// maxUploadSize is the maximum size of an uploaded asset kept in memory.
+const maxUploadSize = 32 << 20
+
+// assets handles the endpoints below /assets.
+func (s *Server) assets(r *request) {
+	if len(r.path) > 1 && r.path[1] == "folders" {
+		s.assetFolders(r)
+		return
+	}
+
+	if len(r.path) == 1 {
+		switch r.Method {
+		case http.MethodGet:
+			s.listAssets(r)
+		case http.MethodPost:
+			s.saveAsset(r, nil)
+		default:
+			methodNotAllowed(r.w)
+		}
+		return
+	}
+	if len(r.path) != 2 {
+		notFound(r.w)
+		return
+	}
+
+	doc, ok := r.stack.assets.get(r.path[1])
+	if !ok || doc["is_dir"] == true {
+		assetNotFound(r.w)
+		return
+	}
+
+	switch r.Method {
+	case http.MethodGet:
+		writeJSON(r.w, http.StatusOK, document{"asset": doc})
+	case http.MethodPut:
+		s.saveAsset(r, doc)
+	case http.MethodDelete:
+		r.stack.assets.remove(r.path[1])
+		delete(r.stack.files, r.path[1])
+		writeJSON(r.w, http.StatusOK, document{"notice": "Asset deleted successfully."})
+	default:
+		methodNotAllowed(r.w)
+	}
+}
+
+func assetNotFound(w http.ResponseWriter) {
+	writeError(w, http.StatusUnprocessableEntity, 145, "Asset was not found.", nil)
+}
+
+func folderNotFound(w http.ResponseWriter) {
+	writeError(w, http.StatusUnprocessableEntity, 145, "Folder was not found.", nil)
+}
+
+// listAssets lists the assets, honoring the folder, include_folders and query
+// parameters. All assets of the stack are listed when no folder is given.
+func (s *Server) listAssets(r *request) {
+	params := r.URL.Query()
+
+	query := document{}
+	if raw := params.Get("query"); raw != "" {
+		if err := json.Unmarshal([]byte(raw), &query); err != nil {
+			writeError(r.w, http.StatusBadRequest, 141, "The query is not valid JSON.", nil)
+			return
+		}
+	}
+
+	folder := params.Get("folder")
+	folders := params.Get("include_folders") == "true"
+	docs := []document{}
+	for _, doc := range r.stack.assets.list() {
+		if doc["is_dir"] == true && !folders {
+			continue
+		}
+		if parent, _ := doc["parent_uid"].(string); folder != "" && parent != folder {
+			continue
+		}
+		matched, err := match(doc, query)
+		if err != nil {
+			writeError(r.w, http.StatusBadRequest, 141, err.Error(), nil)
+			return
+		}
+		if matched {
+			docs = append(docs, doc)
+		}
+	}
+	writeList(r, "assets", docs)
+}
+
+// saveAsset creates a new asset when doc is nil, or replaces the file and the
+// details of the asset.
+func (s *Server) saveAsset(r *request, doc document) {
+	action := "creation"
+	if doc != nil {
+		action = "update"
+	}
+	failed := fmt.Sprintf("Asset %s failed. Please try again.", action)
+
+	if err := r.ParseMultipartForm(maxUploadSize); err != nil {
+		writeError(r.w, http.StatusBadRequest, 400, "Bad Request. Please provide a multipart body.", nil)
+		return
+	}
+	file, header, err := r.FormFile("asset[upload]")
+	if err != nil {
+		writeError(r.w, http.StatusUnprocessableEntity, 145, failed, map[string]interface{}{
+			"upload": []string{"is a required field."},
+		})
+		return
+	}
+	defer file.Close()
+	content, err := io.ReadAll(file)
+	if err != nil {
+		writeError(r.w, http.StatusBadRequest, 400, "Bad Request. Unable to read the upload.", nil)
+		return
+	}
+
+	parent := r.FormValue("asset[parent_uid]")
+	if parent != "" {
+		if folder, ok := r.stack.assets.get(parent); !ok || folder["is_dir"] != true {
+			folderNotFound(r.w)
+			return
+		}
+	}
+
+	now := s.now()
+	if doc == nil {
+		doc = document{
+			"uid":        s.newUID(),
+			"created_at": now,
+			"created_by": "",
+			"is_dir":     false,
+			"_version":   0,
+		}
+	}
+	doc = clone(doc).(document)
+
+	uid := doc["uid"].(string)
+	contentType := mime.TypeByExtension(path.Ext(header.Filename))
+	if contentType == "" {
+		contentType = "application/octet-stream"
+	}
+	tags := []interface{}{}
+	for _, tag := range strings.Split(r.FormValue("asset[tags]"), ",") {
+		if tag != "" {
+			tags = append(tags, tag)
+		}
+	}
+
+	doc["filename"] = header.Filename
+	doc["title"] = header.Filename
+	if title := r.FormValue("asset[title]"); title != "" {
+		doc["title"] = title
+	}
+	doc["description"] = r.FormValue("asset[description]")
+	doc["tags"] = tags
+	doc["parent_uid"] = nil
+	if parent != "" {
+		doc["parent_uid"] = parent
+	}
+	doc["content_type"] = contentType
+	doc["file_size"] = strconv.Itoa(len(content))
+	doc["url"] = fmt.Sprintf("%s/v3/assets/%s/%s/%s/%s", s.URL, r.stack.apiKey, uid, s.newUID(), url.PathEscape(header.Filename))
+	doc["updated_at"] = now
+	doc["updated_by"] = ""
+	doc["_version"] = doc["_version"].(float64) + 1
+
+	r.stack.assets.put(uid, doc)
+	r.stack.files[uid] = content
+
+	status := http.StatusOK
+	if action == "creation" {
+		status = http.StatusCreated
+	}
+	writeJSON(r.w, status, document{
+		"notice": fmt.Sprintf("Asset %s successfully.", map[string]string{"creation": "created", "update": "updated"}[action]),
+		"asset":  doc,
+	})
+}
+
+// downloadAsset serves the content of an asset from its url, which like the
+// CDN doesn't require credentials.
+func (s *Server) downloadAsset(r *request) {
+	if r.Method != http.MethodGet {
+		methodNotAllowed(r.w)
+		return
+	}
+
+	apiKey, uid := r.path[1], r.path[2]
+	for _, st := range s.stacks {
+		if st.apiKey != apiKey {
+			continue
+		}
+		doc, ok := st.assets.get(uid)
+		if !ok || doc["url"] != s.URL+r.URL.EscapedPath() {
+			break
+		}
+
+		r.w.Header().Set("Content-Type", doc["content_type"].(string))
+		r.w.WriteHeader(http.StatusOK)
+		_, _ = r.w.Write(st.files[uid])
+		return
+	}
+	notFound(r.w)
+}
+
+// assetFolders handles the endpoints below /assets/folders.
+func (s *Server) assetFolders(r *request) {
+	if len(r.path) == 2 {
+		if r.Method != http.MethodPost {
+			methodNotAllowed(r.w)
+			return
+		}
+		s.saveAssetFolder(r, nil)
+		return
+	}
+	if len(r.path) != 3 {
+		notFound(r.w)
+		return
+	}
+
+	doc, ok := r.stack.assets.get(r.path[2])
+	if !ok || doc["is_dir"] != true {
+		folderNotFound(r.w)
+		return
+	}
+
+	switch r.Method {
+	case http.MethodGet:
+		writeJSON(r.w, http.StatusOK, document{"asset": doc})
+	case http.MethodPut:
+		s.saveAssetFolder(r, doc)
+	case http.MethodDelete:
+		s.deleteAssetFolder(r.stack, r.path[2])
+		writeJSON(r.w, http.StatusOK, document{"notice": "Folder deleted successfully."})
+	default:
+		methodNotAllowed(r.w)
+	}
+}
+
+// saveAssetFolder creates a new folder when doc is nil, or renames and/or
+// moves the folder.
+func (s *Server) saveAssetFolder(r *request, doc document) {
+	body := map[string]document{}
+	if !decode(r, &body) {
+		return
+	}
+	input := body["asset"]
+
+	name, _ := input["name"].(string)
+	if doc == nil && name == "" {
+		writeError(r.w, http.StatusUnprocessableEntity, 145, "Folder creation failed. Please try again.", map[string]interface{}{
+			"name": []string{"is a required field."},
+		})
+		return
+	}
+	parent, _ := input["parent_uid"].(string)
+	if parent != "" {
+		if folder, ok := r.stack.assets.get(parent); !ok || folder["is_dir"] != true {
+			folderNotFound(r.w)
+			return
+		}
+	}
+
+	now := s.now()
+	status := http.StatusOK
+	if doc == nil {
+		status = http.StatusCreated
+		doc = document{
+			"uid":        s.newUID(),
+			"created_at": now,
+			"created_by": "",
+			"is_dir":     true,
+			"parent_uid": nil,
+			"_version":   1,
+		}
+	}
+	doc = clone(doc).(document)
+	if name != "" {
+		doc["name"] = name
+	}
+	if parent != "" {
+		doc["parent_uid"] = parent
+	} else if value, ok := input["parent_uid"]; ok && value == nil {
+		// A null parent moves the folder to the root
+		doc["parent_uid"] = nil
+	}
+	doc["updated_at"] = now
+	doc["updated_by"] = ""
+
+	r.stack.assets.put(doc["uid"].(string), doc)
+	writeJSON(r.w, status, document{
+		"notice": "Folder saved successfully.",
+		"asset":  doc,
+	})
+}
+
+// deleteAssetFolder deletes the folder including its content.
+func (s *Server) deleteAssetFolder(st *stack, uid string) {
+	for _, doc := range st.assets.list() {
+		if parent, _ := doc["parent_uid"].(string); parent == uid {
+			child := doc["uid"].(string)
+			if doc["is_dir"] == true {
+				s.deleteAssetFolder(st, child)
+			} else {
+				st.assets.remove(child)
+				delete(st.files, child)
+			}
+		}
+	}
+	st.assets.remove(uid)
+}
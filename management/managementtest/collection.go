@@ -0,0 +1,112 @@
+package managementtest
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+)
+
+// document is a JSON object as stored and returned by the server.
+type document = map[string]interface{}
+
+// collection is an ordered set of documents.
+type collection struct {
+	keys []string
+	docs map[string]document
+}
+
+func newCollection() *collection {
+	return &collection{docs: map[string]document{}}
+}
+
+func (c *collection) get(key string) (document, bool) {
+	doc, ok := c.docs[key]
+	return doc, ok
+}
+
+func (c *collection) put(key string, doc document) {
+	if _, ok := c.docs[key]; !ok {
+		c.keys = append(c.keys, key)
+	}
+	c.docs[key] = doc
+}
+
+func (c *collection) remove(key string) {
+	if _, ok := c.docs[key]; !ok {
+		return
+	}
+	delete(c.docs, key)
+	for i, k := range c.keys {
+		if k == key {
+			c.keys = append(c.keys[:i], c.keys[i+1:]...)
+			break
+		}
+	}
+}
+
+func (c *collection) list() []document {
+	result := make([]document, len(c.keys))
+	for i, key := range c.keys {
+		result[i] = c.docs[key]
+	}
+	return result
+}
+
+// clone returns a deep copy of the value, so stored documents are never
+// shared with the caller.
+func clone(v interface{}) interface{} {
+	data, err := json.Marshal(v)
+	if err != nil {
+		panic(err)
+	}
+	var result interface{}
+	if err := json.Unmarshal(data, &result); err != nil {
+		panic(err)
+	}
+	return result
+}
+
+// maxLimit is the maximum number of items returned per request.
+const maxLimit = 100
+
+// writeList writes a page of the documents, honoring the asc, desc, skip,
+// limit and include_count parameters.
+func writeList(r *request, key string, docs []document) {
+	params := r.URL.Query()
+
+	docs = append([]document{}, docs...)
+	if field := params.Get("asc"); field != "" {
+		sort.SliceStable(docs, func(i, j int) bool { return less(docs[i][field], docs[j][field]) })
+	}
+	if field := params.Get("desc"); field != "" {
+		sort.SliceStable(docs, func(i, j int) bool { return less(docs[j][field], docs[i][field]) })
+	}
+
+	skip, _ := strconv.Atoi(params.Get("skip"))
+	limit, _ := strconv.Atoi(params.Get("limit"))
+	if limit <= 0 || limit > maxLimit {
+		limit = maxLimit
+	}
+
+	page := []document{}
+	for i := skip; i < len(docs) && i < skip+limit; i++ {
+		page = append(page, docs[i])
+	}
+
+	result := document{key: page}
+	if params.Get("include_count") == "true" {
+		result["count"] = len(docs)
+	}
+	writeJSON(r.w, http.StatusOK, result)
+}
+
+func less(a interface{}, b interface{}) bool {
+	fa, okA := a.(float64)
+	fb, okB := b.(float64)
+	if okA && okB {
+		return fa < fb
+	}
+	return fmt.Sprint(a) < fmt.Sprint(b)
+}
@@ -0,0 +1,295 @@
+package managementtest
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+type entryCollection struct {
+	keys    []string
+	entries map[string]*entry
+}
+
+type entry struct {
+	uid       string
+	version   int
+	createdAt string
+
+	// locales contains the localized versions of the entry.
+	locales map[string]document
+}
+
+// systemFields are set by the server and can't be changed.
+var systemFields = []string{"uid", "locale", "_version", "created_at", "updated_at", "created_by", "updated_by", "publish_details", "ACL", "_in_progress"}
+
+// entries handles the endpoints below /content_types/{uid}/entries.
+func (s *Server) entries(r *request) {
+	contentTypeUID := r.path[1]
+	contentType, ok := r.stack.contentTypes.get(contentTypeUID)
+	if !ok {
+		contentTypeResource.notFound(r.w, contentTypeUID)
+		return
+	}
+
+	items, ok := r.stack.entries[contentTypeUID]
+	if !ok {
+		items = &entryCollection{entries: map[string]*entry{}}
+		r.stack.entries[contentTypeUID] = items
+	}
+
+	locale := r.URL.Query().Get("locale")
+	if locale == "" {
+		locale = r.stack.masterLocale
+	}
+	if _, ok := r.stack.locales.get(locale); !ok {
+		localeResource.notFound(r.w, locale)
+		return
+	}
+
+	if len(r.path) == 3 {
+		switch r.Method {
+		case http.MethodGet:
+			s.listEntries(r, items, locale)
+		case http.MethodPost:
+			s.saveEntry(r, contentType, items, nil, locale)
+		default:
+			methodNotAllowed(r.w)
+		}
+		return
+	}
+
+	e, ok := items.entries[r.path[3]]
+	if !ok {
+		entryNotFound(r.w)
+		return
+	}
+
+	if len(r.path) == 5 {
+		switch {
+		case r.path[4] == "locales" && r.Method == http.MethodGet:
+			result := []document{}
+			for _, code := range r.stack.locales.keys {
+				_, localized := e.locales[code]
+				result = append(result, document{"code": code, "localized": localized})
+			}
+			writeJSON(r.w, http.StatusOK, document{"locales": result})
+		case r.path[4] == "unlocalize" && r.Method == http.MethodPost:
+			if locale == r.stack.masterLocale {
+				writeError(r.w, http.StatusUnprocessableEntity, 141, "The entry can not be unlocalized in the master language.", nil)
+				return
+			}
+			delete(e.locales, locale)
+			writeJSON(r.w, http.StatusOK, document{"notice": "Entry unlocalized successfully."})
+		default:
+			notFound(r.w)
+		}
+		return
+	}
+	if len(r.path) != 4 {
+		notFound(r.w)
+		return
+	}
+
+	switch r.Method {
+	case http.MethodGet:
+		doc := s.resolveEntry(r.stack, e, locale, r.URL.Query().Get("include_fallback") == "true")
+		if doc == nil {
+			entryNotFound(r.w)
+			return
+		}
+		writeJSON(r.w, http.StatusOK, document{"entry": doc})
+
+	case http.MethodPut:
+		s.saveEntry(r, contentType, items, e, locale)
+
+	case http.MethodDelete:
+		if r.URL.Query().Get("locale") != "" && locale != r.stack.masterLocale {
+			delete(e.locales, locale)
+		} else {
+			delete(items.entries, e.uid)
+			for i, key := range items.keys {
+				if key == e.uid {
+					items.keys = append(items.keys[:i], items.keys[i+1:]...)
+					break
+				}
+			}
+		}
+		writeJSON(r.w, http.StatusOK, document{"notice": "Entry deleted successfully."})
+
+	default:
+		methodNotAllowed(r.w)
+	}
+}
+
+func entryNotFound(w http.ResponseWriter) {
+	writeError(w, http.StatusUnprocessableEntity, 141, "The requested entry doesn't exist.", nil)
+}
+
+// resolveEntry returns the version of the entry to return for the locale. Like
+// the API, the version in the master locale is returned when the entry isn't
+// localized, or the version in the first localized fallback locale when
+// fallback is set.
+func (s *Server) resolveEntry(st *stack, e *entry, locale string, fallback bool) document {
+	if doc, ok := e.locales[locale]; ok {
+		return doc
+	}
+	if fallback {
+		for i := 0; i < len(st.locales.keys); i++ {
+			l, ok := st.locales.get(locale)
+			if !ok {
+				break
+			}
+			next, _ := l["fallback_locale"].(string)
+			if next == "" || next == locale {
+				break
+			}
+			locale = next
+			if doc, ok := e.locales[locale]; ok {
+				return doc
+			}
+		}
+	}
+	return e.locales[st.masterLocale]
+}
+
+func (s *Server) listEntries(r *request, items *entryCollection, locale string) {
+	params := r.URL.Query()
+	fallback := params.Get("include_fallback") == "true"
+
+	query := document{}
+	if raw := params.Get("query"); raw != "" {
+		if err := json.Unmarshal([]byte(raw), &query); err != nil {
+			writeError(r.w, http.StatusBadRequest, 141, "The query is not valid JSON.", nil)
+			return
+		}
+	}
+
+	docs := []document{}
+	for _, uid := range items.keys {
+		doc := s.resolveEntry(r.stack, items.entries[uid], locale, fallback)
+		if doc == nil {
+			continue
+		}
+		matched, err := match(doc, query)
+		if err != nil {
+			writeError(r.w, http.StatusBadRequest, 141, err.Error(), nil)
+			return
+		}
+		if matched {
+			docs = append(docs, doc)
+		}
+	}
+
+	if params.Get("count") == "true" {
+		writeJSON(r.w, http.StatusOK, document{"entries": len(docs)})
+		return
+	}
+	writeList(r, "entries", docs)
+}
+
+// saveEntry creates a new entry when e is nil, or creates or updates the
+// version of the entry in the locale.
+func (s *Server) saveEntry(r *request, contentType document, items *entryCollection, e *entry, locale string) {
+	action := "creation"
+	if e != nil {
+		action = "update"
+	}
+
+	body := map[string]document{}
+	if !decode(r, &body) {
+		return
+	}
+	input, ok := body["entry"]
+	if !ok {
+		writeError(r.w, http.StatusUnprocessableEntity, 119, fmt.Sprintf("Entry %s failed. Please enter valid data.", action), map[string]interface{}{
+			"entry": []string{"is a required field."},
+		})
+		return
+	}
+	for _, field := range systemFields {
+		delete(input, field)
+	}
+
+	uid := ""
+	if e != nil {
+		uid = e.uid
+	}
+	if problems := validateEntry(contentType, input, items, uid, locale); len(problems) > 0 {
+		writeError(r.w, http.StatusUnprocessableEntity, 119, fmt.Sprintf("Entry %s failed. Please enter valid data.", action), problems)
+		return
+	}
+
+	now := s.now()
+	if e == nil {
+		e = &entry{uid: s.newUID(), createdAt: now, locales: map[string]document{}}
+		items.entries[e.uid] = e
+		items.keys = append(items.keys, e.uid)
+	}
+	e.version++
+
+	doc := clone(input).(document)
+	doc["uid"] = e.uid
+	doc["locale"] = locale
+	doc["_version"] = e.version
+	doc["created_at"] = e.createdAt
+	doc["updated_at"] = now
+	doc["created_by"] = ""
+	doc["updated_by"] = ""
+	doc["publish_details"] = []interface{}{}
+	if _, ok := doc["tags"]; !ok {
+		doc["tags"] = []interface{}{}
+	}
+	doc = clone(doc).(document)
+	e.locales[locale] = doc
+
+	status := http.StatusOK
+	if action == "creation" {
+		status = http.StatusCreated
+	}
+	writeJSON(r.w, status, document{
+		"notice": fmt.Sprintf("Entry %s successfully.", map[string]string{"creation": "created", "update": "updated"}[action]),
+		"entry":  doc,
+	})
+}
+
+// validateEntry checks the mandatory and unique top level fields of the
+// content type.
+func validateEntry(contentType document, input document, items *entryCollection, uid string, locale string) map[string]interface{} {
+	problems := map[string]interface{}{}
+	fields, _ := contentType["schema"].([]interface{})
+	for _, f := range fields {
+		field, _ := f.(document)
+		name, _ := field["uid"].(string)
+		value, set := input[name]
+
+		if mandatory, _ := field["mandatory"].(bool); mandatory && (!set || isEmpty(value)) {
+			problems[name] = []string{"is a required field."}
+			continue
+		}
+		if unique, _ := field["unique"].(bool); unique && set && !isEmpty(value) {
+			for _, other := range items.entries {
+				if other.uid == uid {
+					continue
+				}
+				if doc, ok := other.locales[locale]; ok && fmt.Sprint(doc[name]) == fmt.Sprint(value) {
+					problems[name] = []string{"is not unique."}
+				}
+			}
+		}
+	}
+	return problems
+}
+
+func isEmpty(value interface{}) bool {
+	switch v := value.(type) {
+	case nil:
+		return true
+	case string:
+		return strings.TrimSpace(v) == ""
+	case []interface{}:
+		return len(v) == 0
+	}
+	return false
+}
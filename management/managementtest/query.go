@@ -0,0 +1,153 @@
+package managementtest
+
+import (
+	"fmt"
+	"reflect"
+	"regexp"
+	"strings"
+)
+
+// match returns whether the document matches the entry query. Only the
+// operators used by the management package are supported.
+func match(doc document, query document) (bool, error) {
+	for key, condition := range query {
+		switch key {
+		case "$and", "$or":
+			queries, _ := condition.([]interface{})
+			matched := false
+			for _, q := range queries {
+				sub, _ := q.(document)
+				ok, err := match(doc, sub)
+				if err != nil {
+					return false, err
+				}
+				if key == "$and" && !ok {
+					return false, nil
+				}
+				matched = matched || ok
+			}
+			if key == "$or" && !matched {
+				return false, nil
+			}
+			continue
+		}
+
+		value, exists := lookup(doc, key)
+		operators, ok := condition.(document)
+		if !ok || !hasOperators(operators) {
+			if !exists || !equal(value, condition) {
+				return false, nil
+			}
+			continue
+		}
+
+		if pattern, ok := operators["$regex"].(string); ok {
+			options, _ := operators["$options"].(string)
+			ok, err := matchRegex(value, pattern, options)
+			if err != nil || !ok {
+				return false, err
+			}
+		}
+		for operator, operand := range operators {
+			if operator == "$regex" || operator == "$options" {
+				continue
+			}
+			ok, err := compare(operator, value, exists, operand)
+			if err != nil || !ok {
+				return false, err
+			}
+		}
+	}
+	return true, nil
+}
+
+func hasOperators(condition document) bool {
+	for key := range condition {
+		if strings.HasPrefix(key, "$") {
+			return true
+		}
+	}
+	return false
+}
+
+// lookup returns the value of the field, following dots into nested objects.
+func lookup(doc document, path string) (interface{}, bool) {
+	var value interface{} = doc
+	for _, key := range strings.Split(path, ".") {
+		object, ok := value.(document)
+		if !ok {
+			return nil, false
+		}
+		if value, ok = object[key]; !ok {
+			return nil, false
+		}
+	}
+	return value, true
+}
+
+// equal returns whether the value equals the operand. Lists match when one of
+// their items equals the operand.
+func equal(value interface{}, operand interface{}) bool {
+	if items, ok := value.([]interface{}); ok {
+		for _, item := range items {
+			if equal(item, operand) {
+				return true
+			}
+		}
+		return false
+	}
+	if ref, ok := value.(document); ok {
+		if uid, ok := ref["uid"]; ok && uid == operand {
+			return true
+		}
+	}
+	return reflect.DeepEqual(value, operand)
+}
+
+func compare(operator string, value interface{}, exists bool, operand interface{}) (bool, error) {
+	switch operator {
+	case "$exists":
+		want, _ := operand.(bool)
+		return exists == want, nil
+	case "$ne":
+		return !exists || !equal(value, operand), nil
+	case "$in", "$nin":
+		items, _ := operand.([]interface{})
+		found := false
+		for _, item := range items {
+			if exists && equal(value, item) {
+				found = true
+			}
+		}
+		return found == (operator == "$in"), nil
+	case "$lt", "$lte", "$gt", "$gte":
+		if !exists {
+			return false, nil
+		}
+		switch operator {
+		case "$lt":
+			return less(value, operand), nil
+		case "$lte":
+			return !less(operand, value), nil
+		case "$gt":
+			return less(operand, value), nil
+		default:
+			return !less(value, operand), nil
+		}
+	}
+	return false, fmt.Errorf("The query operator %s is not supported.", operator)
+}
+
+// matchRegex returns whether the value matches the pattern. Only the "i"
+// option is supported.
+func matchRegex(value interface{}, pattern string, options string) (bool, error) {
+	if strings.Contains(options, "i") {
+		pattern = "(?i)" + pattern
+	}
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		return false, fmt.Errorf("The regular expression %q is not valid.", pattern)
+	}
+	s, ok := value.(string)
+	return ok && re.MatchString(s), nil
+}
@@ -0,0 +1,262 @@
+package managementtest
+
+import (
+	"fmt"
+	"net/http"
+)
+
+// resourceType describes a resource which is managed with the standard
+// create, fetch, list, update and delete endpoints.
+type resourceType struct {
+	// name is used in the messages, key in the request and response bodies.
+	name   string
+	key    string
+	plural string
+
+	// id is the field identifying the resource in the path. A uid is
+	// generated when the id isn't set on create.
+	id       string
+	required []string
+
+	collection func(st *stack) *collection
+	notFound   func(w http.ResponseWriter, id string)
+
+	// created is called with new resources to set the defaults.
+	created func(st *stack, doc document)
+
+	// deleting is called before a resource is deleted and returns false
+	// when the resource can't be deleted.
+	deleting func(r *request, id string) bool
+}
+
+var contentTypeResource = &resourceType{
+	name:       "Content Type",
+	key:        "content_type",
+	plural:     "content_types",
+	id:         "uid",
+	required:   []string{"uid", "title"},
+	collection: func(st *stack) *collection { return st.contentTypes },
+	notFound: func(w http.ResponseWriter, id string) {
+		writeError(w, http.StatusUnprocessableEntity, 118, fmt.Sprintf("The Content Type '%s' was not found. Please try again.", id), nil)
+	},
+	created: func(st *stack, doc document) {
+		setDefault(doc, "schema", []interface{}{})
+		setDefault(doc, "description", "")
+		setDefault(doc, "options", document{"is_page": false, "singleton": false, "title": "title", "sub_title": []interface{}{}})
+	},
+	deleting: func(r *request, id string) bool {
+		delete(r.stack.entries, id)
+		return true
+	},
+}
+
+var globalFieldResource = &resourceType{
+	name:       "Global Field",
+	key:        "global_field",
+	plural:     "global_fields",
+	id:         "uid",
+	required:   []string{"uid", "title"},
+	collection: func(st *stack) *collection { return st.globalFields },
+	notFound: func(w http.ResponseWriter, id string) {
+		writeError(w, http.StatusUnprocessableEntity, 118, fmt.Sprintf("The Global Field '%s' was not found. Please try again.", id), nil)
+	},
+	created: func(st *stack, doc document) {
+		setDefault(doc, "schema", []interface{}{})
+		setDefault(doc, "description", "")
+	},
+}
+
+var localeResource = &resourceType{
+	name:       "Language",
+	key:        "locale",
+	plural:     "locales",
+	id:         "code",
+	required:   []string{"code"},
+	collection: func(st *stack) *collection { return st.locales },
+	notFound: func(w http.ResponseWriter, id string) {
+		writeError(w, http.StatusUnprocessableEntity, 141, "Language was not found. Please try again.", nil)
+	},
+	created: func(st *stack, doc document) {
+		setDefault(doc, "name", doc["code"])
+		if doc["fallback_locale"] == nil || doc["fallback_locale"] == "" {
+			doc["fallback_locale"] = st.masterLocale
+		}
+	},
+	deleting: func(r *request, id string) bool {
+		if id == r.stack.masterLocale {
+			writeError(r.w, http.StatusUnprocessableEntity, 247, "The master language cannot be deleted.", nil)
+			return false
+		}
+		return true
+	},
+}
+
+var environmentResource = &resourceType{
+	name:       "Environment",
+	key:        "environment",
+	plural:     "environments",
+	id:         "name",
+	required:   []string{"name"},
+	collection: func(st *stack) *collection { return st.environments },
+	notFound: func(w http.ResponseWriter, id string) {
+		writeError(w, http.StatusUnprocessableEntity, 141, "Environment was not found. Please try again.", nil)
+	},
+	created: func(st *stack, doc document) {
+		setDefault(doc, "urls", []interface{}{})
+	},
+}
+
+var webhookResource = &resourceType{
+	name:       "Webhook",
+	key:        "webhook",
+	plural:     "webhooks",
+	id:         "uid",
+	required:   []string{"name"},
+	collection: func(st *stack) *collection { return st.webhooks },
+	notFound: func(w http.ResponseWriter, id string) {
+		writeError(w, http.StatusUnprocessableEntity, 141, "The Webhook was not found. Please try again.", nil)
+	},
+	created: func(st *stack, doc document) {
+		setDefault(doc, "channels", []interface{}{})
+		setDefault(doc, "branches", []interface{}{"main"})
+		setDefault(doc, "destinations", []interface{}{})
+		setDefault(doc, "retry_policy", "manual")
+		setDefault(doc, "disabled", false)
+		setDefault(doc, "concise_payload", false)
+	},
+}
+
+func setDefault(doc document, key string, value interface{}) {
+	if _, ok := doc[key]; !ok {
+		doc[key] = value
+	}
+}
+
+// resource handles the standard endpoints of the resource type.
+func (s *Server) resource(r *request, rt *resourceType) {
+	items := rt.collection(r.stack)
+
+	if len(r.path) == 1 {
+		switch r.Method {
+		case http.MethodGet:
+			writeList(r, rt.plural, items.list())
+		case http.MethodPost:
+			s.createResource(r, rt)
+		default:
+			methodNotAllowed(r.w)
+		}
+		return
+	}
+	if len(r.path) != 2 {
+		notFound(r.w)
+		return
+	}
+
+	id := r.path[1]
+	doc, ok := items.get(id)
+	if !ok {
+		rt.notFound(r.w, id)
+		return
+	}
+
+	switch r.Method {
+	case http.MethodGet:
+		writeJSON(r.w, http.StatusOK, document{rt.key: doc})
+
+	case http.MethodPut:
+		body := map[string]document{}
+		if !decode(r, &body) {
+			return
+		}
+		input, ok := body[rt.key]
+		if !ok {
+			writeError(r.w, http.StatusUnprocessableEntity, 115, fmt.Sprintf("%s update failed. Please try again.", rt.name), map[string]interface{}{
+				rt.key: []string{"is a required field."},
+			})
+			return
+		}
+
+		updated := clone(doc).(document)
+		for key, value := range input {
+			if key != rt.id && key != "uid" {
+				updated[key] = value
+			}
+		}
+		updated["updated_at"] = s.now()
+		if version, ok := updated["_version"].(float64); ok {
+			updated["_version"] = version + 1
+		}
+		updated = clone(updated).(document)
+		items.put(id, updated)
+
+		writeJSON(r.w, http.StatusOK, document{
+			"notice": fmt.Sprintf("%s updated successfully.", rt.name),
+			rt.key:   updated,
+		})
+
+	case http.MethodDelete:
+		if rt.deleting != nil && !rt.deleting(r, id) {
+			return
+		}
+		items.remove(id)
+		writeJSON(r.w, http.StatusOK, document{
+			"notice": fmt.Sprintf("%s deleted successfully.", rt.name),
+		})
+
+	default:
+		methodNotAllowed(r.w)
+	}
+}
+
+func (s *Server) createResource(r *request, rt *resourceType) {
+	items := rt.collection(r.stack)
+	failed := fmt.Sprintf("%s creation failed. Please try again.", rt.name)
+
+	body := map[string]document{}
+	if !decode(r, &body) {
+		return
+	}
+	doc, ok := body[rt.key]
+	if !ok {
+		writeError(r.w, http.StatusUnprocessableEntity, 115, failed, map[string]interface{}{
+			rt.key: []string{"is a required field."},
+		})
+		return
+	}
+
+	problems := map[string]interface{}{}
+	for _, field := range rt.required {
+		if value, ok := doc[field].(string); !ok || value == "" {
+			problems[field] = []string{"is a required field."}
+		}
+	}
+	if id, ok := doc[rt.id].(string); ok {
+		if _, exists := items.get(id); exists {
+			problems[rt.id] = []string{"is not unique."}
+		}
+	}
+	if len(problems) > 0 {
+		writeError(r.w, http.StatusUnprocessableEntity, 115, failed, problems)
+		return
+	}
+
+	now := s.now()
+	if _, ok := doc["uid"].(string); !ok {
+		doc["uid"] = s.newUID()
+	}
+	doc["created_at"] = now
+	doc["updated_at"] = now
+	if rt == contentTypeResource || rt == globalFieldResource {
+		doc["_version"] = 1
+	}
+	if rt.created != nil {
+		rt.created(r.stack, doc)
+	}
+
+	doc = clone(doc).(document)
+	items.put(doc[rt.id].(string), doc)
+	writeJSON(r.w, http.StatusCreated, document{
+		"notice": fmt.Sprintf("%s created successfully.", rt.name),
+		rt.key:   doc,
+	})
+}
@@ -0,0 +1,484 @@
+// Package managementtest provides an in-memory fake of the Contentstack
+// management API for tests.
+//
+// The fake implements the endpoints used by the management package for
+// stacks, content types, global fields, entries, assets, asset folders,
+// locales, environments, webhooks, stack settings and user sessions. Errors are
+// returned with the status codes and bodies of the real API. Like from the CDN,
+// assets are downloaded from their url without credentials.
+//
+//	server := managementtest.NewServer()
+//	defer server.Close()
+//	server.AddStack(managementtest.StackOptions{APIKey: "api-key", ManagementToken: "token"})
+//
+//	client, _ := management.NewClient(management.ClientConfig{BaseURL: server.URL})
+//	stack, _ := client.Stack(&management.StackAuth{ApiKey: "api-key", ManagementToken: "token"})
+package managementtest
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+)
+
+// statusTwoFactorRequired is returned when a two-factor authentication token
+// is required to log in.
+const statusTwoFactorRequired = 294
+
+// Server is a fake Contentstack management API. It is safe for concurrent
+// use.
+type Server struct {
+	// URL is the base URL of the server, to be used as the BaseURL of the
+	// client.
+	URL string
+
+	server *httptest.Server
+
+	mu       sync.Mutex
+	seq      int
+	users    map[string]*user
+	sessions map[string]*user
+	stacks   []*stack
+	requests []Request
+}
+
+// Request is a request received by the server.
+type Request struct {
+	Method string
+	Path   string
+	Query  url.Values
+	Header http.Header
+}
+
+type user struct {
+	uid      string
+	email    string
+	password string
+	tfaToken string
+}
+
+// StackOptions configures a stack added to the server.
+type StackOptions struct {
+	APIKey          string
+	Name            string
+	OrganizationUID string
+
+	// ManagementToken can be used to access the stack, next to the auth
+	// token of a logged in user.
+	ManagementToken string
+
+	// MasterLocale defaults to en-us.
+	MasterLocale string
+}
+
+type stack struct {
+	doc             document
+	apiKey          string
+	managementToken string
+	masterLocale    string
+
+	contentTypes *collection
+	globalFields *collection
+	locales      *collection
+	environments *collection
+	webhooks     *collection
+	entries      map[string]*entryCollection
+
+	// assets contains the assets and folders, files the uploaded content of
+	// the assets.
+	assets *collection
+	files  map[string][]byte
+}
+
+// NewServer starts a new server. Call Close when done.
+func NewServer() *Server {
+	s := &Server{
+		users:    map[string]*user{},
+		sessions: map[string]*user{},
+	}
+	s.server = httptest.NewServer(http.HandlerFunc(s.serveHTTP))
+	s.URL = s.server.URL
+	return s
+}
+
+// Close shuts down the server.
+func (s *Server) Close() {
+	s.server.Close()
+}
+
+// Client returns an HTTP client for the server.
+func (s *Server) Client() *http.Client {
+	return s.server.Client()
+}
+
+// AddUser adds a user which can log in with the email and password. The
+// returned auth token can be used without logging in, until logging out.
+func (s *Server) AddUser(email string, password string) string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	u := &user{
+		uid:      s.newUID(),
+		email:    email,
+		password: password,
+	}
+	s.users[email] = u
+	return s.newSession(u)
+}
+
+// EnableTwoFactor requires the user to pass the two-factor authentication
+// token when logging in.
+func (s *Server) EnableTwoFactor(email string, token string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if u, ok := s.users[email]; ok {
+		u.tfaToken = token
+	}
+}
+
+func (s *Server) newSession(u *user) string {
+	token := s.newUID()
+	s.sessions[token] = u
+	return token
+}
+
+// AddStack adds a stack with only the master locale.
+func (s *Server) AddStack(opts StackOptions) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if opts.MasterLocale == "" {
+		opts.MasterLocale = "en-us"
+	}
+	if opts.Name == "" {
+		opts.Name = opts.APIKey
+	}
+
+	now := s.now()
+	st := &stack{
+		doc: document{
+			"uid":           s.newUID(),
+			"api_key":       opts.APIKey,
+			"name":          opts.Name,
+			"org_uid":       opts.OrganizationUID,
+			"master_locale": opts.MasterLocale,
+			"created_at":    now,
+			"updated_at":    now,
+		},
+		apiKey:          opts.APIKey,
+		managementToken: opts.ManagementToken,
+		masterLocale:    opts.MasterLocale,
+		contentTypes:    newCollection(),
+		globalFields:    newCollection(),
+		locales:         newCollection(),
+		environments:    newCollection(),
+		webhooks:        newCollection(),
+		entries:         map[string]*entryCollection{},
+		assets:          newCollection(),
+		files:           map[string][]byte{},
+	}
+	st.locales.put(opts.MasterLocale, document{
+		"uid":             s.newUID(),
+		"code":            opts.MasterLocale,
+		"name":            opts.MasterLocale,
+		"fallback_locale": nil,
+		"created_at":      now,
+		"updated_at":      now,
+	})
+	s.stacks = append(s.stacks, st)
+}
+
+// Requests returns the requests received by the server.
+func (s *Server) Requests() []Request {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]Request{}, s.requests...)
+}
+
+func (s *Server) newUID() string {
+	s.seq++
+	return fmt.Sprintf("blt%016x", s.seq)
+}
+
+func (s *Server) now() string {
+	return time.Now().UTC().Format("2006-01-02T15:04:05.000Z")
+}
+
+// request is the context of a single request.
+type request struct {
+	*http.Request
+	w     http.ResponseWriter
+	path  []string
+	stack *stack
+}
+
+func (s *Server) serveHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.requests = append(s.requests, Request{
+		Method: r.Method,
+		Path:   r.URL.Path,
+		Query:  r.URL.Query(),
+		Header: r.Header.Clone(),
+	})
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("X-Request-Id", s.newUID())
+
+	path := strings.Split(strings.Trim(r.URL.Path, "/"), "/")
+	if len(path) < 2 || path[0] != "v3" {
+		notFound(w)
+		return
+	}
+	req := &request{Request: r, w: w, path: path[1:]}
+
+	switch req.path[0] {
+	case "user-session":
+		s.userSession(req)
+		return
+	case "user":
+		s.currentUser(req)
+		return
+	case "stacks":
+		s.listStacks(req)
+		return
+	case "assets":
+		// The url of an asset: /v3/assets/{api_key}/{uid}/{version}/{filename}
+		if len(req.path) == 5 {
+			s.downloadAsset(req)
+			return
+		}
+	}
+
+	if !s.authorize(req) {
+		return
+	}
+	switch req.path[0] {
+	case "content_types":
+		if len(req.path) > 2 && req.path[2] == "entries" {
+			s.entries(req)
+			return
+		}
+		s.resource(req, contentTypeResource)
+	case "global_fields":
+		s.resource(req, globalFieldResource)
+	case "locales":
+		s.resource(req, localeResource)
+	case "environments":
+		s.resource(req, environmentResource)
+	case "webhooks":
+		s.resource(req, webhookResource)
+	case "assets":
+		s.assets(req)
+	case "settings":
+		s.settings(req)
+	default:
+		notFound(w)
+	}
+}
+
+// authorize resolves the stack of the request and checks the credentials.
+func (s *Server) authorize(r *request) bool {
+	apiKey := r.Header.Get("api_key")
+	for _, st := range s.stacks {
+		if st.apiKey == apiKey && apiKey != "" {
+			r.stack = st
+		}
+	}
+	if r.stack == nil {
+		writeError(r.w, http.StatusPreconditionFailed, 109, "The api_key provided is invalid.", nil)
+		return false
+	}
+
+	if token := r.Header.Get("authorization"); token != "" && token == r.stack.managementToken {
+		return true
+	}
+	if _, ok := s.sessions[r.Header.Get("authtoken")]; ok {
+		return true
+	}
+	writeError(r.w, http.StatusUnauthorized, 105, "You're not allowed in here unless you're logged in.", nil)
+	return false
+}
+
+func (s *Server) userSession(r *request) {
+	switch r.Method {
+	case http.MethodPost:
+		s.login(r)
+	case http.MethodDelete:
+		token := r.Header.Get("authtoken")
+		if _, ok := s.sessions[token]; !ok {
+			writeError(r.w, http.StatusUnauthorized, 105, "You're not allowed in here unless you're logged in.", nil)
+			return
+		}
+		delete(s.sessions, token)
+		writeJSON(r.w, http.StatusOK, document{"notice": "You've logged out successfully."})
+	default:
+		methodNotAllowed(r.w)
+	}
+}
+
+func (s *Server) login(r *request) {
+	body := struct {
+		User struct {
+			Email    string `json:"email"`
+			Password string `json:"password"`
+			TFAToken string `json:"tfa_token"`
+		} `json:"user"`
+	}{}
+	if !decode(r, &body) {
+		return
+	}
+
+	u, ok := s.users[body.User.Email]
+	if !ok || u.password != body.User.Password {
+		message := "Looks like your email or password is invalid. Please try again or reset your password."
+		writeError(r.w, http.StatusUnprocessableEntity, 104, message, map[string]interface{}{
+			"error": []string{message},
+		})
+		return
+	}
+
+	if u.tfaToken != "" && body.User.TFAToken == "" {
+		writeJSON(r.w, statusTwoFactorRequired, document{
+			"error_message": "Please login using the Two-Factor verification Token",
+			"user":          document{"tfa_type": "totp_authenticator"},
+		})
+		return
+	}
+	if u.tfaToken != "" && body.User.TFAToken != u.tfaToken {
+		message := "The Two-Factor verification Token is invalid."
+		writeError(r.w, http.StatusUnprocessableEntity, 104, message, map[string]interface{}{
+			"tfa_token": []string{message},
+		})
+		return
+	}
+
+	doc := s.userDocument(u)
+	doc["authtoken"] = s.newSession(u)
+	writeJSON(r.w, http.StatusOK, document{
+		"notice": "Login Successful.",
+		"user":   doc,
+	})
+}
+
+func (s *Server) currentUser(r *request) {
+	if r.Method != http.MethodGet {
+		methodNotAllowed(r.w)
+		return
+	}
+	u, ok := s.sessions[r.Header.Get("authtoken")]
+	if !ok {
+		writeError(r.w, http.StatusUnauthorized, 105, "You're not allowed in here unless you're logged in.", nil)
+		return
+	}
+	writeJSON(r.w, http.StatusOK, document{"user": s.userDocument(u)})
+}
+
+// userDocument returns the user as returned by the API. The user is the
+// owner of the organizations of all stacks.
+func (s *Server) userDocument(u *user) document {
+	organizations := []document{}
+	seen := map[string]bool{}
+	for _, st := range s.stacks {
+		uid, _ := st.doc["org_uid"].(string)
+		if uid == "" || seen[uid] {
+			continue
+		}
+		seen[uid] = true
+		organizations = append(organizations, document{
+			"uid":       uid,
+			"name":      uid,
+			"owner_uid": u.uid,
+			"is_owner":  true,
+			"enabled":   true,
+			"org_roles": []document{{
+				"uid":     uid + "_admin",
+				"name":    "Admin",
+				"org_uid": uid,
+				"admin":   true,
+				"default": true,
+			}},
+		})
+	}
+
+	return document{
+		"uid":           u.uid,
+		"email":         u.email,
+		"username":      strings.SplitN(u.email, "@", 2)[0],
+		"active":        true,
+		"tfa_enabled":   u.tfaToken != "",
+		"organizations": organizations,
+	}
+}
+
+func (s *Server) listStacks(r *request) {
+	if r.Method != http.MethodGet {
+		methodNotAllowed(r.w)
+		return
+	}
+	if _, ok := s.sessions[r.Header.Get("authtoken")]; !ok {
+		writeError(r.w, http.StatusUnauthorized, 105, "You're not allowed in here unless you're logged in.", nil)
+		return
+	}
+
+	organization := r.Header.Get("organization_uid")
+	docs := []document{}
+	for _, st := range s.stacks {
+		if organization == "" || st.doc["org_uid"] == organization {
+			docs = append(docs, st.doc)
+		}
+	}
+	writeList(r, "stacks", docs)
+}
+
+func (s *Server) settings(r *request) {
+	if r.Method != http.MethodGet {
+		methodNotAllowed(r.w)
+		return
+	}
+	writeJSON(r.w, http.StatusOK, document{
+		"stack_settings": document{
+			"stack_variables":    document{},
+			"discrete_variables": document{},
+			"rte":                document{},
+		},
+	})
+}
+
+func decode(r *request, v interface{}) bool {
+	if err := json.NewDecoder(r.Body).Decode(v); err != nil {
+		writeError(r.w, http.StatusBadRequest, 400, "Bad Request. Please provide valid JSON.", nil)
+		return false
+	}
+	return true
+}
+
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(v)
+}
+
+func writeError(w http.ResponseWriter, status int, code int, message string, errors map[string]interface{}) {
+	body := document{
+		"error_message": message,
+		"error_code":    code,
+	}
+	if errors != nil {
+		body["errors"] = errors
+	}
+	writeJSON(w, status, body)
+}
+
+func notFound(w http.ResponseWriter) {
+	writeError(w, http.StatusNotFound, 404, "The requested URL was not found on this server.", nil)
+}
+
+func methodNotAllowed(w http.ResponseWriter) {
+	writeError(w, http.StatusMethodNotAllowed, 405, "Method not allowed.", nil)
+}
@@ -0,0 +1,410 @@
+package managementtest_test
+
+import (
+	"context"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/labd/contentstack-go-sdk/management"
+	"github.com/labd/contentstack-go-sdk/management/managementtest"
+	"github.com/labd/contentstack-go-sdk/schema"
+)
+
+func newStack(t *testing.T) (*managementtest.Server, *management.StackInstance) {
+	t.Helper()
+	server := managementtest.NewServer()
+	t.Cleanup(server.Close)
+	server.AddStack(managementtest.StackOptions{APIKey: "api-key", ManagementToken: "token"})
+
+	client, err := management.NewClient(management.ClientConfig{BaseURL: server.URL})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	stack, err := client.Stack(&management.StackAuth{ApiKey: "api-key", ManagementToken: "token"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	return server, stack
+}
+
+func TestServer_Login(t *testing.T) {
+	server := managementtest.NewServer()
+	t.Cleanup(server.Close)
+	server.AddUser("john@example.com", "secret")
+	server.AddStack(managementtest.StackOptions{APIKey: "a", OrganizationUID: "org"})
+	server.AddStack(managementtest.StackOptions{APIKey: "b", OrganizationUID: "other"})
+
+	client, err := management.NewClient(management.ClientConfig{BaseURL: server.URL})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	ctx := context.Background()
+	err = client.Login(ctx, management.UserCredentials{Email: "john@example.com", Password: "wrong"})
+	apiErr := &management.APIError{}
+	if !errors.As(err, &apiErr) || apiErr.ErrorCode != 104 || apiErr.RequestID == "" {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	user, err := client.LoginUser(ctx, management.UserCredentials{Email: "john@example.com", Password: "secret"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if user.Email != "john@example.com" || len(user.Organizations) != 2 {
+		t.Errorf("unexpected user %+v", user)
+	}
+	stacks, err := client.Stacks(ctx, management.StacksInput{OrganizationUid: "org"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(stacks) != 1 || stacks[0].ApiKey != "a" {
+		t.Errorf("unexpected stacks %v", stacks)
+	}
+
+	stack, err := client.Stack(&management.StackAuth{ApiKey: "b"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, err := stack.Settings(ctx); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+
+	current, err := client.CurrentUser(ctx)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if current.UID != user.UID {
+		t.Errorf("CurrentUser().UID = %q, want %q", current.UID, user.UID)
+	}
+
+	if err := client.Logout(ctx); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, err := stack.Settings(ctx); err == nil {
+		t.Error("expected an error after logging out")
+	}
+}
+
+func TestServer_TwoFactor(t *testing.T) {
+	server := managementtest.NewServer()
+	t.Cleanup(server.Close)
+	server.AddUser("john@example.com", "secret")
+	server.EnableTwoFactor("john@example.com", "123456")
+
+	client, err := management.NewClient(management.ClientConfig{BaseURL: server.URL})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	ctx := context.Background()
+	credentials := management.UserCredentials{Email: "john@example.com", Password: "secret"}
+	if err := client.Login(ctx, credentials); !errors.Is(err, management.ErrTwoFactorRequired) {
+		t.Fatalf("err = %v, want ErrTwoFactorRequired", err)
+	}
+
+	credentials.TFAToken = "000000"
+	if err := client.Login(ctx, credentials); err == nil {
+		t.Fatal("expected an error for an invalid token")
+	}
+
+	credentials.TFAToken = "123456"
+	user, err := client.LoginUser(ctx, credentials)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !user.TFAEnabled {
+		t.Error("TFAEnabled = false, want true")
+	}
+}
+
+func TestServer_Unauthorized(t *testing.T) {
+	server, _ := newStack(t)
+	client, err := management.NewClient(management.ClientConfig{BaseURL: server.URL})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	stack, err := client.Stack(&management.StackAuth{ApiKey: "api-key", ManagementToken: "wrong"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	_, err = stack.ContentTypeFetchAll(context.Background())
+	if !errors.Is(err, management.ErrUnauthorized) {
+		t.Errorf("err = %v, want ErrUnauthorized", err)
+	}
+}
+
+func TestServer_ContentTypes(t *testing.T) {
+	_, stack := newStack(t)
+	ctx := context.Background()
+
+	input, err := management.NewContentTypeInput("blog", "Blog", schema.Fields(
+		schema.Text("title").Mandatory().Unique(),
+		schema.Text("category"),
+	), schema.ValidationOptions{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, err := stack.ContentTypeCreate(ctx, input); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	_, err = stack.ContentTypeCreate(ctx, input)
+	apiErr := &management.APIError{}
+	if !errors.As(err, &apiErr) || len(apiErr.Errors["uid"]) != 1 {
+		t.Errorf("unexpected error for duplicate uid: %v", err)
+	}
+
+	input.Description = management.StringRef("Blog posts")
+	if _, err := stack.ContentTypeUpdate(ctx, "blog", input); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	contentType, err := stack.ContentTypeFetch(ctx, "blog")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if contentType.Description != "Blog posts" {
+		t.Errorf("Description = %q", contentType.Description)
+	}
+
+	if err := stack.ContentTypeDelete(ctx, "blog"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	_, err = stack.ContentTypeFetch(ctx, "blog")
+	if !errors.As(err, &apiErr) || apiErr.ErrorCode != 118 {
+		t.Errorf("unexpected error for deleted content type: %v", err)
+	}
+}
+
+func TestServer_Entries(t *testing.T) {
+	_, stack := newStack(t)
+	ctx := context.Background()
+
+	input, err := management.NewContentTypeInput("blog", "Blog", schema.Fields(
+		schema.Text("title").Mandatory().Unique(),
+		schema.Number("rating"),
+	), schema.ValidationOptions{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, err := stack.ContentTypeCreate(ctx, input); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, err := stack.LocaleCreate(ctx, management.LocaleInput{Code: "nl-nl"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	_, err = stack.EntryCreate(ctx, &management.EntryInput{ContentTypeUID: "blog", Fields: map[string]interface{}{"rating": 1}})
+	apiErr := &management.APIError{}
+	if !errors.As(err, &apiErr) || len(apiErr.Errors["title"]) != 1 {
+		t.Fatalf("unexpected error for missing title: %v", err)
+	}
+
+	for i, title := range []string{"First", "Second", "Third"} {
+		_, err := stack.EntryCreate(ctx, &management.EntryInput{
+			ContentTypeUID: "blog",
+			Fields:         map[string]interface{}{"title": title, "rating": i + 1},
+		})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+
+	entries, err := stack.EntryFind(ctx, "blog", management.NewEntryQuery().GreaterThan("rating", 1).Descending("rating"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(entries) != 2 || entries[0].Fields["title"] != "Third" {
+		t.Fatalf("unexpected entries %v", entries)
+	}
+	count, err := stack.EntryCount(ctx, "blog", management.NewEntryQuery().In("title", "First", "Second"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if count != 2 {
+		t.Errorf("count = %d, want 2", count)
+	}
+
+	entryInput := &management.EntryContextInput{ContentTypeUID: "blog", UID: entries[0].UID, Locale: "nl-nl"}
+	if _, err := stack.EntryLocalize(ctx, entryInput, map[string]interface{}{"title": "Derde"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	localized, err := stack.EntryFetch(ctx, entryInput)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if localized.Locale != "nl-nl" || localized.Fields["title"] != "Derde" {
+		t.Errorf("unexpected localized entry %v", localized)
+	}
+
+	languages, err := stack.EntryLanguages(ctx, entryInput)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(languages) != 2 || !languages[1].Localized {
+		t.Errorf("unexpected languages %v", languages)
+	}
+
+	if err := stack.EntryUnlocalize(ctx, entryInput); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	fallback, err := stack.EntryFetch(ctx, entryInput)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if fallback.Locale != "en-us" {
+		t.Errorf("Locale = %q, want en-us", fallback.Locale)
+	}
+
+	if err := stack.EntryDelete(ctx, entryInput); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, err := stack.EntryFetch(ctx, entryInput); !errors.As(err, &apiErr) || apiErr.ErrorCode != 141 {
+		t.Errorf("unexpected error for deleted entry: %v", err)
+	}
+}
+
+func TestServer_Assets(t *testing.T) {
+	server, stack := newStack(t)
+	ctx := context.Background()
+
+	folder, err := stack.AssetFolderCreate(ctx, management.AssetFolderInput{Name: "images"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	_, err = stack.AssetCreate(ctx, management.AssetInput{Filename: "logo.svg", Content: strings.NewReader("<svg/>"), ParentUID: "unknown"})
+	apiErr := &management.APIError{}
+	if !errors.As(err, &apiErr) || apiErr.ErrorCode != 145 {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	asset, err := stack.AssetCreate(ctx, management.AssetInput{
+		Filename:  "logo.svg",
+		Content:   strings.NewReader("<svg/>"),
+		Title:     "Logo",
+		Tags:      []string{"brand"},
+		ParentUID: folder.UID,
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if asset.Title != "Logo" || asset.ParentUID != folder.UID || asset.ContentType != "image/svg+xml" || asset.FileSize != "6" {
+		t.Errorf("unexpected asset %+v", asset)
+	}
+
+	assets, err := stack.AssetFetchAll(ctx, "")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	fetched, err := stack.AssetFolderFetch(ctx, folder.UID)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(assets) != 1 || fetched.Name != "images" {
+		t.Errorf("assets = %+v, folder = %+v", assets, fetched)
+	}
+
+	// The asset is downloaded without credentials
+	before := len(server.Requests())
+	body, err := stack.AssetDownload(ctx, asset)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	content, _ := io.ReadAll(body)
+	body.Close()
+	if string(content) != "<svg/>" {
+		t.Errorf("content = %q, want %q", content, "<svg/>")
+	}
+	if header := server.Requests()[before].Header; header.Get("authorization") != "" || header.Get("api_key") != "" {
+		t.Errorf("credentials sent with the download: %v", header)
+	}
+
+	replaced, err := stack.AssetReplace(ctx, asset.UID, management.AssetInput{Filename: "logo.png", Content: strings.NewReader("png")})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if replaced.Version != 2 || replaced.ParentUID != "" || replaced.URL == asset.URL {
+		t.Errorf("unexpected asset %+v", replaced)
+	}
+	if _, err := stack.AssetDownload(ctx, asset); !errors.Is(err, management.ErrNotFound) {
+		t.Errorf("old url: error = %v, want ErrNotFound", err)
+	}
+
+	child, err := stack.AssetFolderCreate(ctx, management.AssetFolderInput{Name: "logos", ParentUID: folder.UID})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if child.ParentUID != folder.UID {
+		t.Errorf("ParentUID = %q, want %q", child.ParentUID, folder.UID)
+	}
+	moved, err := stack.AssetFolderMove(ctx, child.UID, "")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if moved.ParentUID != "" || moved.Name != "logos" {
+		t.Errorf("unexpected folder %+v", moved)
+	}
+
+	if err := stack.AssetFolderDelete(ctx, folder.UID); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, err := stack.AssetFolderFetch(ctx, folder.UID); err == nil {
+		t.Error("folder is not deleted")
+	}
+}
+
+func TestServer_Resources(t *testing.T) {
+	_, stack := newStack(t)
+	ctx := context.Background()
+
+	if _, err := stack.EnvironmentCreate(ctx, management.EnvironmentInput{Name: "production"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	environment, err := stack.EnvironmentFetch(ctx, "production")
+	if err != nil || environment.UID == "" {
+		t.Fatalf("unexpected result %v: %v", environment, err)
+	}
+
+	webhook, err := stack.WebHookCreate(ctx, management.WebHookInput{Name: "deploy", Channels: []string{"content_types.entries.publish"}})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, err := stack.WebHookFetch(ctx, webhook.UID); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if _, err := stack.GlobalFieldCreate(ctx, management.GlobalFieldInput{UID: management.StringRef("seo"), Title: management.StringRef("SEO")}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	globalFields, err := stack.GlobalFieldFetchAll(ctx)
+	if err != nil || len(globalFields) != 1 {
+		t.Fatalf("unexpected result %v: %v", globalFields, err)
+	}
+
+	locales, err := stack.LocaleFetchAll(ctx)
+	if err != nil || len(locales) != 1 {
+		t.Fatalf("unexpected result %v: %v", locales, err)
+	}
+	if err := stack.LocaleDelete(ctx, "en-us"); err == nil {
+		t.Error("expected an error when deleting the master locale")
+	}
+
+	for i := 0; i < 150; i++ {
+		if _, err := stack.EnvironmentCreate(ctx, management.EnvironmentInput{Name: string(rune('a'+i%26)) + string(rune('a'+i/26))}); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+	page, err := stack.EnvironmentFetchPage(ctx, management.ListOptions{Limit: 100})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(page.Items) != 100 || page.Count != 151 || !page.HasMore() {
+		t.Errorf("unexpected page: %d items, count %d", len(page.Items), page.Count)
+	}
+	all, err := stack.EnvironmentFetchAll(ctx, "")
+	if err != nil || len(all) != 151 {
+		t.Errorf("unexpected result: %d environments, %v", len(all), err)
+	}
+}
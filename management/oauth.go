@@ -0,0 +1,299 @@
+package management
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+)
+
+// oauthExpiryLeeway is how long before the expiry an access token is
+// refreshed, so it doesn't expire while a request is in flight.
+const oauthExpiryLeeway = time.Minute
+
+// Authenticator adds the credentials of the client to a request. It is used
+// for requests to the API which are not authenticated with an auth token or
+// management token, and is called for every attempt of a request. Requests to
+// other hosts, like asset downloads from the CDN, are never authenticated.
+type Authenticator interface {
+	Authenticate(ctx context.Context, header http.Header) error
+}
+
+// OAuthConfig is the configuration of a Contentstack OAuth app.
+//
+//	verifier, err := management.NewPKCEVerifier()
+//	url := cfg.AuthCodeURL(state, verifier)
+//	// redirect the user to url, and receive the code on the redirect URL
+//	token, err := cfg.Exchange(ctx, code, verifier)
+//
+//	client, err := management.NewClient(management.ClientConfig{
+//		Region:        management.RegionEU,
+//		Authenticator: management.NewOAuthAuthenticator(cfg, *token),
+//	})
+type OAuthConfig struct {
+	// AppUID is the UID of the app in the Developer Hub.
+	AppUID string
+
+	ClientID string
+
+	// ClientSecret is optional when using PKCE.
+	ClientSecret string
+
+	RedirectURL string
+	Scopes      []string
+
+	// Region determines the authorization and token URLs. Defaults to
+	// RegionNA.
+	Region Region
+
+	// AuthURL and TokenURL override the URLs of the region.
+	AuthURL  string
+	TokenURL string
+
+	// HTTPClient is used to obtain tokens. Defaults to http.DefaultClient.
+	HTTPClient *http.Client
+
+	// OnRefresh is called with the new token after refreshing it, e.g. to
+	// store the new refresh token.
+	OnRefresh func(OAuthToken)
+}
+
+// OAuthToken is a token obtained via OAuth.
+type OAuthToken struct {
+	AccessToken  string `json:"access_token"`
+	RefreshToken string `json:"refresh_token"`
+	TokenType    string `json:"token_type"`
+
+	// Expiry is the time the access token expires. A zero value means the
+	// token doesn't expire.
+	Expiry time.Time `json:"expiry"`
+
+	OrganizationUID string `json:"organization_uid,omitempty"`
+	UserUID         string `json:"user_uid,omitempty"`
+	StackAPIKey     string `json:"stack_api_key,omitempty"`
+}
+
+// expired returns whether the access token expires within the leeway.
+func (t *OAuthToken) expired() bool {
+	return !t.Expiry.IsZero() && time.Until(t.Expiry) < oauthExpiryLeeway
+}
+
+// NewPKCEVerifier returns a random PKCE code verifier, to be used for a single
+// authorization.
+func NewPKCEVerifier() (string, error) {
+	data := make([]byte, 32)
+	if _, err := rand.Read(data); err != nil {
+		return "", fmt.Errorf("Unable to generate code verifier: %w", err)
+	}
+	return base64.RawURLEncoding.EncodeToString(data), nil
+}
+
+// PKCEChallenge returns the S256 code challenge of the verifier.
+func PKCEChallenge(verifier string) string {
+	sum := sha256.Sum256([]byte(verifier))
+	return base64.RawURLEncoding.EncodeToString(sum[:])
+}
+
+func (c *OAuthConfig) endpoints() Endpoints {
+	region := c.Region
+	if region == "" {
+		region = RegionNA
+	}
+	endpoints, _ := region.Endpoints()
+	return endpoints
+}
+
+func (c *OAuthConfig) authURL() string {
+	if c.AuthURL != "" {
+		return c.AuthURL
+	}
+	return fmt.Sprintf("%sapps/%s/authorize", c.endpoints().App, c.AppUID)
+}
+
+func (c *OAuthConfig) tokenURL() string {
+	if c.TokenURL != "" {
+		return c.TokenURL
+	}
+	return c.endpoints().DeveloperHub + "token"
+}
+
+// AuthCodeURL returns the URL to redirect the user to for authorizing the
+// app. The state is returned on the redirect URL to protect against CSRF, the
+// verifier needs to be passed to Exchange.
+func (c *OAuthConfig) AuthCodeURL(state string, verifier string) string {
+	params := url.Values{
+		"response_type":         []string{"code"},
+		"client_id":             []string{c.ClientID},
+		"redirect_uri":          []string{c.RedirectURL},
+		"code_challenge":        []string{PKCEChallenge(verifier)},
+		"code_challenge_method": []string{"S256"},
+	}
+	if state != "" {
+		params.Set("state", state)
+	}
+	if len(c.Scopes) > 0 {
+		params.Set("scope", strings.Join(c.Scopes, " "))
+	}
+
+	separator := "?"
+	if strings.Contains(c.authURL(), "?") {
+		separator = "&"
+	}
+	return c.authURL() + separator + params.Encode()
+}
+
+// Exchange exchanges the authorization code received on the redirect URL for
+// a token.
+func (c *OAuthConfig) Exchange(ctx context.Context, code string, verifier string) (*OAuthToken, error) {
+	return c.requestToken(ctx, url.Values{
+		"grant_type":    []string{"authorization_code"},
+		"code":          []string{code},
+		"code_verifier": []string{verifier},
+	})
+}
+
+// Refresh obtains a new token with the refresh token.
+func (c *OAuthConfig) Refresh(ctx context.Context, refreshToken string) (*OAuthToken, error) {
+	return c.requestToken(ctx, url.Values{
+		"grant_type":    []string{"refresh_token"},
+		"refresh_token": []string{refreshToken},
+	})
+}
+
+func (c *OAuthConfig) requestToken(ctx context.Context, params url.Values) (*OAuthToken, error) {
+	params.Set("client_id", c.ClientID)
+	params.Set("redirect_uri", c.RedirectURL)
+	if c.ClientSecret != "" {
+		params.Set("client_secret", c.ClientSecret)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.tokenURL(), strings.NewReader(params.Encode()))
+	if err != nil {
+		return nil, fmt.Errorf("Creating new request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Accept", "application/json")
+
+	httpClient := c.HTTPClient
+	if httpClient == nil {
+		httpClient = http.DefaultClient
+	}
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	content, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("Reading response body: %w", err)
+	}
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return nil, newOAuthError(resp, content)
+	}
+
+	result := struct {
+		OAuthToken
+		ExpiresIn int `json:"expires_in"`
+	}{}
+	if err := json.Unmarshal(content, &result); err != nil {
+		return nil, fmt.Errorf("Unable to parse token response: %w", err)
+	}
+	if result.AccessToken == "" {
+		return nil, fmt.Errorf("no access token returned by the server")
+	}
+
+	token := result.OAuthToken
+	token.Expiry = time.Time{}
+	if result.ExpiresIn > 0 {
+		token.Expiry = time.Now().Add(time.Duration(result.ExpiresIn) * time.Second)
+	}
+	return &token, nil
+}
+
+// newOAuthError creates an APIError from an error response of the token
+// endpoint, which uses the OAuth error format instead of the one of the
+// management API.
+func newOAuthError(r *http.Response, content []byte) *APIError {
+	result := newAPIError(r, content)
+
+	body := struct {
+		Error            string `json:"error"`
+		ErrorDescription string `json:"error_description"`
+		Message          string `json:"message"`
+	}{}
+	if err := json.Unmarshal(content, &body); err == nil && result.ErrorMessage == "" {
+		for _, msg := range []string{body.ErrorDescription, body.Message, body.Error} {
+			if msg != "" {
+				result.ErrorMessage = msg
+				break
+			}
+		}
+	}
+	return result
+}
+
+// OAuthAuthenticator authenticates requests with an OAuth access token. The
+// token is refreshed when it is about to expire. It is safe for concurrent
+// use, concurrent requests share a single refresh.
+type OAuthAuthenticator struct {
+	config OAuthConfig
+
+	mu    sync.Mutex
+	token OAuthToken
+}
+
+// NewOAuthAuthenticator returns an authenticator using the token, as returned
+// by OAuthConfig.Exchange or stored after a previous refresh.
+func NewOAuthAuthenticator(cfg OAuthConfig, token OAuthToken) *OAuthAuthenticator {
+	return &OAuthAuthenticator{
+		config: cfg,
+		token:  token,
+	}
+}
+
+// Token returns a valid token, refreshing it when needed.
+func (a *OAuthAuthenticator) Token(ctx context.Context) (OAuthToken, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	if !a.token.expired() {
+		return a.token, nil
+	}
+	if a.token.RefreshToken == "" {
+		return OAuthToken{}, fmt.Errorf("the access token expired and no refresh token is available")
+	}
+
+	token, err := a.config.Refresh(ctx, a.token.RefreshToken)
+	if err != nil {
+		return OAuthToken{}, fmt.Errorf("Unable to refresh token: %w", err)
+	}
+	// The refresh token is not always rotated
+	if token.RefreshToken == "" {
+		token.RefreshToken = a.token.RefreshToken
+	}
+	a.token = *token
+
+	if a.config.OnRefresh != nil {
+		a.config.OnRefresh(a.token)
+	}
+	return a.token, nil
+}
+
+// Authenticate sets the Authorization header to the access token.
+func (a *OAuthAuthenticator) Authenticate(ctx context.Context, header http.Header) error {
+	token, err := a.Token(ctx)
+	if err != nil {
+		return err
+	}
+	header.Set("Authorization", "Bearer "+token.AccessToken)
+	return nil
+}
@@ -0,0 +1,229 @@
+package management
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"reflect"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestPKCEChallenge(t *testing.T) {
+	// Example of RFC 7636, appendix B
+	got := PKCEChallenge("dBjftJeZ4CVP-mB92K27uhbUJU1p1r_wW1gFWFOEjXk")
+	if want := "E9Melhoa2OwvFrEMTJguCHaoeK1t8URWbuGJSstw-cM"; got != want {
+		t.Errorf("PKCEChallenge() = %q, want %q", got, want)
+	}
+
+	verifier, err := NewPKCEVerifier()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(verifier) < 43 {
+		t.Errorf("verifier %q is too short", verifier)
+	}
+}
+
+func TestOAuthConfig_AuthCodeURL(t *testing.T) {
+	cfg := OAuthConfig{
+		AppUID:      "app",
+		ClientID:    "client",
+		RedirectURL: "https://example.com/callback",
+		Scopes:      []string{"cm.stacks.management:read", "cm.stacks.management:write"},
+		Region:      RegionEU,
+	}
+
+	u, err := url.Parse(cfg.AuthCodeURL("state", "verifier"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if u.Host != "eu-app.contentstack.com" || u.Path != "/apps/app/authorize" {
+		t.Errorf("url = %s", u)
+	}
+
+	want := url.Values{
+		"response_type":         []string{"code"},
+		"client_id":             []string{"client"},
+		"redirect_uri":          []string{"https://example.com/callback"},
+		"scope":                 []string{"cm.stacks.management:read cm.stacks.management:write"},
+		"state":                 []string{"state"},
+		"code_challenge":        []string{PKCEChallenge("verifier")},
+		"code_challenge_method": []string{"S256"},
+	}
+	if got := u.Query(); got.Encode() != want.Encode() {
+		t.Errorf("query = %v, want %v", got, want)
+	}
+}
+
+func TestOAuthConfig_Exchange(t *testing.T) {
+	var form url.Values
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_ = r.ParseForm()
+		form = r.PostForm
+		if form.Get("code") != "code" {
+			w.WriteHeader(http.StatusBadRequest)
+			_, _ = w.Write([]byte(`{"error": "invalid_grant", "error_description": "The code is invalid"}`))
+			return
+		}
+		_, _ = w.Write([]byte(`{
+			"access_token": "access", "refresh_token": "refresh", "token_type": "Bearer",
+			"expires_in": 3600, "organization_uid": "org", "user_uid": "user"
+		}`))
+	}))
+	t.Cleanup(server.Close)
+
+	cfg := OAuthConfig{ClientID: "client", RedirectURL: "https://example.com/callback", TokenURL: server.URL}
+	token, err := cfg.Exchange(context.Background(), "code", "verifier")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if token.AccessToken != "access" || token.OrganizationUID != "org" || time.Until(token.Expiry) < 59*time.Minute {
+		t.Errorf("unexpected token %+v", token)
+	}
+	if form.Get("grant_type") != "authorization_code" || form.Get("code_verifier") != "verifier" || form.Get("client_id") != "client" {
+		t.Errorf("unexpected form %v", form)
+	}
+
+	_, err = cfg.Exchange(context.Background(), "invalid", "verifier")
+	apiErr := &APIError{}
+	if !errors.As(err, &apiErr) || apiErr.ErrorMessage != "The code is invalid" {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
+
+func TestOAuthAuthenticator(t *testing.T) {
+	var refreshes int32
+	tokenServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		n := atomic.AddInt32(&refreshes, 1)
+		_ = r.ParseForm()
+		if r.PostForm.Get("refresh_token") != "refresh" {
+			t.Errorf("refresh_token = %q", r.PostForm.Get("refresh_token"))
+		}
+		fmt.Fprintf(w, `{"access_token": "access_%d", "expires_in": 3600}`, n)
+	}))
+	t.Cleanup(tokenServer.Close)
+
+	var mu sync.Mutex
+	authorizations := map[string]int{}
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		authorizations[r.Header.Get("Authorization")]++
+		mu.Unlock()
+		if r.Header.Get("authtoken") != "" {
+			t.Errorf("unexpected authtoken header")
+		}
+		_, _ = w.Write([]byte(`{"stack_settings": {}}`))
+	}, nil)
+	client.setAuthToken("")
+
+	var refreshed []OAuthToken
+	authenticator := NewOAuthAuthenticator(OAuthConfig{
+		ClientID: "client",
+		TokenURL: tokenServer.URL,
+		OnRefresh: func(token OAuthToken) {
+			refreshed = append(refreshed, token)
+		},
+	}, OAuthToken{
+		AccessToken:  "expired",
+		RefreshToken: "refresh",
+		Expiry:       time.Now().Add(30 * time.Second),
+	})
+	client.authenticator = authenticator
+
+	stack, err := client.Stack(&StackAuth{ApiKey: "api-key"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if _, err := stack.Settings(context.Background()); err != nil {
+				t.Errorf("unexpected error: %v", err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	if n := atomic.LoadInt32(&refreshes); n != 1 {
+		t.Errorf("refreshes = %d, want 1", n)
+	}
+	if authorizations["Bearer access_1"] != 10 {
+		t.Errorf("authorizations = %v", authorizations)
+	}
+	if len(refreshed) != 1 || refreshed[0].RefreshToken != "refresh" {
+		t.Errorf("refreshed = %+v", refreshed)
+	}
+
+	// A management token takes precedence over the authenticator
+	stack, _ = client.Stack(&StackAuth{ApiKey: "api-key", ManagementToken: "management-token"})
+	if _, err := stack.Settings(context.Background()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if authorizations["management-token"] != 1 {
+		t.Errorf("authorizations = %v", authorizations)
+	}
+}
+
+type staticAuthenticator string
+
+func (a staticAuthenticator) Authenticate(ctx context.Context, header http.Header) error {
+	header.Set("Authorization", "Bearer "+string(a))
+	return nil
+}
+
+func TestAuthenticator_OnlyAPIRequests(t *testing.T) {
+	cdn := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if auth := r.Header.Get("Authorization"); auth != "" {
+			t.Errorf("Authorization sent to the CDN: %q", auth)
+		}
+		_, _ = w.Write([]byte("<svg/>"))
+	}))
+	t.Cleanup(cdn.Close)
+
+	authorizations := map[string]string{}
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		authorizations[r.URL.Path] = r.Header.Get("Authorization")
+		switch r.URL.Path {
+		case "/v3/user-session":
+			_, _ = w.Write([]byte(`{"user": {"authtoken": "token"}}`))
+		default:
+			_, _ = w.Write([]byte(`{"stack_settings": {}}`))
+		}
+	}, nil)
+	client.setAuthToken("")
+	client.authenticator = staticAuthenticator("access")
+
+	stack, err := client.Stack(&StackAuth{ApiKey: "api-key"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	ctx := context.Background()
+	if _, err := stack.Settings(ctx); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	body, err := stack.AssetDownload(ctx, &Asset{UID: "asset_uid", URL: cdn.URL + "/v3/assets/api-key/asset_uid/version/logo.svg"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	body.Close()
+	if err := client.Login(ctx, UserCredentials{Email: "john@example.com", Password: "secret"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	want := map[string]string{
+		"/v3/settings":     "Bearer access",
+		"/v3/user-session": "",
+	}
+	if !reflect.DeepEqual(authorizations, want) {
+		t.Errorf("authorizations = %v, want %v", authorizations, want)
+	}
+}
@@ -0,0 +1,99 @@
+package management
+
+import (
+	"context"
+	"net/url"
+	"strconv"
+)
+
+// maxPageSize is the maximum number of items Contentstack returns per request.
+const maxPageSize = 100
+
+// ListOptions controls which part of a collection is fetched.
+type ListOptions struct {
+	// Limit is the number of items per page. Defaults to (and is capped at)
+	// 100 when fetching all items.
+	Limit int
+
+	// Skip is the number of items to skip.
+	Skip int
+
+	// Asc and Desc sort the items on the given field.
+	Asc  string
+	Desc string
+}
+
+func (o ListOptions) values() url.Values {
+	params := url.Values{}
+	params.Set("include_count", "true")
+	if o.Limit > 0 {
+		params.Set("limit", strconv.Itoa(o.Limit))
+	}
+	if o.Skip > 0 {
+		params.Set("skip", strconv.Itoa(o.Skip))
+	}
+	if o.Asc != "" {
+		params.Set("asc", o.Asc)
+	}
+	if o.Desc != "" {
+		params.Set("desc", o.Desc)
+	}
+	return params
+}
+
+// Page is a single page of a collection.
+type Page[T any] struct {
+	Items []T
+
+	// Count is the total number of items in the collection.
+	Count int
+
+	Skip  int
+	Limit int
+}
+
+// HasMore returns whether there are items after this page. When the endpoint
+// didn't return the total count, a full page is assumed to be followed by
+// more items.
+func (p *Page[T]) HasMore() bool {
+	if len(p.Items) == 0 {
+		return false
+	}
+	if p.Count == 0 {
+		limit := p.Limit
+		if limit <= 0 {
+			limit = maxPageSize
+		}
+		return len(p.Items) >= limit
+	}
+	return p.Skip+len(p.Items) < p.Count
+}
+
+func newPage[T any](items []T, count int, opts ListOptions) *Page[T] {
+	if items == nil {
+		items = []T{}
+	}
+	return &Page[T]{
+		Items: items,
+		Count: count,
+		Skip:  opts.Skip,
+		Limit: opts.Limit,
+	}
+}
+
+type pageFetcher[T any] func(ctx context.Context, opts ListOptions) (*Page[T], error)
+
+// fetchAll fetches all pages starting at opts.Skip and returns the combined
+// items.
+func fetchAll[T any](ctx context.Context, opts ListOptions, fetch pageFetcher[T]) ([]T, error) {
+	it := newIterator(opts, fetch)
+
+	result := []T{}
+	for it.Next(ctx) {
+		result = append(result, it.Value())
+	}
+	if err := it.Err(); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
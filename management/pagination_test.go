@@ -0,0 +1,154 @@
+package management
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"testing"
+)
+
+// pagedHandler serves total items of the given collection, honoring the
+// skip and limit parameters.
+func pagedHandler(t *testing.T, key string, total int) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		query := r.URL.Query()
+		if query.Get("include_count") != "true" {
+			t.Errorf("include_count not set")
+		}
+		skip, _ := strconv.Atoi(query.Get("skip"))
+		limit, _ := strconv.Atoi(query.Get("limit"))
+		if limit == 0 {
+			limit = 100
+		}
+
+		items := []map[string]interface{}{}
+		for i := skip; i < total && i < skip+limit; i++ {
+			items = append(items, map[string]interface{}{
+				"uid":   fmt.Sprintf("item_%d", i),
+				"title": fmt.Sprintf("Item %d", i),
+			})
+		}
+
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{
+			key:     items,
+			"count": total,
+		})
+	}
+}
+
+func newTestStack(t *testing.T, handler http.HandlerFunc) *StackInstance {
+	t.Helper()
+	client := newTestClient(t, handler, nil)
+	stack, err := client.Stack(&StackAuth{ApiKey: "api-key"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	return stack
+}
+
+func TestStackInstance_EntryFetchAllPaginates(t *testing.T) {
+	stack := newTestStack(t, pagedHandler(t, "entries", 250))
+
+	entries, err := stack.EntryFetchAll(context.Background(), "blog")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(entries) != 250 {
+		t.Fatalf("len(entries) = %d, want 250", len(entries))
+	}
+	if entries[249].UID != "item_249" {
+		t.Errorf("last entry = %q, want %q", entries[249].UID, "item_249")
+	}
+}
+
+func TestStackInstance_ContentTypeFetchPage(t *testing.T) {
+	stack := newTestStack(t, pagedHandler(t, "content_types", 25))
+
+	page, err := stack.ContentTypeFetchPage(context.Background(), ListOptions{Limit: 10, Skip: 20})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if page.Count != 25 {
+		t.Errorf("Count = %d, want 25", page.Count)
+	}
+	if len(page.Items) != 5 {
+		t.Errorf("len(Items) = %d, want 5", len(page.Items))
+	}
+	if page.HasMore() {
+		t.Error("HasMore() = true, want false")
+	}
+}
+
+func TestClient_StacksPaginates(t *testing.T) {
+	var orgUID string
+	handler := pagedHandler(t, "stacks", 30)
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		orgUID = r.Header.Get("organization_uid")
+		handler(w, r)
+	}, nil)
+
+	stacks, err := client.Stacks(context.Background(), StacksInput{
+		OrganizationUid: "org",
+		Skip:            5,
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(stacks) != 25 {
+		t.Errorf("len(stacks) = %d, want 25", len(stacks))
+	}
+	if orgUID != "org" {
+		t.Errorf("organization_uid = %q, want %q", orgUID, "org")
+	}
+}
+
+func TestClient_StacksLimit(t *testing.T) {
+	requests := 0
+	handler := pagedHandler(t, "stacks", 250)
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		handler(w, r)
+	}, nil)
+
+	tests := []struct {
+		limit    int
+		want     int
+		requests int
+	}{
+		{limit: 10, want: 10, requests: 1},
+		{limit: 150, want: 150, requests: 2},
+		{limit: 0, want: 250, requests: 3},
+	}
+	for _, tt := range tests {
+		requests = 0
+		stacks, err := client.Stacks(context.Background(), StacksInput{Limit: tt.limit})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if len(stacks) != tt.want || requests != tt.requests {
+			t.Errorf("Limit %d: got %d stacks in %d requests, want %d in %d",
+				tt.limit, len(stacks), requests, tt.want, tt.requests)
+		}
+	}
+}
+
+func TestPage_HasMoreWithoutCount(t *testing.T) {
+	tests := []struct {
+		items int
+		limit int
+		want  bool
+	}{
+		{items: 10, limit: 10, want: true},
+		{items: 7, limit: 10, want: false},
+		{items: 100, limit: 0, want: true},
+		{items: 0, limit: 10, want: false},
+	}
+	for _, tt := range tests {
+		page := newPage(make([]int, tt.items), 0, ListOptions{Limit: tt.limit})
+		if got := page.HasMore(); got != tt.want {
+			t.Errorf("HasMore() with %d items and limit %d = %v, want %v", tt.items, tt.limit, got, tt.want)
+		}
+	}
+}
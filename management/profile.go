@@ -0,0 +1,141 @@
+package management
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+)
+
+// DefaultProfile is the name of the profile used when no profile is given.
+const DefaultProfile = "default"
+
+// Profile is the configuration of a client and stack, as loaded from a
+// profile file and the environment. This allows tools to switch between
+// stacks and regions without code changes.
+//
+// The profile file is a JSON object with a profile per name:
+//
+//	{
+//		"default": {"region": "eu", "api_key": "blt123", "management_token": "cs123"},
+//		"staging": {"region": "na", "api_key": "blt456", "branch": "staging"}
+//	}
+type Profile struct {
+	Region          Region `json:"region,omitempty"`
+	BaseURL         string `json:"base_url,omitempty"`
+	AuthToken       string `json:"auth_token,omitempty"`
+	OrganizationUID string `json:"organization_uid,omitempty"`
+	ApiKey          string `json:"api_key,omitempty"`
+	ManagementToken string `json:"management_token,omitempty"`
+	Branch          string `json:"branch,omitempty"`
+}
+
+// ProfileFile returns the path of the profile file. This is the value of the
+// CONTENTSTACK_CONFIG_FILE environment variable, or contentstack/profiles.json
+// in the user's configuration directory.
+func ProfileFile() (string, error) {
+	if path := os.Getenv("CONTENTSTACK_CONFIG_FILE"); path != "" {
+		return path, nil
+	}
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "contentstack", "profiles.json"), nil
+}
+
+// LoadProfile loads the profile with the given name from the profile file and
+// applies the CONTENTSTACK_* environment variables on top of it:
+//
+//	CONTENTSTACK_REGION, CONTENTSTACK_BASE_URL, CONTENTSTACK_AUTH_TOKEN,
+//	CONTENTSTACK_ORGANIZATION_UID, CONTENTSTACK_API_KEY,
+//	CONTENTSTACK_MANAGEMENT_TOKEN and CONTENTSTACK_BRANCH
+//
+// When name is empty the CONTENTSTACK_PROFILE environment variable is used,
+// falling back to the default profile. A missing default profile is not an
+// error, so the configuration can be passed via the environment only.
+func LoadProfile(name string) (*Profile, error) {
+	path, err := ProfileFile()
+	if err != nil {
+		return nil, err
+	}
+	return LoadProfileFile(path, name)
+}
+
+// LoadProfileFile is like LoadProfile, but reads the profiles from the given
+// file.
+func LoadProfileFile(path string, name string) (*Profile, error) {
+	if name == "" {
+		name = os.Getenv("CONTENTSTACK_PROFILE")
+	}
+	if name == "" {
+		name = DefaultProfile
+	}
+
+	profiles := map[string]Profile{}
+	data, err := os.ReadFile(path)
+	switch {
+	case errors.Is(err, fs.ErrNotExist):
+	case err != nil:
+		return nil, fmt.Errorf("Unable to read profile file: %w", err)
+	default:
+		if err := json.Unmarshal(data, &profiles); err != nil {
+			return nil, fmt.Errorf("Unable to parse profile file %s: %w", path, err)
+		}
+	}
+
+	profile, ok := profiles[name]
+	if !ok && name != DefaultProfile {
+		return nil, fmt.Errorf("profile %q not found in %s", name, path)
+	}
+
+	profile.applyEnv()
+	if profile.Region != "" {
+		region, err := ParseRegion(string(profile.Region))
+		if err != nil {
+			return nil, fmt.Errorf("profile %q: %w", name, err)
+		}
+		profile.Region = region
+	}
+	return &profile, nil
+}
+
+func (p *Profile) applyEnv() {
+	for variable, field := range map[string]*string{
+		"CONTENTSTACK_BASE_URL":         &p.BaseURL,
+		"CONTENTSTACK_AUTH_TOKEN":       &p.AuthToken,
+		"CONTENTSTACK_ORGANIZATION_UID": &p.OrganizationUID,
+		"CONTENTSTACK_API_KEY":          &p.ApiKey,
+		"CONTENTSTACK_MANAGEMENT_TOKEN": &p.ManagementToken,
+		"CONTENTSTACK_BRANCH":           &p.Branch,
+	} {
+		if value := os.Getenv(variable); value != "" {
+			*field = value
+		}
+	}
+	if value := os.Getenv("CONTENTSTACK_REGION"); value != "" {
+		p.Region = Region(value)
+	}
+}
+
+// ClientConfig returns the client configuration of the profile. Other
+// options, such as the retry policy, can be set on the result.
+func (p *Profile) ClientConfig() ClientConfig {
+	return ClientConfig{
+		BaseURL:         p.BaseURL,
+		Region:          p.Region,
+		AuthToken:       p.AuthToken,
+		OrganizationUID: p.OrganizationUID,
+	}
+}
+
+// StackAuth returns the stack credentials of the profile.
+func (p *Profile) StackAuth() *StackAuth {
+	return &StackAuth{
+		ApiKey:          p.ApiKey,
+		ManagementToken: p.ManagementToken,
+		Branch:          p.Branch,
+	}
+}
@@ -0,0 +1,136 @@
+package management
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+// PublishInput describes to which environments and locales a resource is
+// published or from which it is unpublished.
+type PublishInput struct {
+	Environments []string
+	Locales      []string
+
+	// ScheduledAt schedules the action. When nil the action is performed
+	// immediately.
+	ScheduledAt *time.Time
+
+	// Version is the version to publish. The latest version is used when
+	// zero.
+	Version int
+}
+
+// PublishResult is returned by the API when a publish or unpublish action is
+// queued. Use the publish queue to track whether the action completed.
+type PublishResult struct {
+	Notice string `json:"notice"`
+	JobID  string `json:"job_id,omitempty"`
+}
+
+// PublishDetail describes where an entry is published.
+type PublishDetail struct {
+	Environment string    `json:"environment"`
+	Locale      string    `json:"locale"`
+	Time        time.Time `json:"time"`
+	User        string    `json:"user"`
+	Version     int       `json:"version"`
+}
+
+// PublishDetails unmarshals the publish details of a resource, which is
+// either a list or, for a resource published in a single environment, a
+// single object.
+type PublishDetails []PublishDetail
+
+func (p *PublishDetails) UnmarshalJSON(data []byte) error {
+	var list []PublishDetail
+	if err := json.Unmarshal(data, &list); err == nil {
+		*p = list
+		return nil
+	}
+	var single PublishDetail
+	if err := json.Unmarshal(data, &single); err != nil {
+		return fmt.Errorf("PublishDetails: cannot unmarshal %s", data)
+	}
+	*p = PublishDetails{single}
+	return nil
+}
+
+type publishTarget struct {
+	Environments []string `json:"environments"`
+	Locales      []string `json:"locales,omitempty"`
+}
+
+// serialize returns the request body where the target is stored under the
+// given key, e.g. entry or asset.
+func (p PublishInput) serialize(key string, locale string) (map[string]interface{}, error) {
+	if len(p.Environments) == 0 {
+		return nil, fmt.Errorf("at least one environment is required")
+	}
+
+	body := map[string]interface{}{
+		key: publishTarget{
+			Environments: p.Environments,
+			Locales:      p.Locales,
+		},
+	}
+	if locale != "" {
+		body["locale"] = locale
+	}
+	if p.Version > 0 {
+		body["version"] = p.Version
+	}
+	if p.ScheduledAt != nil {
+		body["scheduled_at"] = p.ScheduledAt.UTC().Format(time.RFC3339)
+	}
+	return body, nil
+}
+
+// EntryPublish publishes the entry to the given environments and locales.
+// The locale of the entry is used when no locales are given.
+func (si *StackInstance) EntryPublish(ctx context.Context, entry *EntryContextInput, input PublishInput) (*PublishResult, error) {
+	endpoint := fmt.Sprintf("/v3/content_types/%s/entries/%s/publish", entry.ContentTypeUID, entry.UID)
+	return si.entryPublishAction(ctx, endpoint, entry, input)
+}
+
+// EntryUnpublish unpublishes the entry from the given environments and
+// locales.
+func (si *StackInstance) EntryUnpublish(ctx context.Context, entry *EntryContextInput, input PublishInput) (*PublishResult, error) {
+	endpoint := fmt.Sprintf("/v3/content_types/%s/entries/%s/unpublish", entry.ContentTypeUID, entry.UID)
+	return si.entryPublishAction(ctx, endpoint, entry, input)
+}
+
+func (si *StackInstance) entryPublishAction(ctx context.Context, endpoint string, entry *EntryContextInput, input PublishInput) (*PublishResult, error) {
+	if len(input.Locales) == 0 && entry.Locale != "" {
+		input.Locales = []string{entry.Locale}
+	}
+
+	body, err := input.serialize("entry", entry.Locale)
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := serializeInput(body)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := si.client.post(
+		ctx,
+		endpoint,
+		nil,
+		si.headers(),
+		data,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &PublishResult{}
+	if err = si.client.processResponse(resp, &result); err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
@@ -0,0 +1,132 @@
+package management
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"time"
+)
+
+// Statuses of a publish queue item.
+const (
+	PublishStatusPending   = "pending"
+	PublishStatusScheduled = "scheduled"
+	PublishStatusSuccess   = "success"
+	PublishStatusFailed    = "failed"
+)
+
+type PublishQueueResponse struct {
+	Queue PublishQueueItem `json:"queue"`
+}
+
+// PublishQueueItem represents a publish or unpublish action in the publish
+// queue.
+type PublishQueueItem struct {
+	UID          string                   `json:"uid"`
+	Type         string                   `json:"type"`
+	Action       string                   `json:"action"`
+	Status       string                   `json:"status"`
+	Environments []string                 `json:"environment"`
+	Locales      []string                 `json:"locale"`
+	Entry        *PublishQueueEntry       `json:"entry,omitempty"`
+	Asset        *PublishQueueAsset       `json:"asset,omitempty"`
+	ContentType  *PublishQueueContentType `json:"content_type,omitempty"`
+	CreatedAt    time.Time                `json:"created_at"`
+	CreatedBy    string                   `json:"created_by"`
+	ScheduledAt  *time.Time               `json:"scheduled_at,omitempty"`
+	PublishedAt  *time.Time               `json:"published_at,omitempty"`
+}
+
+type PublishQueueEntry struct {
+	UID     string `json:"uid"`
+	Title   string `json:"title"`
+	Locale  string `json:"locale"`
+	Version int    `json:"version"`
+}
+
+type PublishQueueAsset struct {
+	UID     string `json:"uid"`
+	Title   string `json:"title"`
+	Version int    `json:"version"`
+}
+
+type PublishQueueContentType struct {
+	UID   string `json:"uid"`
+	Title string `json:"title"`
+}
+
+// Completed returns whether the action was processed, either successfully or
+// not.
+func (p *PublishQueueItem) Completed() bool {
+	return p.Status == PublishStatusSuccess || p.Status == PublishStatusFailed
+}
+
+func (si *StackInstance) PublishQueueFetch(ctx context.Context, uid string) (*PublishQueueItem, error) {
+	resp, err := si.client.get(
+		ctx,
+		fmt.Sprintf("/v3/publish-queue/%s", uid),
+		url.Values{},
+		si.headers(),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &PublishQueueResponse{}
+	if err = si.client.processResponse(resp, &result); err != nil {
+		return nil, err
+	}
+
+	return &result.Queue, nil
+}
+
+func (si *StackInstance) PublishQueueFetchAll(ctx context.Context) ([]PublishQueueItem, error) {
+	return fetchAll(ctx, ListOptions{}, si.PublishQueueFetchPage)
+}
+
+func (si *StackInstance) PublishQueueIterator(opts ListOptions) *Iterator[PublishQueueItem] {
+	return newIterator(opts, si.PublishQueueFetchPage)
+}
+
+func (si *StackInstance) PublishQueueFetchPage(ctx context.Context, opts ListOptions) (*Page[PublishQueueItem], error) {
+	resp, err := si.client.get(
+		ctx,
+		"/v3/publish-queue",
+		opts.values(),
+		si.headers(),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	result := struct {
+		Queue []PublishQueueItem `json:"queue"`
+		Count int                `json:"count"`
+	}{}
+
+	if err = si.client.processResponse(resp, &result); err != nil {
+		return nil, err
+	}
+
+	return newPage(result.Queue, result.Count, opts), nil
+}
+
+// PublishQueueCancel cancels a scheduled publish or unpublish action.
+func (si *StackInstance) PublishQueueCancel(ctx context.Context, uid string) error {
+	resp, err := si.client.get(
+		ctx,
+		fmt.Sprintf("/v3/publish-queue/%s/unschedule", uid),
+		url.Values{},
+		si.headers(),
+	)
+	if err != nil {
+		return err
+	}
+
+	result := &PublishResult{}
+	if err = si.client.processResponse(resp, &result); err != nil {
+		return err
+	}
+
+	return nil
+}
@@ -0,0 +1,71 @@
+package management
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestStackInstance_EntryPublish(t *testing.T) {
+	var path string
+	var body map[string]interface{}
+	stack := newTestStack(t, func(w http.ResponseWriter, r *http.Request) {
+		path = r.URL.Path
+		_ = json.NewDecoder(r.Body).Decode(&body)
+		_, _ = w.Write([]byte(`{"notice":"The requested action has been performed."}`))
+	})
+
+	scheduledAt := time.Date(2026, 1, 2, 10, 0, 0, 0, time.UTC)
+	result, err := stack.EntryPublish(context.Background(), &EntryContextInput{
+		ContentTypeUID: "blog",
+		UID:            "entry_uid",
+		Locale:         "en-us",
+	}, PublishInput{
+		Environments: []string{"production"},
+		ScheduledAt:  &scheduledAt,
+		Version:      3,
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result.Notice == "" {
+		t.Error("Notice is empty")
+	}
+
+	if path != "/v3/content_types/blog/entries/entry_uid/publish" {
+		t.Errorf("path = %q", path)
+	}
+	want := map[string]interface{}{
+		"entry": map[string]interface{}{
+			"environments": []interface{}{"production"},
+			"locales":      []interface{}{"en-us"},
+		},
+		"locale":       "en-us",
+		"version":      float64(3),
+		"scheduled_at": "2026-01-02T10:00:00Z",
+	}
+	if !reflect.DeepEqual(body, want) {
+		t.Errorf("body = %v, want %v", body, want)
+	}
+}
+
+func TestPublishDetails_UnmarshalJSON(t *testing.T) {
+	var list PublishDetails
+	if err := json.Unmarshal([]byte(`[{"environment":"a","locale":"en-us","version":1}]`), &list); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(list) != 1 || list[0].Environment != "a" {
+		t.Errorf("unexpected result %v", list)
+	}
+
+	var single PublishDetails
+	if err := json.Unmarshal([]byte(`{"environment":"b","locale":"en-us","version":2}`), &single); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(single) != 1 || single[0].Environment != "b" {
+		t.Errorf("unexpected result %v", single)
+	}
+}
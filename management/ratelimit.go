@@ -0,0 +1,173 @@
+package management
+
+import (
+	"context"
+	"math"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// RateLimit configures the client side rate limiting of requests. The limits
+// are shared by all stacks of a client, since Contentstack enforces them per
+// organization. Reads (GET and HEAD requests) and writes have separate
+// budgets.
+type RateLimit struct {
+	// ReadsPerSecond is the number of reads allowed per second. Reads are not
+	// limited when zero.
+	ReadsPerSecond float64
+
+	// WritesPerSecond is the number of writes allowed per second. Writes are
+	// not limited when zero.
+	WritesPerSecond float64
+
+	// Burst is the number of requests which can be sent at once after being
+	// idle. Defaults to the number of requests per second.
+	Burst int
+}
+
+// rateLimiter limits the requests of a client. A nil rateLimiter doesn't
+// limit anything.
+type rateLimiter struct {
+	reads  *tokenBucket
+	writes *tokenBucket
+}
+
+func newRateLimiter(cfg *RateLimit) *rateLimiter {
+	if cfg == nil {
+		return nil
+	}
+	return &rateLimiter{
+		reads:  newTokenBucket(cfg.ReadsPerSecond, cfg.Burst),
+		writes: newTokenBucket(cfg.WritesPerSecond, cfg.Burst),
+	}
+}
+
+func (l *rateLimiter) bucket(method string) *tokenBucket {
+	if l == nil {
+		return nil
+	}
+	if method == http.MethodGet || method == http.MethodHead {
+		return l.reads
+	}
+	return l.writes
+}
+
+// wait blocks until the request is allowed or the context is done.
+func (l *rateLimiter) wait(ctx context.Context, method string) error {
+	return l.bucket(method).wait(ctx)
+}
+
+// update adapts the limiter to the quota reported by the server.
+func (l *rateLimiter) update(method string, resp *http.Response) {
+	if resp == nil {
+		return
+	}
+	l.bucket(method).update(resp)
+}
+
+// tokenBucket is a token bucket refilled at a fixed rate. A nil tokenBucket
+// allows all requests.
+type tokenBucket struct {
+	mu     sync.Mutex
+	rate   float64
+	burst  float64
+	tokens float64
+	last   time.Time
+
+	// pausedUntil is set when the server reported that the quota is used up.
+	pausedUntil time.Time
+}
+
+func newTokenBucket(rate float64, burst int) *tokenBucket {
+	if rate <= 0 {
+		return nil
+	}
+	size := float64(burst)
+	if burst <= 0 {
+		size = math.Max(1, math.Floor(rate))
+	}
+	return &tokenBucket{
+		rate:   rate,
+		burst:  size,
+		tokens: size,
+		last:   time.Now(),
+	}
+}
+
+func (b *tokenBucket) wait(ctx context.Context) error {
+	if b == nil {
+		return ctx.Err()
+	}
+
+	for {
+		delay := b.take()
+		if delay == 0 {
+			return ctx.Err()
+		}
+		if err := sleep(ctx, delay); err != nil {
+			return err
+		}
+	}
+}
+
+// take takes a token from the bucket. When no token is available it returns
+// how long to wait before trying again.
+func (b *tokenBucket) take() time.Duration {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	now := time.Now()
+	if now.Before(b.pausedUntil) {
+		return b.pausedUntil.Sub(now)
+	}
+
+	b.tokens = math.Min(b.burst, b.tokens+now.Sub(b.last).Seconds()*b.rate)
+	b.last = now
+	if b.tokens >= 1 {
+		b.tokens--
+		return 0
+	}
+	return time.Duration((1 - b.tokens) / b.rate * float64(time.Second))
+}
+
+func (b *tokenBucket) update(resp *http.Response) {
+	if b == nil {
+		return
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if resp.StatusCode == http.StatusTooManyRequests || remaining(resp.Header) == 0 {
+		delay, ok := rateLimitDelay(resp.Header)
+		if !ok {
+			delay = time.Second
+		}
+		if until := time.Now().Add(delay); until.After(b.pausedUntil) {
+			b.pausedUntil = until
+		}
+		b.tokens = 0
+		return
+	}
+
+	// Don't send more requests than the server still allows
+	if n := remaining(resp.Header); n > 0 && float64(n) < b.tokens {
+		b.tokens = float64(n)
+	}
+}
+
+// remaining returns the number of requests the server still allows in the
+// current window, or -1 when not reported.
+func remaining(h http.Header) int {
+	v := h.Get("X-RateLimit-Remaining")
+	if v == "" {
+		return -1
+	}
+	n, err := strconv.Atoi(v)
+	if err != nil {
+		return -1
+	}
+	return n
+}
@@ -0,0 +1,125 @@
+package management
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestRateLimit_SharedByStacks(t *testing.T) {
+	var requests int32
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&requests, 1)
+		_, _ = w.Write([]byte(`{"stack_settings": {}}`))
+	}, nil)
+	client.rateLimiter = newRateLimiter(&RateLimit{ReadsPerSecond: 50, Burst: 1})
+
+	start := time.Now()
+	var wg sync.WaitGroup
+	for _, apiKey := range []string{"a", "b"} {
+		stack, err := client.Stack(&StackAuth{ApiKey: apiKey})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		for i := 0; i < 3; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				if _, err := stack.Settings(context.Background()); err != nil {
+					t.Errorf("unexpected error: %v", err)
+				}
+			}()
+		}
+	}
+	wg.Wait()
+
+	// The first request uses the burst, the other five wait 20ms each
+	if elapsed := time.Since(start); elapsed < 100*time.Millisecond {
+		t.Errorf("elapsed = %v, want at least 100ms", elapsed)
+	}
+	if n := atomic.LoadInt32(&requests); n != 6 {
+		t.Errorf("requests = %d, want 6", n)
+	}
+}
+
+func TestRateLimit_ContextCanceled(t *testing.T) {
+	var requests int32
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&requests, 1)
+		_, _ = w.Write([]byte(`{"stack_settings": {}}`))
+	}, nil)
+	client.rateLimiter = newRateLimiter(&RateLimit{ReadsPerSecond: 1})
+	stack, err := client.Stack(&StackAuth{ApiKey: "api-key"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if _, err := stack.Settings(context.Background()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	if _, err := stack.Settings(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("err = %v, want context.DeadlineExceeded", err)
+	}
+	if n := atomic.LoadInt32(&requests); n != 1 {
+		t.Errorf("requests = %d, want 1", n)
+	}
+}
+
+func TestTokenBucket_Update(t *testing.T) {
+	tests := []struct {
+		name   string
+		status int
+		header http.Header
+		tokens float64
+		paused bool
+	}{
+		{
+			name:   "no headers",
+			status: http.StatusOK,
+			header: http.Header{},
+			tokens: 10,
+		},
+		{
+			name:   "remaining quota",
+			status: http.StatusOK,
+			header: http.Header{"X-Ratelimit-Remaining": []string{"3"}},
+			tokens: 3,
+		},
+		{
+			name:   "quota used up",
+			status: http.StatusOK,
+			header: http.Header{"X-Ratelimit-Remaining": []string{"0"}},
+			paused: true,
+		},
+		{
+			name:   "rate limited",
+			status: http.StatusTooManyRequests,
+			header: http.Header{"Retry-After": []string{"2"}},
+			paused: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			bucket := newTokenBucket(10, 0)
+			bucket.update(&http.Response{StatusCode: tt.status, Header: tt.header})
+
+			if tt.paused {
+				if delay := bucket.take(); delay < 500*time.Millisecond {
+					t.Errorf("take() = %v, want a pause", delay)
+				}
+				return
+			}
+			if bucket.tokens != tt.tokens {
+				t.Errorf("tokens = %v, want %v", bucket.tokens, tt.tokens)
+			}
+		})
+	}
+}
@@ -0,0 +1,266 @@
+package management
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+)
+
+// RecorderMode controls whether a Recorder records or replays requests.
+type RecorderMode int
+
+const (
+	// RecorderModeRecord sends the requests to the API and records the
+	// responses. The fixture is written when calling Save.
+	RecorderModeRecord RecorderMode = iota
+
+	// RecorderModeReplay serves the responses from the fixture without
+	// sending any request.
+	RecorderModeReplay
+)
+
+// Recorder is a http.RoundTripper recording requests and responses to a
+// fixture file, to be replayed later in tests without access to a stack.
+// Credentials are scrubbed from the recorded headers, query parameters and
+// JSON bodies.
+//
+//	recorder, err := management.NewRecorder("testdata/fixture.json", management.RecorderModeReplay, nil)
+//	client, err := management.NewClient(management.ClientConfig{
+//		BaseURL:    "https://api.contentstack.io/",
+//		HTTPClient: &http.Client{Transport: recorder},
+//	})
+//
+// When replaying, a request is matched on its method, path, query parameters
+// and body. Every recorded response is served once, in the recorded order.
+type Recorder struct {
+	path      string
+	mode      RecorderMode
+	transport http.RoundTripper
+
+	mu           sync.Mutex
+	interactions []Interaction
+	used         []bool
+}
+
+// Interaction is a recorded request and its response.
+type Interaction struct {
+	Request  RecordedRequest  `json:"request"`
+	Response RecordedResponse `json:"response"`
+}
+
+type RecordedRequest struct {
+	Method string      `json:"method"`
+	Path   string      `json:"path"`
+	Query  string      `json:"query,omitempty"`
+	Header http.Header `json:"header,omitempty"`
+	RecordedBody
+}
+
+type RecordedResponse struct {
+	StatusCode int         `json:"status_code"`
+	Header     http.Header `json:"header,omitempty"`
+	RecordedBody
+}
+
+// RecordedBody holds JSON bodies as is to keep the fixtures readable. Other
+// bodies are stored base64 encoded.
+type RecordedBody struct {
+	Body    json.RawMessage `json:"body,omitempty"`
+	RawBody []byte          `json:"raw_body,omitempty"`
+}
+
+func newRecordedBody(body []byte) RecordedBody {
+	if len(body) == 0 {
+		return RecordedBody{}
+	}
+	if json.Valid(body) {
+		return RecordedBody{Body: body}
+	}
+	return RecordedBody{RawBody: body}
+}
+
+func (b RecordedBody) bytes() []byte {
+	if b.Body != nil {
+		return b.Body
+	}
+	return b.RawBody
+}
+
+type fixture struct {
+	Interactions []Interaction `json:"interactions"`
+}
+
+// NewRecorder returns a Recorder for the fixture at path. In replay mode the
+// fixture is read immediately. The transport is used to send the requests
+// when recording and defaults to http.DefaultTransport.
+func NewRecorder(path string, mode RecorderMode, transport http.RoundTripper) (*Recorder, error) {
+	if transport == nil {
+		transport = http.DefaultTransport
+	}
+	r := &Recorder{
+		path:      path,
+		mode:      mode,
+		transport: transport,
+	}
+
+	if mode == RecorderModeReplay {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("Unable to read fixture: %w", err)
+		}
+		result := fixture{}
+		if err := json.Unmarshal(data, &result); err != nil {
+			return nil, fmt.Errorf("Unable to parse fixture %s: %w", path, err)
+		}
+		r.interactions = result.Interactions
+		r.used = make([]bool, len(result.Interactions))
+	}
+	return r, nil
+}
+
+// Interactions returns the recorded (or loaded) interactions.
+func (r *Recorder) Interactions() []Interaction {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]Interaction{}, r.interactions...)
+}
+
+// Save writes the recorded interactions to the fixture file. It does nothing
+// in replay mode.
+func (r *Recorder) Save() error {
+	if r.mode != RecorderModeRecord {
+		return nil
+	}
+
+	r.mu.Lock()
+	data, err := json.MarshalIndent(fixture{Interactions: r.interactions}, "", "  ")
+	r.mu.Unlock()
+	if err != nil {
+		return fmt.Errorf("Unable to serialize fixture: %w", err)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(r.path), 0o755); err != nil {
+		return err
+	}
+	return os.WriteFile(r.path, append(data, '\n'), 0o644)
+}
+
+func (r *Recorder) RoundTrip(req *http.Request) (*http.Response, error) {
+	body, err := readRequestBody(req)
+	if err != nil {
+		return nil, err
+	}
+
+	if r.mode == RecorderModeReplay {
+		return r.replay(req, body)
+	}
+	return r.record(req, body)
+}
+
+func (r *Recorder) record(req *http.Request, body []byte) (*http.Response, error) {
+	resp, err := r.transport.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+
+	content, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		return nil, fmt.Errorf("Reading response body: %w", err)
+	}
+	resp.Body = io.NopCloser(bytes.NewReader(content))
+
+	interaction := Interaction{
+		Request: RecordedRequest{
+			Method:       req.Method,
+			Path:         req.URL.Path,
+			Query:        redactQuery(req.URL.Query()).Encode(),
+			Header:       redactHeader(req.Header),
+			RecordedBody: newRecordedBody(normalizeBody(req.Header, body)),
+		},
+		Response: RecordedResponse{
+			StatusCode:   resp.StatusCode,
+			Header:       redactHeader(resp.Header),
+			RecordedBody: newRecordedBody(redactBody(content)),
+		},
+	}
+
+	r.mu.Lock()
+	r.interactions = append(r.interactions, interaction)
+	r.mu.Unlock()
+	return resp, nil
+}
+
+func (r *Recorder) replay(req *http.Request, body []byte) (*http.Response, error) {
+	query := redactQuery(req.URL.Query()).Encode()
+	body = normalizeBody(req.Header, body)
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for i, interaction := range r.interactions {
+		recorded := interaction.Request
+		if r.used[i] ||
+			recorded.Method != req.Method ||
+			recorded.Path != req.URL.Path ||
+			recorded.Query != query ||
+			!bytes.Equal(normalizeBody(recorded.Header, recorded.bytes()), body) {
+			continue
+		}
+		r.used[i] = true
+
+		content := interaction.Response.bytes()
+		header := interaction.Response.Header.Clone()
+		if header == nil {
+			header = http.Header{}
+		}
+		// Redacting the body may have changed its length
+		header.Del("Content-Length")
+		return &http.Response{
+			Status:        fmt.Sprintf("%d %s", interaction.Response.StatusCode, http.StatusText(interaction.Response.StatusCode)),
+			StatusCode:    interaction.Response.StatusCode,
+			Proto:         "HTTP/1.1",
+			ProtoMajor:    1,
+			ProtoMinor:    1,
+			Header:        header,
+			Body:          io.NopCloser(bytes.NewReader(content)),
+			ContentLength: int64(len(content)),
+			Request:       req,
+		}, nil
+	}
+
+	return nil, fmt.Errorf("No recorded response for %s %s", req.Method, req.URL.Path)
+}
+
+// readRequestBody reads the body of the request and replaces it, so it can
+// still be sent.
+func readRequestBody(req *http.Request) ([]byte, error) {
+	if req.Body == nil || req.Body == http.NoBody {
+		return nil, nil
+	}
+	body, err := io.ReadAll(req.Body)
+	req.Body.Close()
+	if err != nil {
+		return nil, fmt.Errorf("Reading request body: %w", err)
+	}
+	req.Body = io.NopCloser(bytes.NewReader(body))
+	return body, nil
+}
+
+// normalizeBody returns the body in a form that can be compared: JSON bodies
+// are re-encoded with sorted keys and without credentials, and the random
+// boundary of multipart bodies is replaced.
+func normalizeBody(header http.Header, body []byte) []byte {
+	mediaType, params, err := mime.ParseMediaType(header.Get("Content-Type"))
+	if err == nil && strings.HasPrefix(mediaType, "multipart/") && params["boundary"] != "" {
+		return bytes.ReplaceAll(body, []byte(params["boundary"]), []byte("boundary"))
+	}
+	return redactBody(body)
+}
@@ -0,0 +1,122 @@
+package management
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestRecorder(t *testing.T) {
+	fixture := filepath.Join(t.TempDir(), "fixture.json")
+
+	requests := 0
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/v3/user-session":
+			_, _ = w.Write([]byte(`{"user": {"email": "john@example.com", "authtoken": "secret-token"}}`))
+		case "/v3/content_types":
+			requests++
+			fmt.Fprintf(w, `{"content_types": [{"uid": "blog_%d"}], "count": 1}`, requests)
+		default:
+			http.NotFound(w, r)
+		}
+	}
+
+	run := func(client *Client) []string {
+		ctx := context.Background()
+		if err := client.Login(ctx, UserCredentials{Email: "john@example.com", Password: "hunter2"}); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		stack, err := client.Stack(&StackAuth{ApiKey: "api-key"})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		result := []string{}
+		for i := 0; i < 2; i++ {
+			contentTypes, err := stack.ContentTypeFetchAll(ctx)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			result = append(result, contentTypes[0].UID)
+		}
+		return result
+	}
+
+	recorder, err := NewRecorder(fixture, RecorderModeRecord, nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	client := newTestClient(t, handler, nil)
+	client.httpClient = &http.Client{Transport: recorder}
+	recorded := run(client)
+	if err := recorder.Save(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	data, err := os.ReadFile(fixture)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	for _, secret := range []string{"hunter2", "secret-token", `"api-key"`, `"token"`} {
+		if strings.Contains(string(data), secret) {
+			t.Errorf("fixture contains %s", secret)
+		}
+	}
+
+	recorder, err = NewRecorder(fixture, RecorderModeReplay, nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	client, err = NewClient(ClientConfig{
+		BaseURL:    "https://api.contentstack.io/",
+		HTTPClient: &http.Client{Transport: recorder},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	replayed := run(client)
+	if strings.Join(replayed, ",") != strings.Join(recorded, ",") {
+		t.Errorf("replayed = %v, want %v", replayed, recorded)
+	}
+
+	stack, _ := client.Stack(&StackAuth{ApiKey: "api-key"})
+	if _, err := stack.ContentTypeFetchAll(context.Background()); err == nil {
+		t.Error("expected an error for a request that was not recorded")
+	}
+}
+
+func TestNormalizeBody(t *testing.T) {
+	tests := []struct {
+		name   string
+		header http.Header
+		a, b   string
+	}{
+		{
+			name:   "json key order and credentials",
+			header: http.Header{"Content-Type": []string{"application/json"}},
+			a:      `{"user": {"email": "a", "password": "x"}}`,
+			b:      `{"user":{"password":"y","email":"a"}}`,
+		},
+		{
+			name:   "multipart boundary",
+			header: http.Header{"Content-Type": []string{"multipart/form-data; boundary=abc123"}},
+			a:      "--abc123\r\ncontent\r\n--abc123--",
+			b:      "--boundary\r\ncontent\r\n--boundary--",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			a := normalizeBody(tt.header, []byte(tt.a))
+			b := normalizeBody(tt.header, []byte(tt.b))
+			if string(a) != string(b) {
+				t.Errorf("normalizeBody() = %s, want %s", a, b)
+			}
+		})
+	}
+}
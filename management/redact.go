@@ -0,0 +1,104 @@
+package management
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// redacted replaces the values of credentials in recorded and logged
+// requests and responses.
+const redacted = "REDACTED"
+
+// isSensitive returns whether the header, query parameter or JSON key holds a
+// credential.
+func isSensitive(key string) bool {
+	key = strings.ToLower(key)
+	switch key {
+	case "authtoken", "authorization", "api_key", "token", "access_token",
+		"refresh_token", "client_secret", "cookie", "set-cookie":
+		return true
+	}
+	return strings.HasSuffix(key, "password") || strings.HasSuffix(key, "_token")
+}
+
+// redactHeader returns a copy of the header with the credentials replaced.
+func redactHeader(header http.Header) http.Header {
+	result := header.Clone()
+	for key, values := range result {
+		if !isSensitive(key) {
+			continue
+		}
+		for i := range values {
+			values[i] = redacted
+		}
+	}
+	return result
+}
+
+// redactQuery returns a copy of the query parameters with the credentials
+// replaced.
+func redactQuery(params url.Values) url.Values {
+	result := url.Values{}
+	for key, values := range params {
+		for _, value := range values {
+			if isSensitive(key) {
+				value = redacted
+			}
+			result.Add(key, value)
+		}
+	}
+	return result
+}
+
+// redactBody replaces the credentials in a JSON body. Bodies that are not
+// JSON are returned as is.
+func redactBody(body []byte) []byte {
+	value, ok := decodeJSON(body)
+	if !ok {
+		return body
+	}
+	result, err := json.Marshal(redactValue(value))
+	if err != nil {
+		return body
+	}
+	return result
+}
+
+func redactValue(value interface{}) interface{} {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		for key, item := range v {
+			if isSensitive(key) {
+				if _, ok := item.(string); ok {
+					v[key] = redacted
+					continue
+				}
+			}
+			v[key] = redactValue(item)
+		}
+	case []interface{}:
+		for i, item := range v {
+			v[i] = redactValue(item)
+		}
+	}
+	return value
+}
+
+// decodeJSON decodes the body keeping numbers as is, so re-encoding the value
+// doesn't change them.
+func decodeJSON(body []byte) (interface{}, bool) {
+	if len(body) == 0 || !json.Valid(body) {
+		return nil, false
+	}
+	decoder := json.NewDecoder(bytes.NewReader(body))
+	decoder.UseNumber()
+
+	var value interface{}
+	if err := decoder.Decode(&value); err != nil {
+		return nil, false
+	}
+	return value, true
+}
@@ -0,0 +1,110 @@
+package management
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Region is a Contentstack region. The region determines the hosts of the
+// management, delivery and image APIs.
+type Region string
+
+const (
+	RegionNA      Region = "na"
+	RegionEU      Region = "eu"
+	RegionAzureNA Region = "azure-na"
+	RegionAzureEU Region = "azure-eu"
+	RegionGCPNA   Region = "gcp-na"
+
+	// RegionCustom is used for hosts not covered by the other regions, e.g. a
+	// proxy. The hosts need to be configured explicitly.
+	RegionCustom Region = "custom"
+)
+
+// Endpoints are the base URLs of the Contentstack APIs in a region.
+type Endpoints struct {
+	Management string
+	Delivery   string
+	Images     string
+
+	// App is the URL of the web application, used to authorize OAuth apps.
+	App string
+
+	// DeveloperHub is the URL of the Developer Hub API, used to obtain OAuth
+	// tokens.
+	DeveloperHub string
+}
+
+var regionEndpoints = map[Region]Endpoints{
+	RegionNA: {
+		Management:   "https://api.contentstack.io/",
+		Delivery:     "https://cdn.contentstack.io/",
+		Images:       "https://images.contentstack.io/",
+		App:          "https://app.contentstack.com/",
+		DeveloperHub: "https://developerhub-api.contentstack.com/",
+	},
+	RegionEU: {
+		Management:   "https://eu-api.contentstack.com/",
+		Delivery:     "https://eu-cdn.contentstack.com/",
+		Images:       "https://eu-images.contentstack.com/",
+		App:          "https://eu-app.contentstack.com/",
+		DeveloperHub: "https://eu-developerhub-api.contentstack.com/",
+	},
+	RegionAzureNA: {
+		Management:   "https://azure-na-api.contentstack.com/",
+		Delivery:     "https://azure-na-cdn.contentstack.com/",
+		Images:       "https://azure-na-images.contentstack.com/",
+		App:          "https://azure-na-app.contentstack.com/",
+		DeveloperHub: "https://azure-na-developerhub-api.contentstack.com/",
+	},
+	RegionAzureEU: {
+		Management:   "https://azure-eu-api.contentstack.com/",
+		Delivery:     "https://azure-eu-cdn.contentstack.com/",
+		Images:       "https://azure-eu-images.contentstack.com/",
+		App:          "https://azure-eu-app.contentstack.com/",
+		DeveloperHub: "https://azure-eu-developerhub-api.contentstack.com/",
+	},
+	RegionGCPNA: {
+		Management:   "https://gcp-na-api.contentstack.com/",
+		Delivery:     "https://gcp-na-cdn.contentstack.com/",
+		Images:       "https://gcp-na-images.contentstack.com/",
+		App:          "https://gcp-na-app.contentstack.com/",
+		DeveloperHub: "https://gcp-na-developerhub-api.contentstack.com/",
+	},
+}
+
+// regionAliases are the alternative names of the regions, as used by the
+// Contentstack CLI and documentation.
+var regionAliases = map[string]Region{
+	"us":       RegionNA,
+	"aws-na":   RegionNA,
+	"aws-eu":   RegionEU,
+	"azure_na": RegionAzureNA,
+	"azure_eu": RegionAzureEU,
+	"gcp_na":   RegionGCPNA,
+}
+
+// ParseRegion returns the region with the given name. The name is case
+// insensitive and aliases such as "aws-na" and "us" are accepted.
+func ParseRegion(name string) (Region, error) {
+	name = strings.ToLower(strings.TrimSpace(name))
+	if region, ok := regionAliases[name]; ok {
+		return region, nil
+	}
+
+	region := Region(name)
+	if _, ok := regionEndpoints[region]; ok || region == RegionCustom {
+		return region, nil
+	}
+	return "", fmt.Errorf("unknown region %q", name)
+}
+
+// Endpoints returns the base URLs of the APIs in the region. The endpoints
+// of RegionCustom are not known, so an error is returned for it.
+func (r Region) Endpoints() (Endpoints, error) {
+	endpoints, ok := regionEndpoints[r]
+	if !ok {
+		return Endpoints{}, fmt.Errorf("no endpoints known for region %q", r)
+	}
+	return endpoints, nil
+}
@@ -0,0 +1,99 @@
+package management
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestParseRegion(t *testing.T) {
+	tests := []struct {
+		name    string
+		want    Region
+		wantErr bool
+	}{
+		{name: "eu", want: RegionEU},
+		{name: "AWS-NA", want: RegionNA},
+		{name: "us", want: RegionNA},
+		{name: "azure_eu", want: RegionAzureEU},
+		{name: "gcp-na", want: RegionGCPNA},
+		{name: "custom", want: RegionCustom},
+		{name: "mars", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseRegion(tt.name)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseRegion() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("ParseRegion() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNewClient_Region(t *testing.T) {
+	client, err := NewClient(ClientConfig{Region: RegionAzureEU})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got := client.baseURL.String(); got != "https://azure-eu-api.contentstack.com/" {
+		t.Errorf("baseURL = %q", got)
+	}
+
+	if _, err := NewClient(ClientConfig{Region: RegionCustom}); err == nil {
+		t.Error("expected an error for a custom region without BaseURL")
+	}
+	if _, err := NewClient(ClientConfig{}); err == nil {
+		t.Error("expected an error without BaseURL and Region")
+	}
+}
+
+func TestLoadProfileFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "profiles.json")
+	err := os.WriteFile(path, []byte(`{
+		"default": {"region": "eu", "api_key": "blt123", "management_token": "cs123"},
+		"staging": {"region": "aws-na", "api_key": "blt456", "management_token": "cs456", "branch": "staging"}
+	}`), 0o600)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	profile, err := LoadProfileFile(path, "")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := &Profile{Region: RegionEU, ApiKey: "blt123", ManagementToken: "cs123"}
+	if !reflect.DeepEqual(profile, want) {
+		t.Errorf("profile = %+v, want %+v", profile, want)
+	}
+
+	t.Setenv("CONTENTSTACK_PROFILE", "staging")
+	t.Setenv("CONTENTSTACK_BRANCH", "feature")
+	profile, err = LoadProfileFile(path, "")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want = &Profile{Region: RegionNA, ApiKey: "blt456", ManagementToken: "cs456", Branch: "feature"}
+	if !reflect.DeepEqual(profile, want) {
+		t.Errorf("profile = %+v, want %+v", profile, want)
+	}
+
+	if _, err := LoadProfileFile(path, "production"); err == nil {
+		t.Error("expected an error for an unknown profile")
+	}
+
+	t.Setenv("CONTENTSTACK_PROFILE", "")
+	t.Setenv("CONTENTSTACK_REGION", "gcp-na")
+	t.Setenv("CONTENTSTACK_API_KEY", "blt789")
+	profile, err = LoadProfileFile(filepath.Join(t.TempDir(), "missing.json"), "")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if profile.Region != RegionGCPNA || profile.StackAuth().ApiKey != "blt789" {
+		t.Errorf("profile = %+v", profile)
+	}
+}
@@ -0,0 +1,181 @@
+package management
+
+import (
+	"context"
+	"errors"
+	"io"
+	"math/rand"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// RetryPolicy configures how the client retries requests which failed because
+// of rate limiting, server errors or transient network errors.
+//
+// Requests which were rejected with a 429 status code are always retried since
+// the server did not process them. Server errors (5xx) and network errors are
+// only retried for idempotent methods (GET, HEAD, PUT, DELETE and OPTIONS).
+type RetryPolicy struct {
+	// MaxAttempts is the total number of attempts, including the first one.
+	MaxAttempts int
+
+	// MinBackoff is the delay before the first retry. The delay is doubled for
+	// every following retry. Defaults to DefaultRetryPolicy.MinBackoff when
+	// zero.
+	MinBackoff time.Duration
+
+	// MaxBackoff caps the delay between two attempts. A delay requested by
+	// the server via the Retry-After header is not capped. Defaults to
+	// DefaultRetryPolicy.MaxBackoff when zero.
+	MaxBackoff time.Duration
+}
+
+// DefaultRetryPolicy is a sensible retry policy for the Contentstack
+// management API, which uses a per second rate limit.
+var DefaultRetryPolicy = RetryPolicy{
+	MaxAttempts: 5,
+	MinBackoff:  500 * time.Millisecond,
+	MaxBackoff:  30 * time.Second,
+}
+
+// shouldRetry returns whether the request should be attempted again and how
+// long to wait before doing so.
+func (p *RetryPolicy) shouldRetry(ctx context.Context, method string, attempt int, resp *http.Response, err error) (time.Duration, bool) {
+	if p == nil || attempt >= p.MaxAttempts {
+		return 0, false
+	}
+
+	// Don't retry when the caller gave up
+	if ctx.Err() != nil {
+		return 0, false
+	}
+
+	if err != nil {
+		if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+			return 0, false
+		}
+		if !isIdempotent(method) {
+			return 0, false
+		}
+		return p.backoff(attempt), true
+	}
+
+	switch {
+	case resp.StatusCode == http.StatusTooManyRequests:
+		delay := p.backoff(attempt)
+		if d, ok := rateLimitDelay(resp.Header); ok && d > delay {
+			delay = d
+		}
+		return delay, true
+	case resp.StatusCode >= 500 && resp.StatusCode != http.StatusNotImplemented:
+		if !isIdempotent(method) {
+			return 0, false
+		}
+		delay := p.backoff(attempt)
+		if d, ok := rateLimitDelay(resp.Header); ok && d > delay {
+			delay = d
+		}
+		return delay, true
+	}
+	return 0, false
+}
+
+// backoff returns the exponential backoff for the given attempt with jitter
+// applied. The result is between half and the full backoff value.
+func (p *RetryPolicy) backoff(attempt int) time.Duration {
+	minBackoff, maxBackoff := p.MinBackoff, p.MaxBackoff
+	if minBackoff <= 0 {
+		minBackoff = DefaultRetryPolicy.MinBackoff
+	}
+	if maxBackoff <= 0 {
+		maxBackoff = DefaultRetryPolicy.MaxBackoff
+	}
+
+	delay := minBackoff
+	for i := 1; i < attempt && delay < maxBackoff; i++ {
+		delay *= 2
+	}
+	if delay > maxBackoff {
+		delay = maxBackoff
+	}
+
+	half := int64(delay / 2)
+	return time.Duration(half + rand.Int63n(half+1))
+}
+
+// rateLimitDelay returns the delay requested by the server, either via the
+// Retry-After header or the X-RateLimit-* headers.
+func rateLimitDelay(h http.Header) (time.Duration, bool) {
+	if v := h.Get("Retry-After"); v != "" {
+		if secs, err := strconv.Atoi(v); err == nil && secs >= 0 {
+			return time.Duration(secs) * time.Second, true
+		}
+		if t, err := http.ParseTime(v); err == nil {
+			d := time.Until(t)
+			if d < 0 {
+				d = 0
+			}
+			return d, true
+		}
+	}
+
+	if v := h.Get("X-RateLimit-Reset"); v != "" {
+		if secs, err := strconv.ParseInt(v, 10, 64); err == nil && secs >= 0 {
+			// The header is either the number of seconds until the reset or
+			// the unix timestamp of the reset.
+			if secs > 1_000_000_000 {
+				d := time.Until(time.Unix(secs, 0))
+				if d < 0 {
+					d = 0
+				}
+				return d, true
+			}
+			return time.Duration(secs) * time.Second, true
+		}
+	}
+
+	// Contentstack limits the number of requests per second, so when no
+	// requests are remaining wait for the next window.
+	if v := h.Get("X-RateLimit-Remaining"); v != "" {
+		if remaining, err := strconv.Atoi(v); err == nil && remaining <= 0 {
+			return time.Second, true
+		}
+	}
+
+	return 0, false
+}
+
+func isIdempotent(method string) bool {
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodPut, http.MethodDelete, http.MethodOptions:
+		return true
+	}
+	return false
+}
+
+// drainBody reads the remainder of the body and closes it so the underlying
+// connection can be reused for the next attempt.
+func drainBody(resp *http.Response) {
+	if resp == nil || resp.Body == nil {
+		return
+	}
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 1<<20))
+	resp.Body.Close()
+}
+
+// sleep waits for the given duration or until the context is done.
+func sleep(ctx context.Context, d time.Duration) error {
+	if d <= 0 {
+		return ctx.Err()
+	}
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
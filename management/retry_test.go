@@ -0,0 +1,304 @@
+package management
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func newTestClient(t *testing.T, handler http.HandlerFunc, policy *RetryPolicy) *Client {
+	t.Helper()
+	server := httptest.NewServer(handler)
+	t.Cleanup(server.Close)
+
+	client, err := NewClient(ClientConfig{
+		BaseURL:     server.URL,
+		AuthToken:   "token",
+		RetryPolicy: policy,
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	return client
+}
+
+var testRetryPolicy = &RetryPolicy{
+	MaxAttempts: 3,
+	MinBackoff:  time.Millisecond,
+	MaxBackoff:  5 * time.Millisecond,
+}
+
+func TestClient_RetryRateLimited(t *testing.T) {
+	var calls int32
+	var mu sync.Mutex
+	var bodies []string
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		data, _ := io.ReadAll(r.Body)
+		mu.Lock()
+		bodies = append(bodies, string(data))
+		mu.Unlock()
+		if atomic.AddInt32(&calls, 1) == 1 {
+			w.Header().Set("Retry-After", "0")
+			w.WriteHeader(http.StatusTooManyRequests)
+			return
+		}
+		w.WriteHeader(http.StatusCreated)
+	}, testRetryPolicy)
+
+	resp, err := client.post(context.Background(), "/v3/content_types", url.Values{}, http.Header{}, strings.NewReader(`{"a":1}`))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	resp.Body.Close()
+
+	if resp.StatusCode != http.StatusCreated {
+		t.Errorf("StatusCode = %d, want %d", resp.StatusCode, http.StatusCreated)
+	}
+	if n := atomic.LoadInt32(&calls); n != 2 {
+		t.Errorf("calls = %d, want 2", n)
+	}
+	mu.Lock()
+	defer mu.Unlock()
+	for i, body := range bodies {
+		if body != `{"a":1}` {
+			t.Errorf("body of attempt %d = %q, want replayed body", i+1, body)
+		}
+	}
+}
+
+func TestClient_RetryServerErrors(t *testing.T) {
+	tests := []struct {
+		name      string
+		method    string
+		policy    *RetryPolicy
+		wantCalls int32
+	}{
+		{
+			name:      "idempotent method is retried",
+			method:    http.MethodGet,
+			policy:    testRetryPolicy,
+			wantCalls: 3,
+		},
+		{
+			name:      "post is not retried",
+			method:    http.MethodPost,
+			policy:    testRetryPolicy,
+			wantCalls: 1,
+		},
+		{
+			name:      "no retry policy",
+			method:    http.MethodGet,
+			policy:    nil,
+			wantCalls: 1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var calls int32
+			client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+				atomic.AddInt32(&calls, 1)
+				w.WriteHeader(http.StatusBadGateway)
+			}, tt.policy)
+
+			resp, err := client.execute(context.Background(), tt.method, "/v3/stacks", nil, nil, nil)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			resp.Body.Close()
+
+			if resp.StatusCode != http.StatusBadGateway {
+				t.Errorf("StatusCode = %d, want %d", resp.StatusCode, http.StatusBadGateway)
+			}
+			if n := atomic.LoadInt32(&calls); n != tt.wantCalls {
+				t.Errorf("calls = %d, want %d", n, tt.wantCalls)
+			}
+		})
+	}
+}
+
+func TestClient_RetryContextCanceled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		cancel()
+		w.Header().Set("Retry-After", "10")
+		w.WriteHeader(http.StatusTooManyRequests)
+	}, testRetryPolicy)
+
+	_, err := client.get(ctx, "/v3/stacks", nil, nil)
+	if err == nil {
+		t.Fatal("expected an error")
+	}
+}
+
+type failingAuthenticator struct{}
+
+func (failingAuthenticator) Authenticate(ctx context.Context, header http.Header) error {
+	return errors.New("token expired")
+}
+
+func TestClient_ReleasesRequestBody(t *testing.T) {
+	tests := []struct {
+		name     string
+		setup    func(t *testing.T, client *Client)
+		statuses []int
+		opens    int
+		wantErr  bool
+	}{
+		{
+			name: "authenticator error",
+			setup: func(t *testing.T, client *Client) {
+				client.setAuthToken("")
+				client.authenticator = failingAuthenticator{}
+			},
+			wantErr: true,
+		},
+		{
+			name: "rate limiter canceled",
+			setup: func(t *testing.T, client *Client) {
+				client.rateLimiter = newRateLimiter(&RateLimit{WritesPerSecond: 1})
+				if err := client.rateLimiter.wait(context.Background(), http.MethodPost); err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+			},
+			wantErr: true,
+		},
+		{
+			name:     "retried",
+			statuses: []int{http.StatusTooManyRequests, http.StatusCreated},
+			opens:    2,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var calls int32
+			client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+				n := atomic.AddInt32(&calls, 1)
+				w.WriteHeader(tt.statuses[n-1])
+			}, testRetryPolicy)
+			if tt.setup != nil {
+				tt.setup(t, client)
+			}
+
+			opens, closes := 0, 0
+			body := requestBody{
+				open: func() (io.Reader, error) {
+					opens++
+					return strings.NewReader("{}"), nil
+				},
+				close:      func() { closes++ },
+				replayable: true,
+			}
+
+			ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+			defer cancel()
+			resp, err := client.do(ctx, http.MethodPost, "/v3/content_types", nil, nil, body)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if resp != nil {
+				resp.Body.Close()
+			}
+			if opens != tt.opens {
+				t.Errorf("opens = %d, want %d", opens, tt.opens)
+			}
+			if closes != opens {
+				t.Errorf("closes = %d, want %d", closes, opens)
+			}
+		})
+	}
+}
+
+func TestRateLimitDelay(t *testing.T) {
+	tests := []struct {
+		name   string
+		header http.Header
+		want   time.Duration
+		wantOk bool
+	}{
+		{
+			name:   "retry-after seconds",
+			header: http.Header{"Retry-After": []string{"3"}},
+			want:   3 * time.Second,
+			wantOk: true,
+		},
+		{
+			name:   "rate limit reset",
+			header: http.Header{"X-Ratelimit-Reset": []string{"2"}},
+			want:   2 * time.Second,
+			wantOk: true,
+		},
+		{
+			name:   "no remaining requests",
+			header: http.Header{"X-Ratelimit-Remaining": []string{"0"}},
+			want:   time.Second,
+			wantOk: true,
+		},
+		{
+			name:   "remaining requests",
+			header: http.Header{"X-Ratelimit-Remaining": []string{"5"}},
+			wantOk: false,
+		},
+		{
+			name:   "no headers",
+			header: http.Header{},
+			wantOk: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := rateLimitDelay(tt.header)
+			if ok != tt.wantOk {
+				t.Fatalf("rateLimitDelay() ok = %v, want %v", ok, tt.wantOk)
+			}
+			if got != tt.want {
+				t.Errorf("rateLimitDelay() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRetryPolicy_Backoff(t *testing.T) {
+	p := &RetryPolicy{MaxAttempts: 10, MinBackoff: 100 * time.Millisecond, MaxBackoff: time.Second}
+	for attempt := 1; attempt < 10; attempt++ {
+		got := p.backoff(attempt)
+		if got < 50*time.Millisecond || got > time.Second {
+			t.Errorf("backoff(%d) = %v, out of bounds", attempt, got)
+		}
+	}
+}
+
+func TestRetryPolicy_BackoffDefaults(t *testing.T) {
+	tests := []struct {
+		name    string
+		policy  RetryPolicy
+		attempt int
+		min     time.Duration
+		max     time.Duration
+	}{
+		{"zero min backoff", RetryPolicy{MaxBackoff: time.Second}, 1, 250 * time.Millisecond, 500 * time.Millisecond},
+		{"zero min backoff doubles", RetryPolicy{MaxBackoff: time.Second}, 2, 500 * time.Millisecond, time.Second},
+		{"zero max backoff doubles", RetryPolicy{MinBackoff: 100 * time.Millisecond}, 3, 200 * time.Millisecond, 400 * time.Millisecond},
+		{"zero max backoff caps", RetryPolicy{MinBackoff: 100 * time.Millisecond}, 20, 15 * time.Second, 30 * time.Second},
+		{"zero policy", RetryPolicy{}, 1, 250 * time.Millisecond, 500 * time.Millisecond},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := tt.policy.backoff(tt.attempt)
+			if got < tt.min || got > tt.max {
+				t.Errorf("backoff(%d) = %v, want between %v and %v", tt.attempt, got, tt.min, tt.max)
+			}
+		})
+	}
+}
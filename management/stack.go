@@ -2,8 +2,6 @@ package management
 
 import (
 	"context"
-	"net/http"
-	"net/url"
 	"time"
 )
 
@@ -19,21 +17,56 @@ type Stack struct {
 
 type StacksInput struct {
 	OrganizationUid string
-	IncludeCount    bool
-	Limit           int
-	Skip            int
-	Asc             string
-	Desc            string
+
+	// IncludeCount is ignored, the total count is always requested.
+	IncludeCount bool
+
+	// Limit is the maximum number of stacks returned by Stacks. For
+	// StacksPage and StackIterator it is the number of stacks per page.
+	Limit int
+	Skip  int
+	Asc   string
+	Desc  string
+}
+
+func (s StacksInput) listOptions() ListOptions {
+	return ListOptions{
+		Limit: s.Limit,
+		Skip:  s.Skip,
+		Asc:   s.Asc,
+		Desc:  s.Desc,
+	}
 }
 
+// Stacks returns the stacks starting at input.Skip. At most input.Limit
+// stacks are returned, all stacks when the limit is not set. The stacks are
+// fetched in pages of at most 100 stacks.
 func (c *Client) Stacks(ctx context.Context, input StacksInput) ([]Stack, error) {
-	header := http.Header{}
-	header.Add("authtoken", c.authToken)
+	it := c.StackIterator(input)
+
+	result := []Stack{}
+	for (input.Limit <= 0 || len(result) < input.Limit) && it.Next(ctx) {
+		result = append(result, it.Value())
+	}
+	if err := it.Err(); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// StacksPage returns a single page of stacks. The total number of stacks is
+// always included.
+func (c *Client) StacksPage(ctx context.Context, input StacksInput) (*Page[Stack], error) {
+	header := c.userHeaders()
+	if input.OrganizationUid != "" {
+		header.Add("organization_uid", input.OrganizationUid)
+	}
 
+	opts := input.listOptions()
 	resp, err := c.get(
 		ctx,
 		"/v3/stacks",
-		url.Values{},
+		opts.values(),
 		header,
 	)
 	if err != nil {
@@ -42,6 +75,7 @@ func (c *Client) Stacks(ctx context.Context, input StacksInput) ([]Stack, error)
 
 	result := struct {
 		Stacks []Stack `json:"stacks"`
+		Count  int     `json:"count"`
 	}{}
 
 	err = c.processResponse(resp, &result)
@@ -49,5 +83,5 @@ func (c *Client) Stacks(ctx context.Context, input StacksInput) ([]Stack, error)
 		return nil, err
 	}
 
-	return result.Stacks, nil
+	return newPage(result.Stacks, result.Count, opts), nil
 }
@@ -20,8 +20,8 @@ type StackAuth struct {
 // given stack instance.
 func (c *Client) Stack(s *StackAuth) (*StackInstance, error) {
 
-	if c.authToken == "" && s.ManagementToken == "" {
-		return nil, fmt.Errorf("the management token is required when no auth token is used")
+	if c.token() == "" && s.ManagementToken == "" && c.authenticator == nil {
+		return nil, fmt.Errorf("the management token is required when no auth token or authenticator is used")
 	}
 
 	instance := &StackInstance{
@@ -37,8 +37,8 @@ func (si *StackInstance) headers() http.Header {
 	header.Add("api_key", si.auth.ApiKey)
 	if si.auth.ManagementToken != "" {
 		header.Add("authorization", si.auth.ManagementToken)
-	} else {
-		header.Add("authtoken", si.client.authToken)
+	} else if token := si.client.token(); token != "" {
+		header.Add("authtoken", token)
 	}
 	if si.auth.Branch != "" {
 		header.Add("branch", si.auth.Branch)
@@ -0,0 +1,203 @@
+package management
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/metric"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const instrumentationName = "github.com/labd/contentstack-go-sdk/management"
+
+// collections are the path segments naming a type of resource. The other
+// segments are either the UID of a resource or an operation on it.
+var collections = map[string]bool{
+	"assets":        true,
+	"content_types": true,
+	"entries":       true,
+	"environments":  true,
+	"folders":       true,
+	"global_fields": true,
+	"locales":       true,
+	"publish-queue": true,
+	"settings":      true,
+	"stacks":        true,
+	"user-session":  true,
+	"versions":      true,
+	"webhooks":      true,
+}
+
+// telemetry creates the spans and records the metrics of the API calls.
+type telemetry struct {
+	tracer     trace.Tracer
+	propagator propagation.TextMapPropagator
+	requests   metric.Int64Counter
+	duration   metric.Float64Histogram
+}
+
+func newTelemetry(cfg ClientConfig) (*telemetry, error) {
+	tracerProvider := cfg.TracerProvider
+	if tracerProvider == nil {
+		tracerProvider = otel.GetTracerProvider()
+	}
+	meterProvider := cfg.MeterProvider
+	if meterProvider == nil {
+		meterProvider = otel.GetMeterProvider()
+	}
+	propagator := cfg.Propagator
+	if propagator == nil {
+		propagator = otel.GetTextMapPropagator()
+	}
+
+	meter := meterProvider.Meter(instrumentationName)
+	requests, err := meter.Int64Counter(
+		"contentstack.client.requests",
+		metric.WithDescription("Number of calls to the Contentstack management API"),
+		metric.WithUnit("{request}"),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("Unable to create requests counter: %w", err)
+	}
+	duration, err := meter.Float64Histogram(
+		"contentstack.client.request.duration",
+		metric.WithDescription("Duration of calls to the Contentstack management API, including retries"),
+		metric.WithUnit("s"),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("Unable to create duration histogram: %w", err)
+	}
+
+	return &telemetry{
+		tracer:     tracerProvider.Tracer(instrumentationName),
+		propagator: propagator,
+		requests:   requests,
+		duration:   duration,
+	}, nil
+}
+
+// requestSpan tracks a single API call, which may consist of multiple
+// attempts.
+type requestSpan struct {
+	telemetry *telemetry
+	span      trace.Span
+	ctx       context.Context
+	start     time.Time
+	attrs     []attribute.KeyValue
+	attempts  int
+}
+
+// start starts the span of an API call. The returned context contains the
+// span and is used for the requests.
+func (t *telemetry) start(ctx context.Context, method string, path string, headers http.Header) (context.Context, *requestSpan) {
+	resource, operation := describeEndpoint(method, path)
+	attrs := []attribute.KeyValue{
+		attribute.String("contentstack.resource.type", resource),
+		attribute.String("contentstack.operation", operation),
+		attribute.String("http.request.method", method),
+	}
+
+	spanAttrs := []attribute.KeyValue{attribute.String("url.path", path)}
+	if apiKey := headers.Get("api_key"); apiKey != "" {
+		spanAttrs = append(spanAttrs, attribute.String("contentstack.stack.api_key", apiKey))
+	}
+	if branch := headers.Get("branch"); branch != "" {
+		spanAttrs = append(spanAttrs, attribute.String("contentstack.branch", branch))
+	}
+
+	ctx, span := t.tracer.Start(ctx, fmt.Sprintf("contentstack %s.%s", resource, operation),
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(attrs...),
+		trace.WithAttributes(spanAttrs...),
+	)
+	return ctx, &requestSpan{
+		telemetry: t,
+		span:      span,
+		ctx:       ctx,
+		start:     time.Now(),
+		attrs:     attrs,
+	}
+}
+
+// inject adds the trace context to the headers of an attempt.
+func (s *requestSpan) inject(header http.Header) {
+	s.attempts++
+	s.telemetry.propagator.Inject(s.ctx, propagation.HeaderCarrier(header))
+}
+
+// end ends the span and records the metrics of the API call.
+func (s *requestSpan) end(resp *http.Response, err error) {
+	attrs := s.attrs
+	switch {
+	case err != nil:
+		attrs = append(attrs, attribute.String("error.type", fmt.Sprintf("%T", err)))
+		s.span.RecordError(err)
+		s.span.SetStatus(codes.Error, err.Error())
+	default:
+		attrs = append(attrs, attribute.Int("http.response.status_code", resp.StatusCode))
+		if resp.StatusCode >= 400 {
+			s.span.SetStatus(codes.Error, http.StatusText(resp.StatusCode))
+		}
+		if id := requestID(resp.Header); id != "" {
+			s.span.SetAttributes(attribute.String("contentstack.request_id", id))
+		}
+	}
+
+	if s.attempts > 1 {
+		s.span.SetAttributes(attribute.Int("http.request.resend_count", s.attempts-1))
+	}
+	s.span.SetAttributes(attrs[len(s.attrs):]...)
+	s.span.End()
+
+	// Use a context without cancellation, so metrics of canceled calls are
+	// still recorded
+	ctx := context.WithoutCancel(s.ctx)
+	set := metric.WithAttributes(attrs...)
+	s.telemetry.requests.Add(ctx, 1, set)
+	s.telemetry.duration.Record(ctx, time.Since(s.start).Seconds(), set)
+}
+
+// describeEndpoint returns the type of resource and the operation of an API
+// call based on its path, e.g. ("entries", "publish") for a POST to
+// /v3/content_types/blog/entries/blt123/publish.
+func describeEndpoint(method string, path string) (string, string) {
+	segments := strings.Split(strings.Trim(strings.TrimPrefix(path, "/v3"), "/"), "/")
+
+	resource := "unknown"
+	action := ""
+	hasUID := false
+	for i, segment := range segments {
+		switch {
+		case collections[segment]:
+			resource = segment
+			hasUID = false
+			action = ""
+		case i > 0 && collections[segments[i-1]] && !hasUID:
+			hasUID = true
+		case segment != "":
+			action = segment
+		}
+	}
+
+	switch {
+	case action != "":
+		return resource, action
+	case method == http.MethodPost:
+		return resource, "create"
+	case method == http.MethodGet && hasUID:
+		return resource, "fetch"
+	case method == http.MethodGet:
+		return resource, "list"
+	case method == http.MethodPut:
+		return resource, "update"
+	default:
+		return resource, strings.ToLower(method)
+	}
+}
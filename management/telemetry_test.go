@@ -0,0 +1,143 @@
+package management
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+func TestClient_Telemetry(t *testing.T) {
+	var traceparents []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		traceparents = append(traceparents, r.Header.Get("traceparent"))
+		if len(traceparents) == 1 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.Header().Set("X-Request-Id", "request-id")
+		_, _ = w.Write([]byte(`{"entry": {"uid": "blt123"}}`))
+	}))
+	t.Cleanup(server.Close)
+
+	spans := tracetest.NewSpanRecorder()
+	reader := sdkmetric.NewManualReader()
+	client, err := NewClient(ClientConfig{
+		BaseURL:        server.URL,
+		AuthToken:      "token",
+		RetryPolicy:    &RetryPolicy{MaxAttempts: 2, MinBackoff: time.Millisecond, MaxBackoff: time.Millisecond},
+		TracerProvider: sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(spans)),
+		MeterProvider:  sdkmetric.NewMeterProvider(sdkmetric.WithReader(reader)),
+		Propagator:     propagation.TraceContext{},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	stack, err := client.Stack(&StackAuth{ApiKey: "api-key", Branch: "main"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	_, err = stack.EntryFetch(context.Background(), &EntryContextInput{ContentTypeUID: "blog", UID: "blt123"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	ended := spans.Ended()
+	if len(ended) != 1 {
+		t.Fatalf("got %d spans, want 1", len(ended))
+	}
+	span := ended[0]
+	if span.Name() != "contentstack entries.fetch" {
+		t.Errorf("Name() = %q", span.Name())
+	}
+	if span.Status().Code != codes.Unset {
+		t.Errorf("Status() = %v", span.Status())
+	}
+
+	attrs := map[attribute.Key]attribute.Value{}
+	for _, attr := range span.Attributes() {
+		attrs[attr.Key] = attr.Value
+	}
+	want := map[attribute.Key]string{
+		"contentstack.resource.type": "entries",
+		"contentstack.operation":     "fetch",
+		"contentstack.stack.api_key": "api-key",
+		"contentstack.branch":        "main",
+		"contentstack.request_id":    "request-id",
+		"http.request.method":        "GET",
+	}
+	for key, value := range want {
+		if attrs[key].Emit() != value {
+			t.Errorf("attribute %s = %q, want %q", key, attrs[key].Emit(), value)
+		}
+	}
+	if attrs["http.response.status_code"].AsInt64() != 200 || attrs["http.request.resend_count"].AsInt64() != 1 {
+		t.Errorf("unexpected attributes %v", attrs)
+	}
+
+	traceID := span.SpanContext().TraceID().String()
+	for i, traceparent := range traceparents {
+		if len(traceparent) < 35 || traceparent[3:35] != traceID {
+			t.Errorf("traceparent %d = %q, want trace %s", i, traceparent, traceID)
+		}
+	}
+
+	metrics := metricdata.ResourceMetrics{}
+	if err := reader.Collect(context.Background(), &metrics); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	found := map[string]bool{}
+	for _, scope := range metrics.ScopeMetrics {
+		for _, m := range scope.Metrics {
+			found[m.Name] = true
+			if sum, ok := m.Data.(metricdata.Sum[int64]); ok {
+				if len(sum.DataPoints) != 1 || sum.DataPoints[0].Value != 1 {
+					t.Errorf("%s = %v, want a single call", m.Name, sum.DataPoints)
+				}
+			}
+		}
+	}
+	for _, name := range []string{"contentstack.client.requests", "contentstack.client.request.duration"} {
+		if !found[name] {
+			t.Errorf("metric %s not recorded", name)
+		}
+	}
+}
+
+func TestDescribeEndpoint(t *testing.T) {
+	tests := []struct {
+		method    string
+		path      string
+		resource  string
+		operation string
+	}{
+		{http.MethodGet, "/v3/content_types", "content_types", "list"},
+		{http.MethodPost, "/v3/content_types/", "content_types", "create"},
+		{http.MethodGet, "/v3/content_types/blog", "content_types", "fetch"},
+		{http.MethodPut, "/v3/content_types/blog", "content_types", "update"},
+		{http.MethodDelete, "/v3/content_types/blog", "content_types", "delete"},
+		{http.MethodGet, "/v3/content_types/blog/entries", "entries", "list"},
+		{http.MethodPost, "/v3/content_types/blog/entries/blt1/publish", "entries", "publish"},
+		{http.MethodPost, "/v3/content_types/blog/entries/blt1/versions/2/name", "versions", "name"},
+		{http.MethodGet, "/v3/assets/folders/blt1", "folders", "fetch"},
+		{http.MethodPost, "/v3/user-session", "user-session", "create"},
+	}
+
+	for _, tt := range tests {
+		resource, operation := describeEndpoint(tt.method, tt.path)
+		if resource != tt.resource || operation != tt.operation {
+			t.Errorf("describeEndpoint(%s, %s) = %s, %s, want %s, %s",
+				tt.method, tt.path, resource, operation, tt.resource, tt.operation)
+		}
+	}
+}
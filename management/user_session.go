@@ -2,18 +2,82 @@ package management
 
 import (
 	"context"
+	"fmt"
+	"io"
 	"net/http"
 	"net/url"
+	"time"
 )
 
+// statusTwoFactorRequired is the (non standard) status code returned when
+// logging in to an account with two-factor authentication without a token.
+const statusTwoFactorRequired = 294
+
+type UserCredentials struct {
+	Email    string `json:"email"`
+	Password string `json:"password"`
+
+	// TFAToken is the two-factor authentication token, required when
+	// two-factor authentication is enabled for the account.
+	TFAToken string `json:"tfa_token,omitempty"`
+}
+
+// User is a Contentstack user, as returned when logging in or fetching the
+// current user.
+type User struct {
+	CreatedAt  time.Time `json:"created_at"`
+	UpdatedAt  time.Time `json:"updated_at"`
+	UID        string    `json:"uid"`
+	Email      string    `json:"email"`
+	Username   string    `json:"username"`
+	FirstName  string    `json:"first_name"`
+	LastName   string    `json:"last_name"`
+	Company    string    `json:"company"`
+	Active     bool      `json:"active"`
+	TFAEnabled bool      `json:"tfa_enabled"`
+
+	// AuthToken is only returned when logging in.
+	AuthToken string `json:"authtoken,omitempty"`
+
+	Organizations []UserOrganization `json:"organizations"`
+}
+
+// UserOrganization is an organization the user is a member of.
+type UserOrganization struct {
+	UID      string             `json:"uid"`
+	Name     string             `json:"name"`
+	OwnerUID string             `json:"owner_uid"`
+	IsOwner  bool               `json:"is_owner"`
+	Enabled  bool               `json:"enabled"`
+	Roles    []OrganizationRole `json:"org_roles"`
+}
+
+// OrganizationRole is a role of the user in an organization.
+type OrganizationRole struct {
+	UID         string `json:"uid"`
+	Name        string `json:"name"`
+	Description string `json:"description"`
+	Admin       bool   `json:"admin"`
+	Default     bool   `json:"default"`
+}
+
+// Login logs in the user and uses the returned auth token for all following
+// requests. When two-factor authentication is enabled for the account and no
+// token is passed an error matching ErrTwoFactorRequired is returned.
 func (c *Client) Login(ctx context.Context, s UserCredentials) error {
+	_, err := c.LoginUser(ctx, s)
+	return err
+}
+
+// LoginUser logs in like Login and returns the logged in user.
+func (c *Client) LoginUser(ctx context.Context, s UserCredentials) (*User, error) {
 	data, err := serializeInput(struct {
 		User UserCredentials `json:"user"`
 	}{
 		User: s,
 	})
 	if err != nil {
-		return err
+		return nil, err
 	}
 
 	resp, err := c.post(
@@ -23,18 +87,77 @@ func (c *Client) Login(ctx context.Context, s UserCredentials) error {
 		http.Header{},
 		data,
 	)
-
 	if err != nil {
-		return err
+		return nil, err
 	}
 
-	result := make(map[string]interface{})
-	err = c.processResponse(resp, &result)
+	// The status code is in the 2xx range, so it is not handled as an error
+	// by processResponse
+	if resp.StatusCode == statusTwoFactorRequired {
+		defer resp.Body.Close()
+		content, err := io.ReadAll(resp.Body)
+		if err != nil {
+			return nil, fmt.Errorf("Reading response body: %w", err)
+		}
+		return nil, newAPIError(resp, content)
+	}
+
+	result := struct {
+		User User `json:"user"`
+	}{}
+	if err = c.processResponse(resp, &result); err != nil {
+		return nil, err
+	}
+	if result.User.AuthToken == "" {
+		return nil, fmt.Errorf("no authtoken returned by the server")
+	}
+
+	c.setAuthToken(result.User.AuthToken)
+	return &result.User, nil
+}
+
+// Logout ends the session of the logged in user, invalidating the auth token.
+func (c *Client) Logout(ctx context.Context) error {
+	header := c.userHeaders()
+
+	resp, err := c.delete(
+		ctx,
+		"/v3/user-session",
+		url.Values{},
+		header,
+		nil,
+	)
 	if err != nil {
 		return err
 	}
+	if err = c.processResponse(resp, nil); err != nil {
+		return err
+	}
 
-	c.authToken = result["user"].(map[string]interface{})["authtoken"].(string)
+	c.setAuthToken("")
 	return nil
+}
+
+// CurrentUser returns the user of the auth token. This can be used to verify
+// that the auth token is (still) valid.
+func (c *Client) CurrentUser(ctx context.Context) (*User, error) {
+	header := c.userHeaders()
 
+	resp, err := c.get(
+		ctx,
+		"/v3/user",
+		url.Values{"include_orgs_roles": []string{"true"}},
+		header,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	result := struct {
+		User User `json:"user"`
+	}{}
+	if err = c.processResponse(resp, &result); err != nil {
+		return nil, err
+	}
+	return &result.User, nil
 }
@@ -0,0 +1,132 @@
+package management
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"testing"
+)
+
+func TestClient_LoginUser(t *testing.T) {
+	tests := []struct {
+		name    string
+		status  int
+		body    string
+		wantErr error
+	}{
+		{
+			name:   "success",
+			status: http.StatusOK,
+			body: `{"notice": "Login Successful.", "user": {
+				"uid": "blt1", "email": "john@example.com", "authtoken": "new-token",
+				"organizations": [{"uid": "org", "name": "Org", "org_roles": [{"uid": "role", "name": "Admin", "admin": true}]}]
+			}}`,
+		},
+		{
+			name:    "two-factor token required",
+			status:  statusTwoFactorRequired,
+			body:    `{"error_message": "Please login using the Two-Factor verification Token", "user": {"tfa_type": "totp_authenticator"}}`,
+			wantErr: ErrTwoFactorRequired,
+		},
+		{
+			name:   "unexpected response",
+			status: http.StatusOK,
+			body:   `{"user": "john"}`,
+		},
+		{
+			name:   "missing authtoken",
+			status: http.StatusOK,
+			body:   `{"user": {"uid": "blt1"}}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var credentials map[string]map[string]string
+			client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+				_ = json.NewDecoder(r.Body).Decode(&credentials)
+				w.WriteHeader(tt.status)
+				_, _ = w.Write([]byte(tt.body))
+			}, nil)
+
+			user, err := client.LoginUser(context.Background(), UserCredentials{
+				Email:    "john@example.com",
+				Password: "secret",
+				TFAToken: "123456",
+			})
+			if credentials["user"]["tfa_token"] != "123456" {
+				t.Errorf("credentials = %v", credentials)
+			}
+
+			if tt.name != "success" {
+				if err == nil {
+					t.Fatal("expected an error")
+				}
+				if tt.wantErr != nil && !errors.Is(err, tt.wantErr) {
+					t.Errorf("err = %v, want %v", err, tt.wantErr)
+				}
+				if client.token() != "token" {
+					t.Errorf("token() = %q, want the previous token", client.token())
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if client.token() != "new-token" {
+				t.Errorf("token() = %q, want new-token", client.token())
+			}
+			if len(user.Organizations) != 1 || len(user.Organizations[0].Roles) != 1 || !user.Organizations[0].Roles[0].Admin {
+				t.Errorf("unexpected user %+v", user)
+			}
+		})
+	}
+}
+
+func TestClient_Login(t *testing.T) {
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		credentials := map[string]map[string]string{}
+		_ = json.NewDecoder(r.Body).Decode(&credentials)
+		if credentials["user"]["tfa_token"] == "" {
+			w.WriteHeader(statusTwoFactorRequired)
+			_, _ = w.Write([]byte(`{"error_message": "Please login using the Two-Factor verification Token"}`))
+			return
+		}
+		_, _ = w.Write([]byte(`{"user": {"uid": "blt1", "authtoken": "new-token"}}`))
+	}, nil)
+
+	ctx := context.Background()
+	credentials := UserCredentials{Email: "john@example.com", Password: "secret"}
+	if err := client.Login(ctx, credentials); !errors.Is(err, ErrTwoFactorRequired) {
+		t.Fatalf("err = %v, want ErrTwoFactorRequired", err)
+	}
+
+	credentials.TFAToken = "123456"
+	if err := client.Login(ctx, credentials); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if client.token() != "new-token" {
+		t.Errorf("token() = %q, want new-token", client.token())
+	}
+}
+
+func TestClient_Logout(t *testing.T) {
+	var method, token string
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		method = r.Method
+		token = r.Header.Get("authtoken")
+		_, _ = w.Write([]byte(`{"notice": "You've logged out successfully."}`))
+	}, nil)
+
+	if err := client.Logout(context.Background()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if method != http.MethodDelete || token != "token" {
+		t.Errorf("request = %s with authtoken %q", method, token)
+	}
+	if client.token() != "" {
+		t.Errorf("token() = %q, want empty", client.token())
+	}
+}
@@ -146,10 +146,14 @@ func (si *StackInstance) WebHookFetch(ctx context.Context, uid string) (*WebHook
 }
 
 func (si *StackInstance) WebHookFetchAll(ctx context.Context) ([]WebHook, error) {
+	return fetchAll(ctx, ListOptions{}, si.WebHookFetchPage)
+}
+
+func (si *StackInstance) WebHookFetchPage(ctx context.Context, opts ListOptions) (*Page[WebHook], error) {
 	resp, err := si.client.get(
 		ctx,
 		"/v3/webhooks",
-		url.Values{},
+		opts.values(),
 		si.headers(),
 	)
 	if err != nil {
@@ -158,11 +162,12 @@ func (si *StackInstance) WebHookFetchAll(ctx context.Context) ([]WebHook, error)
 
 	result := struct {
 		WebHooks []WebHook `json:"webhooks"`
+		Count    int       `json:"count"`
 	}{}
 
 	if err = si.client.processResponse(resp, &result); err != nil {
 		return nil, err
 	}
 
-	return result.WebHooks, nil
+	return newPage(result.WebHooks, result.Count, opts), nil
 }
@@ -0,0 +1,230 @@
+// Package migrate runs versioned content migrations against a stack.
+//
+// A migration is a Go function registered with an id. The runner applies the
+// migrations which were not applied before in the order of their ids and
+// records every applied migration in a ledger content type in the stack
+// itself:
+//
+//	runner := migrate.NewRunner(instance, migrate.Options{})
+//	runner.Register("20261018-rename-summary", "Rename summary to intro",
+//		func(ctx context.Context, s *migrate.Stack) error {
+//			return s.RenameField(ctx, "blog", "summary", "intro")
+//		})
+//
+//	applied, err := runner.Run(ctx)
+//
+// The runner doesn't lock the stack, so make sure only one runner is active
+// at a time.
+package migrate
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/labd/contentstack-go-sdk/management"
+	"github.com/labd/contentstack-go-sdk/schema"
+)
+
+// DefaultLedgerContentType is the uid of the content type in which the
+// applied migrations are recorded.
+const DefaultLedgerContentType = "migrations"
+
+// Func applies a migration to the stack.
+type Func func(ctx context.Context, s *Stack) error
+
+// Migration is a single registered migration.
+type Migration struct {
+	// ID identifies the migration. Migrations are applied in the order of
+	// their ids, so use a sortable prefix such as a date or a sequence
+	// number.
+	ID          string
+	Description string
+	Func        Func
+}
+
+// Options configures the runner.
+type Options struct {
+	// LedgerContentType is the uid of the content type in which the applied
+	// migrations are recorded. Defaults to DefaultLedgerContentType. The
+	// content type is created when it doesn't exist.
+	LedgerContentType string
+
+	// DryRun runs the migrations without changing the stack. The helpers of
+	// Stack only log the changes they would make, and the migrations are not
+	// recorded in the ledger.
+	DryRun bool
+
+	// Log is called with a description of every change.
+	Log func(message string)
+}
+
+// Runner applies the registered migrations to a stack.
+type Runner struct {
+	si         *management.StackInstance
+	opts       Options
+	migrations []Migration
+}
+
+func NewRunner(si *management.StackInstance, opts Options) *Runner {
+	if opts.LedgerContentType == "" {
+		opts.LedgerContentType = DefaultLedgerContentType
+	}
+	return &Runner{si: si, opts: opts}
+}
+
+// Register adds a migration to the runner.
+func (r *Runner) Register(id string, description string, fn Func) {
+	r.migrations = append(r.migrations, Migration{ID: id, Description: description, Func: fn})
+}
+
+// Migrations returns the registered migrations in the order they are applied.
+func (r *Runner) Migrations() ([]Migration, error) {
+	result := append([]Migration{}, r.migrations...)
+	sort.SliceStable(result, func(i, j int) bool { return result[i].ID < result[j].ID })
+
+	for i, migration := range result {
+		if migration.ID == "" {
+			return nil, fmt.Errorf("migration without id")
+		}
+		if migration.Func == nil {
+			return nil, fmt.Errorf("migration %s has no function", migration.ID)
+		}
+		if i > 0 && result[i-1].ID == migration.ID {
+			return nil, fmt.Errorf("duplicate migration %s", migration.ID)
+		}
+	}
+	return result, nil
+}
+
+// Pending returns the migrations which are not applied yet.
+func (r *Runner) Pending(ctx context.Context) ([]Migration, error) {
+	migrations, err := r.Migrations()
+	if err != nil {
+		return nil, err
+	}
+
+	applied, err := r.applied(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	result := []Migration{}
+	for _, migration := range migrations {
+		if !applied[migration.ID] {
+			result = append(result, migration)
+		}
+	}
+	return result, nil
+}
+
+// Run applies the pending migrations in order and returns the applied
+// migrations. Running stops at the first failing migration; the migrations
+// applied before it are recorded, so running again continues with the failed
+// migration.
+func (r *Runner) Run(ctx context.Context) ([]Migration, error) {
+	pending, err := r.Pending(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if len(pending) > 0 && !r.opts.DryRun {
+		if err := r.ensureLedger(ctx); err != nil {
+			return nil, err
+		}
+	}
+
+	stack := &Stack{si: r.si, dryRun: r.opts.DryRun, log: r.opts.Log}
+	result := []Migration{}
+	for _, migration := range pending {
+		stack.logf("applying migration %s", migration.ID)
+		if err := migration.Func(ctx, stack); err != nil {
+			return result, fmt.Errorf("migration %s failed: %w", migration.ID, err)
+		}
+
+		if !r.opts.DryRun {
+			if err := r.record(ctx, migration); err != nil {
+				return result, err
+			}
+		}
+		result = append(result, migration)
+	}
+	return result, nil
+}
+
+// errContentTypeNotFound is the error code the API returns, with a 422 status
+// code, for an unknown content type.
+const errContentTypeNotFound = 118
+
+// ledgerExists returns whether the ledger content type exists.
+func (r *Runner) ledgerExists(ctx context.Context) (bool, error) {
+	_, err := r.si.ContentTypeFetch(ctx, r.opts.LedgerContentType)
+	if err == nil {
+		return true, nil
+	}
+
+	apiErr := &management.APIError{}
+	if errors.Is(err, management.ErrNotFound) || (errors.As(err, &apiErr) && apiErr.ErrorCode == errContentTypeNotFound) {
+		return false, nil
+	}
+	return false, fmt.Errorf("unable to fetch the migration ledger: %w", err)
+}
+
+func (r *Runner) ensureLedger(ctx context.Context) error {
+	exists, err := r.ledgerExists(ctx)
+	if err != nil || exists {
+		return err
+	}
+
+	input, err := management.NewContentTypeInput(r.opts.LedgerContentType, "Migrations", schema.Fields(
+		schema.Text("title").DisplayName("ID").Mandatory().Unique(),
+		schema.MultiLineText("description"),
+		schema.Date("applied_at"),
+	), schema.ValidationOptions{})
+	if err != nil {
+		return err
+	}
+	input.Description = management.StringRef("Content migrations applied to this stack")
+
+	if _, err := r.si.ContentTypeCreate(ctx, input); err != nil {
+		return fmt.Errorf("unable to create the migration ledger: %w", err)
+	}
+	return nil
+}
+
+// applied returns the ids of the migrations recorded in the ledger.
+func (r *Runner) applied(ctx context.Context) (map[string]bool, error) {
+	exists, err := r.ledgerExists(ctx)
+	if err != nil || !exists {
+		return map[string]bool{}, err
+	}
+
+	entries, err := r.si.EntryFetchAll(ctx, r.opts.LedgerContentType)
+	if err != nil {
+		return nil, fmt.Errorf("unable to read the migration ledger: %w", err)
+	}
+
+	result := map[string]bool{}
+	for _, entry := range entries {
+		if id, ok := entry.Fields["title"].(string); ok {
+			result[id] = true
+		}
+	}
+	return result, nil
+}
+
+func (r *Runner) record(ctx context.Context, migration Migration) error {
+	_, err := r.si.EntryCreate(ctx, &management.EntryInput{
+		ContentTypeUID: r.opts.LedgerContentType,
+		Fields: map[string]interface{}{
+			"title":       migration.ID,
+			"description": migration.Description,
+			"applied_at":  time.Now().UTC().Format(time.RFC3339),
+		},
+	})
+	if err != nil {
+		return fmt.Errorf("unable to record migration %s: %w", migration.ID, err)
+	}
+	return nil
+}
@@ -0,0 +1,378 @@
+package migrate
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/labd/contentstack-go-sdk/management"
+	"github.com/labd/contentstack-go-sdk/schema"
+)
+
+// fakeStack is a minimal in memory stack with a blog content type.
+type fakeStack struct {
+	mu       sync.Mutex
+	ledger   []map[string]interface{}
+	schemas  map[string]json.RawMessage
+	options  json.RawMessage
+	entries  []map[string]interface{}
+	requests []string
+
+	// ledgerStatus and ledgerError override the response when fetching the
+	// ledger content type.
+	ledgerStatus int
+	ledgerError  string
+}
+
+func newFakeStack(t *testing.T) (*fakeStack, *management.StackInstance) {
+	t.Helper()
+	blog, err := schema.Fields(
+		schema.Text("title").Mandatory().Unique(),
+		schema.Text("summary"),
+	).Raw()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	fake := &fakeStack{
+		schemas: map[string]json.RawMessage{"blog": blog},
+		options: json.RawMessage(`{"title": "title", "is_page": true, "singleton": false, "sub_title": [], "url_pattern": "/:title", "url_prefix": "/blog/"}`),
+		entries: []map[string]interface{}{
+			{"uid": "entry_1", "locale": "en-us", "title": "Hello", "summary": "World", "tags": []interface{}{"featured"}},
+		},
+	}
+	server := httptest.NewServer(fake)
+	t.Cleanup(server.Close)
+
+	client, err := management.NewClient(management.ClientConfig{BaseURL: server.URL, AuthToken: "token"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	stack, err := client.Stack(&management.StackAuth{ApiKey: "api-key"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	return fake, stack
+}
+
+func (f *fakeStack) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	body := map[string]json.RawMessage{}
+	_ = json.NewDecoder(r.Body).Decode(&body)
+	if r.Method != http.MethodGet {
+		f.requests = append(f.requests, r.Method+" "+r.URL.Path)
+	}
+
+	write := func(v interface{}) {
+		_ = json.NewEncoder(w).Encode(v)
+	}
+	path := strings.TrimPrefix(r.URL.Path, "/v3/")
+	switch {
+	case r.Method == http.MethodGet && path == "locales":
+		write(map[string]interface{}{"locales": []interface{}{map[string]string{"code": "en-us"}}, "count": 1})
+
+	case r.Method == http.MethodGet && path == "content_types":
+		items := []interface{}{}
+		for uid, s := range f.schemas {
+			items = append(items, map[string]interface{}{"uid": uid, "schema": s})
+		}
+		write(map[string]interface{}{"content_types": items, "count": len(items)})
+
+	case r.Method == http.MethodPost && path == "content_types/":
+		input := struct {
+			UID    string          `json:"uid"`
+			Schema json.RawMessage `json:"schema"`
+		}{}
+		_ = json.Unmarshal(body["content_type"], &input)
+		f.schemas[input.UID] = input.Schema
+		write(map[string]interface{}{"content_type": input})
+
+	case path == "content_types/blog":
+		if r.Method == http.MethodPut {
+			input := struct {
+				Schema  json.RawMessage `json:"schema"`
+				Options json.RawMessage `json:"options"`
+			}{}
+			_ = json.Unmarshal(body["content_type"], &input)
+			f.schemas["blog"] = input.Schema
+			// Like the API, a missing value resets the options
+			f.options = input.Options
+		}
+		write(map[string]interface{}{"content_type": map[string]interface{}{"uid": "blog", "title": "Blog", "schema": f.schemas["blog"], "options": f.options}})
+
+	case r.Method == http.MethodGet && path == "content_types/migrations":
+		if f.ledgerStatus != 0 {
+			w.WriteHeader(f.ledgerStatus)
+			fmt.Fprint(w, f.ledgerError)
+			return
+		}
+		if _, ok := f.schemas["migrations"]; !ok {
+			// Like the API, an unknown content type is not a 404
+			w.WriteHeader(http.StatusUnprocessableEntity)
+			fmt.Fprint(w, `{"error_message": "The Content Type 'migrations' was not found. Please try again.", "error_code": 118}`)
+			return
+		}
+		write(map[string]interface{}{"content_type": map[string]interface{}{"uid": "migrations", "title": "Migrations", "schema": f.schemas["migrations"]}})
+
+	case path == "content_types/migrations/entries":
+		if r.Method == http.MethodPost {
+			entry := map[string]interface{}{}
+			_ = json.Unmarshal(body["entry"], &entry)
+			f.ledger = append(f.ledger, entry)
+			write(map[string]interface{}{"entry": entry})
+			return
+		}
+		write(map[string]interface{}{"entries": f.ledger, "count": len(f.ledger)})
+
+	case path == "content_types/blog/entries":
+		write(map[string]interface{}{"entries": f.entries, "count": len(f.entries)})
+
+	case r.Method == http.MethodPut && strings.HasPrefix(path, "content_types/blog/entries/"):
+		entry := map[string]interface{}{}
+		_ = json.Unmarshal(body["entry"], &entry)
+		entry["uid"] = path[strings.LastIndex(path, "/")+1:]
+		entry["locale"] = r.URL.Query().Get("locale")
+		f.entries = []map[string]interface{}{entry}
+		write(map[string]interface{}{"entry": entry})
+
+	default:
+		w.WriteHeader(http.StatusNotFound)
+		fmt.Fprintf(w, `{"error_message": "unexpected request %s %s"}`, r.Method, r.URL.Path)
+	}
+}
+
+func TestRunner_Run(t *testing.T) {
+	fake, stack := newFakeStack(t)
+	fake.schemas["migrations"] = json.RawMessage(`[]`)
+	fake.ledger = []map[string]interface{}{{"uid": "l1", "title": "001"}}
+
+	order := []string{}
+	migration := func(id string) Func {
+		return func(ctx context.Context, s *Stack) error {
+			order = append(order, id)
+			return nil
+		}
+	}
+
+	runner := NewRunner(stack, Options{})
+	runner.Register("003", "third", migration("003"))
+	runner.Register("001", "first", migration("001"))
+	runner.Register("002", "second", migration("002"))
+
+	applied, err := runner.Run(context.Background())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(applied) != 2 {
+		t.Errorf("len(applied) = %d, want 2", len(applied))
+	}
+	if want := []string{"002", "003"}; !reflect.DeepEqual(order, want) {
+		t.Errorf("order = %v, want %v", order, want)
+	}
+	if len(fake.ledger) != 3 || fake.ledger[2]["title"] != "003" {
+		t.Errorf("unexpected ledger %v", fake.ledger)
+	}
+
+	pending, err := runner.Pending(context.Background())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(pending) != 0 {
+		t.Errorf("len(pending) = %d, want 0", len(pending))
+	}
+}
+
+func TestRunner_LedgerMissing(t *testing.T) {
+	tests := []struct {
+		name         string
+		ledgerStatus int
+		ledgerError  string
+		wantErr      error
+	}{
+		{
+			name: "unknown content type",
+		},
+		{
+			name:         "not found",
+			ledgerStatus: http.StatusNotFound,
+			ledgerError:  `{"error_message": "Not found."}`,
+		},
+		{
+			name:         "forbidden",
+			ledgerStatus: http.StatusForbidden,
+			ledgerError:  `{"error_message": "You're not allowed in here unless you're logged in.", "error_code": 162}`,
+			wantErr:      management.ErrForbidden,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fake, stack := newFakeStack(t)
+			fake.ledgerStatus = tt.ledgerStatus
+			fake.ledgerError = tt.ledgerError
+
+			ran := false
+			runner := NewRunner(stack, Options{})
+			runner.Register("001", "first", func(ctx context.Context, s *Stack) error {
+				ran = true
+				return nil
+			})
+
+			_, err := runner.Run(context.Background())
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("error = %v, want %v", err, tt.wantErr)
+			}
+			if tt.wantErr != nil {
+				if ran {
+					t.Error("migration ran although the ledger could not be fetched")
+				}
+				if len(fake.requests) != 0 {
+					t.Errorf("unexpected requests %v", fake.requests)
+				}
+				return
+			}
+			if !ran {
+				t.Error("migration did not run")
+			}
+			if _, ok := fake.schemas["migrations"]; !ok {
+				t.Error("ledger content type is not created")
+			}
+		})
+	}
+}
+
+func TestRunner_DryRun(t *testing.T) {
+	fake, stack := newFakeStack(t)
+
+	runner := NewRunner(stack, Options{DryRun: true})
+	runner.Register("001", "rename", func(ctx context.Context, s *Stack) error {
+		return s.RenameField(ctx, "blog", "summary", "intro")
+	})
+
+	applied, err := runner.Run(context.Background())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(applied) != 1 {
+		t.Errorf("len(applied) = %d, want 1", len(applied))
+	}
+	if len(fake.requests) != 0 {
+		t.Errorf("dry run changed the stack: %v", fake.requests)
+	}
+}
+
+func TestStack_RenameField(t *testing.T) {
+	fake, stack := newFakeStack(t)
+
+	runner := NewRunner(stack, Options{})
+	runner.Register("001", "rename", func(ctx context.Context, s *Stack) error {
+		return s.RenameField(ctx, "blog", "summary", "intro")
+	})
+	if _, err := runner.Run(context.Background()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	want := []string{
+		"POST /v3/content_types/",
+		"PUT /v3/content_types/blog",
+		"PUT /v3/content_types/blog/entries/entry_1",
+		"PUT /v3/content_types/blog",
+		"POST /v3/content_types/migrations/entries",
+	}
+	if !reflect.DeepEqual(fake.requests, want) {
+		t.Errorf("requests = %v, want %v", fake.requests, want)
+	}
+
+	s, err := schema.Parse(fake.schemas["blog"])
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if s.Field("summary") != nil || s.Field("intro") == nil {
+		t.Errorf("unexpected schema %s", fake.schemas["blog"])
+	}
+	if fake.entries[0]["intro"] != "World" || fake.entries[0]["summary"] != nil {
+		t.Errorf("unexpected entry %v", fake.entries[0])
+	}
+
+	// The options and tags are kept
+	options := management.ContentTypeOptions{}
+	if err := json.Unmarshal(fake.options, &options); err != nil || !options.IsPage || options.UrlPattern != "/:title" {
+		t.Errorf("unexpected options %s", fake.options)
+	}
+	if !reflect.DeepEqual(fake.entries[0]["tags"], []interface{}{"featured"}) {
+		t.Errorf("unexpected tags %v", fake.entries[0]["tags"])
+	}
+}
+
+func TestStack_RenameFieldResume(t *testing.T) {
+	tests := []struct {
+		name     string
+		fields   []*schema.FieldBuilder
+		entry    map[string]interface{}
+		requests []string
+	}{
+		{
+			name:   "new field added",
+			fields: []*schema.FieldBuilder{schema.Text("summary"), schema.Text("intro")},
+			entry:  map[string]interface{}{"uid": "entry_1", "locale": "en-us", "title": "Hello", "summary": "World"},
+			requests: []string{
+				"PUT /v3/content_types/blog/entries/entry_1",
+				"PUT /v3/content_types/blog",
+			},
+		},
+		{
+			name:   "values copied",
+			fields: []*schema.FieldBuilder{schema.Text("summary"), schema.Text("intro")},
+			entry:  map[string]interface{}{"uid": "entry_1", "locale": "en-us", "title": "Hello", "intro": "World"},
+			requests: []string{
+				"PUT /v3/content_types/blog",
+			},
+		},
+		{
+			name:     "renamed",
+			fields:   []*schema.FieldBuilder{schema.Text("intro")},
+			entry:    map[string]interface{}{"uid": "entry_1", "locale": "en-us", "title": "Hello", "intro": "World"},
+			requests: nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fake, stack := newFakeStack(t)
+			raw, err := schema.Fields(append([]*schema.FieldBuilder{schema.Text("title").Mandatory()}, tt.fields...)...).Raw()
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			fake.schemas["blog"] = raw
+			fake.entries = []map[string]interface{}{tt.entry}
+
+			s := &Stack{si: stack}
+			if err := s.RenameField(context.Background(), "blog", "summary", "intro"); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !reflect.DeepEqual(fake.requests, tt.requests) {
+				t.Errorf("requests = %v, want %v", fake.requests, tt.requests)
+			}
+
+			result, err := schema.Parse(fake.schemas["blog"])
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if result.Field("summary") != nil || result.Field("intro") == nil {
+				t.Errorf("unexpected schema %s", fake.schemas["blog"])
+			}
+			if fake.entries[0]["intro"] != "World" || fake.entries[0]["summary"] != nil {
+				t.Errorf("unexpected entry %v", fake.entries[0])
+			}
+		})
+	}
+}
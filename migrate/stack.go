@@ -0,0 +1,210 @@
+package migrate
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/labd/contentstack-go-sdk/management"
+	"github.com/labd/contentstack-go-sdk/schema"
+)
+
+// Stack is passed to the migrations and provides helpers for common
+// transformations. All helpers honor the dry run option.
+type Stack struct {
+	si     *management.StackInstance
+	dryRun bool
+	log    func(message string)
+}
+
+// Instance returns the stack instance for changes which are not covered by
+// the helpers. Check DryRun before changing the stack.
+func (s *Stack) Instance() *management.StackInstance {
+	return s.si
+}
+
+// DryRun returns whether the migrations run without changing the stack.
+func (s *Stack) DryRun() bool {
+	return s.dryRun
+}
+
+func (s *Stack) logf(format string, args ...interface{}) {
+	if s.log == nil {
+		return
+	}
+	message := fmt.Sprintf(format, args...)
+	if s.dryRun {
+		message = "[dry run] " + message
+	}
+	s.log(message)
+}
+
+// UpdateContentType changes the schema of the content type with fn. The
+// title, description and options of the content type are kept.
+func (s *Stack) UpdateContentType(ctx context.Context, uid string, fn func(schema.Schema) (schema.Schema, error)) error {
+	contentType, err := s.si.ContentTypeFetch(ctx, uid)
+	if err != nil {
+		return err
+	}
+	current, err := contentType.ParseSchema()
+	if err != nil {
+		return fmt.Errorf("content type %s: %w", uid, err)
+	}
+
+	// Work on a copy, so the diff compares with the original schema
+	fields, err := schema.Parse(contentType.Schema)
+	if err != nil {
+		return fmt.Errorf("content type %s: %w", uid, err)
+	}
+	fields, err = fn(fields)
+	if err != nil {
+		return err
+	}
+
+	changes := schema.Diff(current, fields)
+	if len(changes) == 0 {
+		return nil
+	}
+	for _, change := range changes {
+		s.logf("content type %s: %s", uid, change)
+	}
+	if s.dryRun {
+		return nil
+	}
+
+	raw, err := fields.Raw()
+	if err != nil {
+		return err
+	}
+	_, err = s.si.ContentTypeUpdate(ctx, uid, management.ContentTypeInput{
+		Title:       management.StringRef(contentType.Title),
+		Description: management.StringRef(contentType.Description),
+		Schema:      raw,
+		Options:     contentType.Options,
+	})
+	return err
+}
+
+// AddField adds the field at the end of the schema of the content type.
+func (s *Stack) AddField(ctx context.Context, contentTypeUID string, field schema.Field) error {
+	return s.UpdateContentType(ctx, contentTypeUID, func(fields schema.Schema) (schema.Schema, error) {
+		if fields.Field(field.UID) != nil {
+			return nil, fmt.Errorf("content type %s already has a field %s", contentTypeUID, field.UID)
+		}
+		return append(fields, field), nil
+	})
+}
+
+// RemoveField removes the field and its values from the content type.
+func (s *Stack) RemoveField(ctx context.Context, contentTypeUID string, fieldUID string) error {
+	return s.UpdateContentType(ctx, contentTypeUID, func(fields schema.Schema) (schema.Schema, error) {
+		result := schema.Schema{}
+		for _, field := range fields {
+			if field.UID != fieldUID {
+				result = append(result, field)
+			}
+		}
+		if len(result) == len(fields) {
+			return nil, fmt.Errorf("content type %s has no field %s", contentTypeUID, fieldUID)
+		}
+		return result, nil
+	})
+}
+
+// RenameField changes the uid of a top level field. Contentstack doesn't support
+// changing the uid of a field, so a copy of the field is added, the values are
+// copied in every locale and the old field is removed.
+//
+// A rename which failed halfway is resumed when running it again: when both
+// fields exist the remaining values are copied, and when only the new field
+// exists the field is already renamed.
+func (s *Stack) RenameField(ctx context.Context, contentTypeUID string, oldUID string, newUID string) error {
+	renamed := false
+	err := s.UpdateContentType(ctx, contentTypeUID, func(fields schema.Schema) (schema.Schema, error) {
+		switch {
+		case fields.Field(newUID) != nil && fields.Field(oldUID) == nil:
+			renamed = true
+			return fields, nil
+		case fields.Field(newUID) != nil:
+			s.logf("content type %s: resume renaming %s to %s", contentTypeUID, oldUID, newUID)
+			return fields, nil
+		}
+
+		// Add the new field directly after the old field
+		for i, field := range fields {
+			if field.UID == oldUID {
+				field.UID = newUID
+				result := append(schema.Schema{}, fields[:i+1]...)
+				result = append(result, field)
+				return append(result, fields[i+1:]...), nil
+			}
+		}
+		return nil, fmt.Errorf("content type %s has no field %s", contentTypeUID, oldUID)
+	})
+	if err != nil || renamed {
+		return err
+	}
+
+	locales, err := s.si.LocaleFetchAll(ctx)
+	if err != nil {
+		return err
+	}
+	for _, locale := range locales {
+		query := management.NewEntryQuery().Locale(locale.Code)
+		err := s.BackfillEntries(ctx, contentTypeUID, query, func(entry *management.Entry) (bool, error) {
+			// Skip the entries which are not localized in the locale
+			if entry.Locale != "" && entry.Locale != locale.Code {
+				return false, nil
+			}
+
+			value, ok := entry.Fields[oldUID]
+			if !ok {
+				return false, nil
+			}
+			entry.Fields[newUID] = value
+			delete(entry.Fields, oldUID)
+			return true, nil
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	return s.RemoveField(ctx, contentTypeUID, oldUID)
+}
+
+// BackfillEntries calls fn for every entry of the content type matching the
+// query, and updates the entries for which fn returns true. The entries are
+// fetched before any entry is updated, so updates don't affect which entries
+// are visited. The tags of the entries are kept, unless changed by fn.
+func (s *Stack) BackfillEntries(ctx context.Context, contentTypeUID string, query *management.EntryQuery, fn func(entry *management.Entry) (bool, error)) error {
+	entries, err := s.si.EntryFind(ctx, contentTypeUID, query)
+	if err != nil {
+		return err
+	}
+
+	for i := range entries {
+		entry := &entries[i]
+		changed, err := fn(entry)
+		if err != nil {
+			return fmt.Errorf("entry %s: %w", entry.UID, err)
+		}
+		if !changed {
+			continue
+		}
+
+		s.logf("update entry %s/%s (%s)", contentTypeUID, entry.UID, entry.Locale)
+		if s.dryRun {
+			continue
+		}
+		_, err = s.si.EntryUpdate(ctx, entry.UID, &management.EntryInput{
+			ContentTypeUID: contentTypeUID,
+			Locale:         entry.Locale,
+			Fields:         entry.Fields,
+			Tags:           entry.Tags,
+		})
+		if err != nil {
+			return fmt.Errorf("unable to update entry %s: %w", entry.UID, err)
+		}
+	}
+	return nil
+}
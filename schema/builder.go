@@ -0,0 +1,321 @@
+package schema
+
+import (
+	"encoding/json"
+	"strings"
+)
+
+// FieldBuilder builds a single field. Use one of the constructors such as
+// Text or Reference to create a builder, and Fields to create the schema.
+//
+//	s := schema.Fields(
+//		schema.Text("title").Mandatory().Unique(),
+//		schema.Reference("author", "person"),
+//		schema.Group("links",
+//			schema.Text("label"),
+//			schema.Link("link"),
+//		).Multiple(),
+//	)
+type FieldBuilder struct {
+	field Field
+}
+
+// BlockBuilder builds a single block of a modular blocks field.
+type BlockBuilder struct {
+	block Block
+}
+
+// Fields returns the schema of the given fields.
+func Fields(fields ...*FieldBuilder) Schema {
+	result := make(Schema, len(fields))
+	for i, field := range fields {
+		result[i] = field.Field()
+	}
+	return result
+}
+
+func newField(uid string, dataType string) *FieldBuilder {
+	return &FieldBuilder{
+		field: Field{
+			UID:         uid,
+			DataType:    dataType,
+			DisplayName: displayName(uid),
+		},
+	}
+}
+
+func (b *FieldBuilder) metadata() *FieldMetadata {
+	if b.field.FieldMetadata == nil {
+		b.field.FieldMetadata = &FieldMetadata{}
+	}
+	return b.field.FieldMetadata
+}
+
+// Text returns a single line text field.
+func Text(uid string) *FieldBuilder {
+	return newField(uid, TypeText)
+}
+
+// MultiLineText returns a multi line text field.
+func MultiLineText(uid string) *FieldBuilder {
+	b := newField(uid, TypeText)
+	b.metadata().Multiline = true
+	return b
+}
+
+// RichText returns a (HTML) rich text field.
+func RichText(uid string) *FieldBuilder {
+	b := newField(uid, TypeText)
+	b.metadata().AllowRichText = true
+	b.metadata().RichTextType = "advanced"
+	return b
+}
+
+// Markdown returns a markdown field.
+func Markdown(uid string) *FieldBuilder {
+	b := newField(uid, TypeText)
+	b.metadata().Markdown = true
+	return b
+}
+
+// JSONRTE returns a JSON rich text editor field.
+func JSONRTE(uid string) *FieldBuilder {
+	b := newField(uid, TypeJSON)
+	b.metadata().AllowJSONRTE = true
+	b.metadata().RichTextType = "advanced"
+	return b
+}
+
+// Number returns a number field.
+func Number(uid string) *FieldBuilder {
+	return newField(uid, TypeNumber)
+}
+
+// Boolean returns a boolean field.
+func Boolean(uid string) *FieldBuilder {
+	return newField(uid, TypeBoolean)
+}
+
+// Date returns a date field.
+func Date(uid string) *FieldBuilder {
+	return newField(uid, TypeDate)
+}
+
+// File returns a file field.
+func File(uid string) *FieldBuilder {
+	return newField(uid, TypeFile)
+}
+
+// Image returns a file field which only accepts images.
+func Image(uid string) *FieldBuilder {
+	b := newField(uid, TypeFile)
+	b.metadata().Image = true
+	return b
+}
+
+// Link returns a link field.
+func Link(uid string) *FieldBuilder {
+	return newField(uid, TypeLink)
+}
+
+// Select returns a dropdown select field with the given choices.
+func Select(uid string, choices ...string) *FieldBuilder {
+	b := newField(uid, TypeText)
+	b.field.DisplayType = "dropdown"
+	b.field.Enum = &Enum{Choices: make([]Choice, len(choices))}
+	for i, choice := range choices {
+		b.field.Enum.Choices[i] = Choice{Value: choice}
+	}
+	return b
+}
+
+// Reference returns a reference field to one or more content types.
+func Reference(uid string, contentTypes ...string) *FieldBuilder {
+	b := newField(uid, TypeReference)
+	b.field.ReferenceTo = StringList(contentTypes)
+	b.metadata().RefMultipleContentTypes = len(contentTypes) > 1
+	return b
+}
+
+// Group returns a group field containing the given fields.
+func Group(uid string, fields ...*FieldBuilder) *FieldBuilder {
+	b := newField(uid, TypeGroup)
+	b.field.Schema = Fields(fields...)
+	return b
+}
+
+// GlobalField returns a field which includes the given global field.
+func GlobalField(uid string, globalFieldUID string) *FieldBuilder {
+	b := newField(uid, TypeGlobalField)
+	b.field.ReferenceTo = StringList{globalFieldUID}
+	return b
+}
+
+// Blocks returns a modular blocks field with the given blocks.
+func Blocks(uid string, blocks ...*BlockBuilder) *FieldBuilder {
+	b := newField(uid, TypeBlocks)
+	b.field.Multiple = true
+	b.field.Blocks = make([]Block, len(blocks))
+	for i, block := range blocks {
+		b.field.Blocks[i] = block.Block()
+	}
+	return b
+}
+
+// Taxonomies returns a taxonomy field for the given taxonomies.
+func Taxonomies(uid string, taxonomies ...string) *FieldBuilder {
+	b := newField(uid, TypeTaxonomy)
+	b.field.Multiple = true
+	for _, taxonomy := range taxonomies {
+		b.field.Taxonomies = append(b.field.Taxonomies, Taxonomy{TaxonomyUID: taxonomy})
+	}
+	return b
+}
+
+// Extension returns a custom field using the given extension, storing its
+// value with the given data type.
+func Extension(uid string, dataType string, extensionUID string) *FieldBuilder {
+	b := newField(uid, dataType)
+	b.field.ExtensionUID = extensionUID
+	b.metadata().Extension = true
+	return b
+}
+
+// DisplayName sets the name of the field. By default the name is derived from
+// the uid.
+func (b *FieldBuilder) DisplayName(name string) *FieldBuilder {
+	b.field.DisplayName = name
+	return b
+}
+
+func (b *FieldBuilder) Mandatory() *FieldBuilder {
+	b.field.Mandatory = true
+	return b
+}
+
+func (b *FieldBuilder) Unique() *FieldBuilder {
+	b.field.Unique = true
+	return b
+}
+
+// Multiple allows multiple values. For reference fields multiple entries can
+// be referenced.
+func (b *FieldBuilder) Multiple() *FieldBuilder {
+	b.field.Multiple = true
+	if b.field.DataType == TypeReference {
+		b.metadata().RefMultiple = true
+	}
+	return b
+}
+
+func (b *FieldBuilder) NonLocalizable() *FieldBuilder {
+	b.field.NonLocalizable = true
+	return b
+}
+
+func (b *FieldBuilder) Description(description string) *FieldBuilder {
+	b.metadata().Description = description
+	return b
+}
+
+func (b *FieldBuilder) Instruction(instruction string) *FieldBuilder {
+	b.metadata().Instruction = instruction
+	return b
+}
+
+func (b *FieldBuilder) Placeholder(placeholder string) *FieldBuilder {
+	b.metadata().Placeholder = placeholder
+	return b
+}
+
+func (b *FieldBuilder) Default(value interface{}) *FieldBuilder {
+	b.metadata().DefaultValue = value
+	return b
+}
+
+// Format validates the value of a text field with the regular expression.
+func (b *FieldBuilder) Format(pattern string, message string) *FieldBuilder {
+	b.field.Format = pattern
+	if message != "" {
+		b.field.ErrorMessages = map[string]string{"format": message}
+	}
+	return b
+}
+
+// Min sets the minimum value of a number field.
+func (b *FieldBuilder) Min(min float64) *FieldBuilder {
+	b.field.Min = &min
+	return b
+}
+
+// Max sets the maximum value of a number field.
+func (b *FieldBuilder) Max(max float64) *FieldBuilder {
+	b.field.Max = &max
+	return b
+}
+
+// Instances limits the number of values of a multiple field. Zero means no
+// limit.
+func (b *FieldBuilder) Instances(min int, max int) *FieldBuilder {
+	if min > 0 {
+		b.field.MinInstance = &min
+	}
+	if max > 0 {
+		b.field.MaxInstance = &max
+	}
+	return b
+}
+
+// Extensions limits the file extensions of a file field.
+func (b *FieldBuilder) Extensions(extensions ...string) *FieldBuilder {
+	b.field.Extensions = extensions
+	return b
+}
+
+// Config sets the configuration of a custom field.
+func (b *FieldBuilder) Config(config json.RawMessage) *FieldBuilder {
+	b.field.Config = config
+	return b
+}
+
+// Field returns the built field.
+func (b *FieldBuilder) Field() Field {
+	return b.field
+}
+
+// NewBlock returns a block with the given fields.
+func NewBlock(uid string, title string, fields ...*FieldBuilder) *BlockBuilder {
+	return &BlockBuilder{
+		block: Block{
+			UID:    uid,
+			Title:  title,
+			Schema: Fields(fields...),
+		},
+	}
+}
+
+// NewGlobalFieldBlock returns a block which uses the schema of the global
+// field.
+func NewGlobalFieldBlock(uid string, title string, globalFieldUID string) *BlockBuilder {
+	return &BlockBuilder{
+		block: Block{
+			UID:         uid,
+			Title:       title,
+			ReferenceTo: globalFieldUID,
+		},
+	}
+}
+
+// Block returns the built block.
+func (b *BlockBuilder) Block() Block {
+	return b.block
+}
+
+// displayName converts a uid such as "meta_title" to "Meta title".
+func displayName(uid string) string {
+	name := strings.TrimSpace(strings.ReplaceAll(uid, "_", " "))
+	if name == "" {
+		return uid
+	}
+	return strings.ToUpper(name[:1]) + name[1:]
+}
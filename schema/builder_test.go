@@ -0,0 +1,143 @@
+package schema
+
+import (
+	"encoding/json"
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestFields(t *testing.T) {
+	s := Fields(
+		Text("title").Mandatory().Unique(),
+		Reference("author", "person").Multiple(),
+		Group("links",
+			Text("label"),
+			Link("link"),
+		).Multiple(),
+		Blocks("sections",
+			NewBlock("hero", "Hero", Text("heading")),
+			NewGlobalFieldBlock("seo", "SEO", "seo"),
+		),
+		GlobalField("meta", "seo"),
+	)
+
+	raw, err := s.Raw()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	want := `[
+		{"uid": "title", "data_type": "text", "display_name": "Title", "mandatory": true, "unique": true},
+		{"uid": "author", "data_type": "reference", "display_name": "Author", "multiple": true,
+		 "reference_to": ["person"], "field_metadata": {"ref_multiple": true}},
+		{"uid": "links", "data_type": "group", "display_name": "Links", "multiple": true, "schema": [
+			{"uid": "label", "data_type": "text", "display_name": "Label"},
+			{"uid": "link", "data_type": "link", "display_name": "Link"}
+		]},
+		{"uid": "sections", "data_type": "blocks", "display_name": "Sections", "multiple": true, "blocks": [
+			{"uid": "hero", "title": "Hero", "schema": [{"uid": "heading", "data_type": "text", "display_name": "Heading"}]},
+			{"uid": "seo", "title": "SEO", "reference_to": "seo"}
+		]},
+		{"uid": "meta", "data_type": "global_field", "display_name": "Meta", "reference_to": "seo"}
+	]`
+
+	var got, expected interface{}
+	if err := json.Unmarshal(raw, &got); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := json.Unmarshal([]byte(want), &expected); err != nil {
+		t.Fatalf("invalid expected json: %v", err)
+	}
+	if !reflect.DeepEqual(got, expected) {
+		t.Errorf("Raw() = %s", raw)
+	}
+}
+
+func TestSchema_ValidateContentType(t *testing.T) {
+	tests := []struct {
+		name     string
+		schema   Schema
+		opts     ValidationOptions
+		problems []string
+	}{
+		{
+			name: "valid",
+			schema: Fields(
+				Text("title").Mandatory().Unique(),
+				Reference("author", "person"),
+			),
+			opts: ValidationOptions{ContentTypes: []string{"person"}},
+		},
+		{
+			name:     "missing title",
+			schema:   Fields(Text("name")),
+			problems: []string{"a field with uid 'title' is required"},
+		},
+		{
+			name:     "title not mandatory",
+			schema:   Fields(Text("title")),
+			problems: []string{"title: must be mandatory"},
+		},
+		{
+			name:   "title not unique",
+			schema: Fields(Text("title").Mandatory()),
+		},
+		{
+			name:     "title not unique when required",
+			schema:   Fields(Text("title").Mandatory()),
+			opts:     ValidationOptions{RequireUniqueTitle: true},
+			problems: []string{"title: must be unique"},
+		},
+		{
+			name: "duplicate uids",
+			schema: Fields(
+				Text("title").Mandatory().Unique(),
+				Group("group", Text("name"), Number("name")),
+				Text("title"),
+			),
+			problems: []string{"group.name: uid is not unique", "title: uid is not unique"},
+		},
+		{
+			name: "unknown references",
+			schema: Fields(
+				Text("title").Mandatory().Unique(),
+				Reference("author", "person", "company"),
+				Blocks("sections", NewGlobalFieldBlock("seo", "SEO", "seo")),
+			),
+			opts: ValidationOptions{ContentTypes: []string{"person"}, GlobalFields: []string{}},
+			problems: []string{
+				`author: referenced content type "company" does not exist`,
+				`sections.seo: global field "seo" does not exist`,
+			},
+		},
+		{
+			name: "invalid uid",
+			schema: Fields(
+				Text("title").Mandatory().Unique(),
+				Text("Invalid-UID"),
+			),
+			problems: []string{"Invalid-UID: uid must start with a letter and contain only lowercase letters, digits and underscores"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.schema.ValidateContentType(tt.opts)
+			if tt.problems == nil {
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				return
+			}
+
+			var validationErr *ValidationError
+			if !errors.As(err, &validationErr) {
+				t.Fatalf("expected a ValidationError, got %v", err)
+			}
+			if !reflect.DeepEqual(validationErr.Problems, tt.problems) {
+				t.Errorf("Problems = %q, want %q", validationErr.Problems, tt.problems)
+			}
+		})
+	}
+}
@@ -0,0 +1,498 @@
+package schema
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+type ChangeType string
+
+const (
+	ChangeAdded    ChangeType = "added"
+	ChangeRemoved  ChangeType = "removed"
+	ChangeRenamed  ChangeType = "renamed"
+	ChangeRetyped  ChangeType = "retyped"
+	ChangeModified ChangeType = "modified"
+)
+
+// Change is a single change between two schemas. Fields which were removed
+// while a field of the same kind was added at the same level are reported as
+// a rename candidate, with both the old and the new field set. Since the uid
+// of a field cannot be changed the data of a renamed field is lost unless it
+// is migrated.
+type Change struct {
+	Type ChangeType
+
+	// Path is the location of the field, e.g. "sections.hero.title" for a
+	// field of the hero block of the sections field.
+	Path string
+
+	Old *Field
+	New *Field
+
+	// Details describes the changed options of a modified field.
+	Details []string
+
+	// DataLoss indicates whether existing content is lost or invalidated
+	// when applying the change.
+	DataLoss bool
+}
+
+// Safe returns whether the change can be applied without losing content.
+func (c Change) Safe() bool {
+	return !c.DataLoss
+}
+
+func (c Change) String() string {
+	b := strings.Builder{}
+	switch c.Type {
+	case ChangeAdded:
+		fmt.Fprintf(&b, "+ %s (%s) added", c.Path, c.New.Kind())
+	case ChangeRemoved:
+		fmt.Fprintf(&b, "- %s (%s) removed", c.Path, c.Old.Kind())
+	case ChangeRenamed:
+		fmt.Fprintf(&b, "~ %s possibly renamed to %s", c.Path, c.New.UID)
+	case ChangeRetyped:
+		fmt.Fprintf(&b, "! %s changed from %s to %s", c.Path, describe(c.Old), describe(c.New))
+	case ChangeModified:
+		fmt.Fprintf(&b, "~ %s modified", c.Path)
+	}
+	if len(c.Details) > 0 {
+		fmt.Fprintf(&b, ": %s", strings.Join(c.Details, ", "))
+	}
+	if c.DataLoss {
+		b.WriteString(" [data loss]")
+	}
+	return b.String()
+}
+
+// Changes is the list of changes between two schemas.
+type Changes []Change
+
+// HasDataLoss returns whether any of the changes loses content.
+func (c Changes) HasDataLoss() bool {
+	for _, change := range c {
+		if change.DataLoss {
+			return true
+		}
+	}
+	return false
+}
+
+// Render returns a human readable plan of the changes.
+func (c Changes) Render() string {
+	if len(c) == 0 {
+		return "No changes.\n"
+	}
+
+	counts := map[ChangeType]int{}
+	lossy := 0
+	b := strings.Builder{}
+	for _, change := range c {
+		counts[change.Type]++
+		if change.DataLoss {
+			lossy++
+		}
+		b.WriteString(change.String())
+		b.WriteString("\n")
+	}
+
+	fmt.Fprintf(&b, "\nPlan: %d to add, %d to change, %d to remove, %d possibly renamed.\n",
+		counts[ChangeAdded], counts[ChangeModified]+counts[ChangeRetyped], counts[ChangeRemoved], counts[ChangeRenamed])
+	if lossy > 0 {
+		fmt.Fprintf(&b, "Warning: %d change(s) will lose or invalidate existing content.\n", lossy)
+	}
+	return b.String()
+}
+
+// Diff returns the changes needed to go from the old to the new schema. It
+// recurses into groups, modular blocks and global fields.
+func Diff(old Schema, new Schema) Changes {
+	changes := Changes{}
+	diffSchema("", old, new, &changes)
+	return changes
+}
+
+// DiffRaw parses both schemas and returns the changes between them.
+func DiffRaw(old json.RawMessage, new json.RawMessage) (Changes, error) {
+	o, err := Parse(old)
+	if err != nil {
+		return nil, err
+	}
+	n, err := Parse(new)
+	if err != nil {
+		return nil, err
+	}
+	return Diff(o, n), nil
+}
+
+func diffSchema(prefix string, old Schema, new Schema, changes *Changes) {
+	added := []*Field{}
+	for i := range new {
+		n := &new[i]
+		if o := old.Field(n.UID); o != nil {
+			diffField(joinPath(prefix, n.UID), o, n, changes)
+		} else {
+			added = append(added, n)
+		}
+	}
+
+	removed := []*Field{}
+	for i := range old {
+		if new.Field(old[i].UID) == nil {
+			removed = append(removed, &old[i])
+		}
+	}
+
+	// Pair removed and added fields of the same kind as rename candidates
+	renamed := map[*Field]bool{}
+	for _, o := range removed {
+		var match *Field
+		for _, n := range added {
+			if !renamed[n] && n.DataType == o.DataType && n.Kind() == o.Kind() {
+				match = n
+				break
+			}
+		}
+
+		if match == nil {
+			*changes = append(*changes, Change{
+				Type:     ChangeRemoved,
+				Path:     joinPath(prefix, o.UID),
+				Old:      o,
+				DataLoss: true,
+			})
+			continue
+		}
+
+		renamed[match] = true
+		*changes = append(*changes, Change{
+			Type:     ChangeRenamed,
+			Path:     joinPath(prefix, o.UID),
+			Old:      o,
+			New:      match,
+			DataLoss: true,
+		})
+	}
+
+	for _, n := range added {
+		if renamed[n] {
+			continue
+		}
+		change := Change{
+			Type: ChangeAdded,
+			Path: joinPath(prefix, n.UID),
+			New:  n,
+		}
+		if n.Mandatory {
+			change.Details = []string{"mandatory, existing entries require a value"}
+		}
+		*changes = append(*changes, change)
+	}
+}
+
+func diffField(path string, old *Field, new *Field, changes *Changes) {
+	if old.DataType != new.DataType || (old.DataType == TypeGlobalField && !equalStrings(old.ReferenceTo, new.ReferenceTo)) {
+		*changes = append(*changes, Change{
+			Type:     ChangeRetyped,
+			Path:     path,
+			Old:      old,
+			New:      new,
+			DataLoss: true,
+		})
+		return
+	}
+
+	change := Change{
+		Type: ChangeModified,
+		Path: path,
+		Old:  old,
+		New:  new,
+	}
+	detail := func(dataLoss bool, format string, args ...interface{}) {
+		change.Details = append(change.Details, fmt.Sprintf(format, args...))
+		change.DataLoss = change.DataLoss || dataLoss
+	}
+
+	if old.Kind() != new.Kind() {
+		detail(false, "kind %s => %s", old.Kind(), new.Kind())
+	}
+	if old.DisplayName != new.DisplayName {
+		detail(false, "display name %q => %q", old.DisplayName, new.DisplayName)
+	}
+	if old.Multiple != new.Multiple {
+		detail(old.Multiple, "multiple %t => %t", old.Multiple, new.Multiple)
+	}
+	// Existing entries may be missing the value or contain duplicates
+	if old.Mandatory != new.Mandatory {
+		detail(new.Mandatory, "mandatory %t => %t", old.Mandatory, new.Mandatory)
+	}
+	if old.Unique != new.Unique {
+		detail(new.Unique, "unique %t => %t", old.Unique, new.Unique)
+	}
+	if old.NonLocalizable != new.NonLocalizable {
+		detail(new.NonLocalizable, "non localizable %t => %t", old.NonLocalizable, new.NonLocalizable)
+	}
+	if old.DataType == TypeReference {
+		if removed := missing(old.ReferenceTo, new.ReferenceTo); len(removed) > 0 {
+			detail(true, "no longer references %s", strings.Join(removed, ", "))
+		}
+		if added := missing(new.ReferenceTo, old.ReferenceTo); len(added) > 0 {
+			detail(false, "now references %s", strings.Join(added, ", "))
+		}
+	}
+	if removed := missing(choices(old), choices(new)); len(removed) > 0 {
+		detail(true, "choices removed: %s", strings.Join(removed, ", "))
+	}
+	if added := missing(choices(new), choices(old)); len(added) > 0 {
+		detail(false, "choices added: %s", strings.Join(added, ", "))
+	}
+	if removed := missing(taxonomies(old), taxonomies(new)); len(removed) > 0 {
+		detail(true, "taxonomies removed: %s", strings.Join(removed, ", "))
+	}
+	if added := missing(taxonomies(new), taxonomies(old)); len(added) > 0 {
+		detail(false, "taxonomies added: %s", strings.Join(added, ", "))
+	}
+	if !equalInt(old.MaxInstance, new.MaxInstance) {
+		detail(new.MaxInstance != nil && (old.MaxInstance == nil || *new.MaxInstance < *old.MaxInstance),
+			"max instances %s => %s", formatInt(old.MaxInstance), formatInt(new.MaxInstance))
+	}
+	if !equalInt(old.MinInstance, new.MinInstance) {
+		detail(false, "min instances %s => %s", formatInt(old.MinInstance), formatInt(new.MinInstance))
+	}
+	if !equalFloat(old.Min, new.Min) || !equalFloat(old.Max, new.Max) {
+		detail(false, "range changed")
+	}
+	if old.Format != new.Format {
+		detail(false, "format %q => %q", old.Format, new.Format)
+	}
+	if old.ExtensionUID != new.ExtensionUID {
+		detail(false, "extension %q => %q", old.ExtensionUID, new.ExtensionUID)
+	}
+	if !equalJSON(old.FieldMetadata, new.FieldMetadata) && old.Kind() == new.Kind() {
+		detail(false, "metadata changed")
+	}
+
+	if len(change.Details) > 0 {
+		*changes = append(*changes, change)
+	}
+
+	// Recurse into the nested fields
+	switch old.DataType {
+	case TypeGroup:
+		diffSchema(path, old.Schema, new.Schema, changes)
+	case TypeGlobalField:
+		// The API includes the schema of the global field, but it is not
+		// part of the field itself and thus often omitted.
+		if len(old.Schema) > 0 && len(new.Schema) > 0 {
+			diffSchema(path, old.Schema, new.Schema, changes)
+		}
+	case TypeBlocks:
+		diffBlocks(path, old.Blocks, new.Blocks, changes)
+	}
+}
+
+func diffBlocks(path string, old []Block, new []Block, changes *Changes) {
+	find := func(blocks []Block, uid string) *Block {
+		for i := range blocks {
+			if blocks[i].UID == uid {
+				return &blocks[i]
+			}
+		}
+		return nil
+	}
+
+	for i := range new {
+		n := &new[i]
+		blockPath := joinPath(path, n.UID)
+		o := find(old, n.UID)
+		switch {
+		case o == nil:
+			*changes = append(*changes, Change{
+				Type:    ChangeAdded,
+				Path:    blockPath,
+				New:     blockField(n),
+				Details: []string{"block"},
+			})
+		case o.ReferenceTo != n.ReferenceTo:
+			*changes = append(*changes, Change{
+				Type:     ChangeRetyped,
+				Path:     blockPath,
+				Old:      blockField(o),
+				New:      blockField(n),
+				DataLoss: true,
+			})
+		default:
+			if o.Title != n.Title {
+				*changes = append(*changes, Change{
+					Type:    ChangeModified,
+					Path:    blockPath,
+					Old:     blockField(o),
+					New:     blockField(n),
+					Details: []string{fmt.Sprintf("title %q => %q", o.Title, n.Title)},
+				})
+			}
+			if o.ReferenceTo == "" || (len(o.Schema) > 0 && len(n.Schema) > 0) {
+				diffSchema(blockPath, o.Schema, n.Schema, changes)
+			}
+		}
+	}
+
+	for i := range old {
+		if find(new, old[i].UID) == nil {
+			*changes = append(*changes, Change{
+				Type:     ChangeRemoved,
+				Path:     joinPath(path, old[i].UID),
+				Old:      blockField(&old[i]),
+				Details:  []string{"block"},
+				DataLoss: true,
+			})
+		}
+	}
+}
+
+// blockField represents a block as a field, so changes to blocks can be
+// reported the same way as changes to fields.
+func blockField(b *Block) *Field {
+	f := &Field{
+		UID:         b.UID,
+		DisplayName: b.Title,
+		DataType:    TypeGroup,
+		Schema:      b.Schema,
+	}
+	if b.ReferenceTo != "" {
+		f.DataType = TypeGlobalField
+		f.ReferenceTo = StringList{b.ReferenceTo}
+	}
+	return f
+}
+
+func describe(f *Field) string {
+	if f.DataType == TypeGlobalField && len(f.ReferenceTo) > 0 {
+		return fmt.Sprintf("global field %s", f.ReferenceTo[0])
+	}
+	return string(f.Kind())
+}
+
+func joinPath(prefix string, uid string) string {
+	if prefix == "" {
+		return uid
+	}
+	return prefix + "." + uid
+}
+
+func choices(f *Field) []string {
+	if f.Enum == nil {
+		return nil
+	}
+	result := make([]string, len(f.Enum.Choices))
+	for i, choice := range f.Enum.Choices {
+		result[i] = fmt.Sprint(choice.Value)
+	}
+	return result
+}
+
+func taxonomies(f *Field) []string {
+	result := make([]string, len(f.Taxonomies))
+	for i, taxonomy := range f.Taxonomies {
+		result[i] = taxonomy.TaxonomyUID
+	}
+	return result
+}
+
+// missing returns the values of a which are not in b.
+func missing(a []string, b []string) []string {
+	result := []string{}
+	for _, value := range a {
+		found := false
+		for _, other := range b {
+			if value == other {
+				found = true
+				break
+			}
+		}
+		if !found {
+			result = append(result, value)
+		}
+	}
+	return result
+}
+
+func equalStrings(a []string, b []string) bool {
+	return len(missing(a, b)) == 0 && len(missing(b, a)) == 0
+}
+
+func equalInt(a *int, b *int) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return *a == *b
+}
+
+func equalFloat(a *float64, b *float64) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return *a == *b
+}
+
+func formatInt(v *int) string {
+	if v == nil {
+		return "unlimited"
+	}
+	return fmt.Sprint(*v)
+}
+
+// equalJSON compares the JSON representation of both values, ignoring
+// properties with a zero value.
+func equalJSON(a interface{}, b interface{}) bool {
+	return reflect.DeepEqual(normalize(a), normalize(b))
+}
+
+func normalize(v interface{}) interface{} {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil
+	}
+	var result interface{}
+	if err := json.Unmarshal(data, &result); err != nil {
+		return nil
+	}
+	return dropZero(result)
+}
+
+func dropZero(v interface{}) interface{} {
+	switch value := v.(type) {
+	case map[string]interface{}:
+		result := map[string]interface{}{}
+		for key, item := range value {
+			if item = dropZero(item); item != nil {
+				result[key] = item
+			}
+		}
+		if len(result) == 0 {
+			return nil
+		}
+		return result
+	case []interface{}:
+		if len(value) == 0 {
+			return nil
+		}
+		return value
+	case bool:
+		if !value {
+			return nil
+		}
+	case string:
+		if value == "" {
+			return nil
+		}
+	case float64:
+		if value == 0 {
+			return nil
+		}
+	}
+	return v
+}
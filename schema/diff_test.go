@@ -0,0 +1,165 @@
+package schema
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestDiff(t *testing.T) {
+	old := Fields(
+		Text("title").Mandatory().Unique(),
+		Text("summary"),
+		Number("rating"),
+		Select("color", "red", "green", "blue"),
+		Reference("author", "person", "company").Multiple(),
+		Group("details",
+			Text("label"),
+			Text("obsolete_code"),
+		),
+		Blocks("sections",
+			NewBlock("hero", "Hero", Text("heading")),
+			NewBlock("quote", "Quote", Text("quote")),
+		),
+		Text("tags").Multiple(),
+	)
+	new := Fields(
+		Text("title").Mandatory().Unique().DisplayName("Name"),
+		Text("description"),
+		Text("rating"),
+		Select("color", "red", "green", "yellow"),
+		Reference("author", "person").Multiple(),
+		Group("details",
+			Text("label"),
+			Boolean("visible").Mandatory(),
+		),
+		Blocks("sections",
+			NewBlock("hero", "Hero", Text("heading"), Text("subheading")),
+		),
+		Text("tags"),
+	)
+
+	changes := Diff(old, new)
+
+	got := make([]string, len(changes))
+	for i, change := range changes {
+		got[i] = change.String()
+	}
+
+	want := []string{
+		`~ title modified: display name "Title" => "Name"`,
+		`! rating changed from number to single_line_text [data loss]`,
+		`~ color modified: choices removed: blue, choices added: yellow [data loss]`,
+		`~ author modified: no longer references company, metadata changed [data loss]`,
+		`- details.obsolete_code (single_line_text) removed [data loss]`,
+		`+ details.visible (boolean) added: mandatory, existing entries require a value`,
+		`+ sections.hero.subheading (single_line_text) added`,
+		`- sections.quote (group) removed: block [data loss]`,
+		`~ tags modified: multiple true => false [data loss]`,
+		`~ summary possibly renamed to description [data loss]`,
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Diff() =\n%s\nwant\n%s", strings.Join(got, "\n"), strings.Join(want, "\n"))
+	}
+	if !changes.HasDataLoss() {
+		t.Error("HasDataLoss() = false, want true")
+	}
+	if !strings.Contains(changes.Render(), "Plan: 2 to add, 5 to change, 2 to remove, 1 possibly renamed.") {
+		t.Errorf("Render() = %s", changes.Render())
+	}
+}
+
+func TestDiff_Constraints(t *testing.T) {
+	tests := []struct {
+		name string
+		old  *FieldBuilder
+		new  *FieldBuilder
+		want string
+	}{
+		{"mandatory", Text("summary"), Text("summary").Mandatory(), "~ summary modified: mandatory false => true [data loss]"},
+		{"optional", Text("summary").Mandatory(), Text("summary"), "~ summary modified: mandatory true => false"},
+		{"unique", Text("summary"), Text("summary").Unique(), "~ summary modified: unique false => true [data loss]"},
+		{"not unique", Text("summary").Unique(), Text("summary"), "~ summary modified: unique true => false"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			changes := Diff(Fields(tt.old), Fields(tt.new))
+			if len(changes) != 1 {
+				t.Fatalf("Diff() = %v, want 1 change", changes)
+			}
+			if got := changes[0].String(); got != tt.want {
+				t.Errorf("Diff() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDiff_NoChanges(t *testing.T) {
+	s, err := Parse(json.RawMessage(testSchema))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	raw, err := s.Raw()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	changes, err := DiffRaw(json.RawMessage(testSchema), raw)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(changes) != 0 {
+		t.Errorf("Diff() = %v, want no changes", changes)
+	}
+	if changes.Render() != "No changes.\n" {
+		t.Errorf("Render() = %q", changes.Render())
+	}
+}
+
+func TestDiff_GlobalFieldSchemaOmitted(t *testing.T) {
+	expanded := Fields(Text("meta_title"), Text("meta_description"))
+
+	// The API includes the schema of used global fields, while a locally
+	// defined schema usually doesn't.
+	remote := Fields(
+		GlobalField("seo", "seo"),
+		Blocks("sections", NewGlobalFieldBlock("meta", "Meta", "seo")),
+	)
+	remote[0].Schema = expanded
+	remote[1].Blocks[0].Schema = expanded
+
+	local := Fields(
+		GlobalField("seo", "seo"),
+		Blocks("sections", NewGlobalFieldBlock("meta", "Meta", "seo")),
+	)
+
+	if changes := Diff(remote, local); len(changes) != 0 {
+		t.Errorf("Diff() = %v, want no changes", changes)
+	}
+	if changes := Diff(local, remote); len(changes) != 0 {
+		t.Errorf("Diff() = %v, want no changes", changes)
+	}
+
+	// Both schemas are expanded, so the nested fields are compared
+	changed := Fields(
+		GlobalField("seo", "seo"),
+		Blocks("sections", NewGlobalFieldBlock("meta", "Meta", "seo")),
+	)
+	changed[0].Schema = Fields(Text("meta_title"))
+	changed[1].Blocks[0].Schema = Fields(Text("meta_title"))
+
+	got := []string{}
+	for _, change := range Diff(remote, changed) {
+		got = append(got, change.String())
+	}
+	want := []string{
+		`- seo.meta_description (single_line_text) removed [data loss]`,
+		`- sections.meta.meta_description (single_line_text) removed [data loss]`,
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Diff() =\n%s\nwant\n%s", strings.Join(got, "\n"), strings.Join(want, "\n"))
+	}
+}
@@ -0,0 +1,115 @@
+package schema
+
+// Kind is the kind of a field as shown in the Contentstack UI. It is derived
+// from the data type and the metadata of the field.
+type Kind string
+
+const (
+	KindSingleLineText Kind = "single_line_text"
+	KindMultiLineText  Kind = "multi_line_text"
+	KindRichText       Kind = "rich_text"
+	KindMarkdown       Kind = "markdown"
+	KindJSONRTE        Kind = "json_rte"
+	KindNumber         Kind = "number"
+	KindBoolean        Kind = "boolean"
+	KindDate           Kind = "date"
+	KindFile           Kind = "file"
+	KindLink           Kind = "link"
+	KindSelect         Kind = "select"
+	KindReference      Kind = "reference"
+	KindGroup          Kind = "group"
+	KindBlocks         Kind = "modular_blocks"
+	KindGlobalField    Kind = "global_field"
+	KindTaxonomy       Kind = "taxonomy"
+	KindExtension      Kind = "extension"
+	KindJSON           Kind = "json"
+	KindUnknown        Kind = "unknown"
+)
+
+// Kind returns the kind of the field.
+func (f *Field) Kind() Kind {
+	meta := f.FieldMetadata
+	if meta == nil {
+		meta = &FieldMetadata{}
+	}
+
+	if f.ExtensionUID != "" || meta.Extension {
+		return KindExtension
+	}
+
+	switch f.DataType {
+	case TypeText:
+		switch {
+		case f.Enum != nil:
+			return KindSelect
+		case meta.Markdown:
+			return KindMarkdown
+		case meta.AllowRichText:
+			return KindRichText
+		case meta.Multiline:
+			return KindMultiLineText
+		}
+		return KindSingleLineText
+	case TypeNumber:
+		if f.Enum != nil {
+			return KindSelect
+		}
+		return KindNumber
+	case TypeJSON:
+		if meta.AllowJSONRTE {
+			return KindJSONRTE
+		}
+		return KindJSON
+	case TypeBoolean:
+		return KindBoolean
+	case TypeDate:
+		return KindDate
+	case TypeFile:
+		return KindFile
+	case TypeLink:
+		return KindLink
+	case TypeReference:
+		return KindReference
+	case TypeGroup:
+		return KindGroup
+	case TypeBlocks:
+		return KindBlocks
+	case TypeGlobalField:
+		return KindGlobalField
+	case TypeTaxonomy:
+		return KindTaxonomy
+	}
+	return KindUnknown
+}
+
+// WalkFunc is called for every field visited by Walk. The path contains the
+// uids of the parent fields and blocks, followed by the uid of the field.
+type WalkFunc func(path []string, field *Field) error
+
+// Walk calls fn for every field of the schema, recursing into groups, the
+// blocks of modular blocks fields and the schema of global fields (when
+// included in the field). Walking stops at the first error.
+func Walk(s Schema, fn WalkFunc) error {
+	return walk(nil, s, fn)
+}
+
+func walk(parent []string, s Schema, fn WalkFunc) error {
+	for i := range s {
+		field := &s[i]
+		path := append(append([]string{}, parent...), field.UID)
+		if err := fn(path, field); err != nil {
+			return err
+		}
+
+		if err := walk(path, field.Schema, fn); err != nil {
+			return err
+		}
+		for j := range field.Blocks {
+			block := &field.Blocks[j]
+			if err := walk(append(append([]string{}, path...), block.UID), block.Schema, fn); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,104 @@
+package schema
+
+import (
+	"bytes"
+	"encoding/json"
+	"reflect"
+	"strings"
+)
+
+// properties holds the properties of a JSON object which are not mapped onto
+// a struct field, or which are mapped but contain an explicit zero value
+// (e.g. "mandatory": false) which would otherwise be lost due to omitempty.
+type properties struct {
+	extra    map[string]json.RawMessage
+	explicit map[string]json.RawMessage
+}
+
+// unmarshalObject decodes data into dst, which must be a pointer to a struct,
+// and returns the properties required to marshal the object losslessly.
+func unmarshalObject(data []byte, dst interface{}) (properties, error) {
+	if err := json.Unmarshal(data, dst); err != nil {
+		return properties{}, err
+	}
+
+	raw := map[string]json.RawMessage{}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return properties{}, err
+	}
+
+	known := jsonKeys(reflect.TypeOf(dst).Elem())
+	result := properties{}
+	for key, value := range raw {
+		if !known[key] {
+			if result.extra == nil {
+				result.extra = map[string]json.RawMessage{}
+			}
+			result.extra[key] = value
+			continue
+		}
+		if isZeroJSON(value) {
+			if result.explicit == nil {
+				result.explicit = map[string]json.RawMessage{}
+			}
+			result.explicit[key] = value
+		}
+	}
+	return result, nil
+}
+
+// marshalObject marshals src and adds the extra properties and the explicit
+// zero values which were omitted.
+func marshalObject(src interface{}, extra map[string]json.RawMessage, explicit map[string]json.RawMessage) ([]byte, error) {
+	data, err := json.Marshal(src)
+	if err != nil {
+		return nil, err
+	}
+	if len(extra) == 0 && len(explicit) == 0 {
+		return data, nil
+	}
+
+	result := map[string]json.RawMessage{}
+	if err := json.Unmarshal(data, &result); err != nil {
+		return nil, err
+	}
+	for key, value := range explicit {
+		if _, ok := result[key]; !ok {
+			result[key] = value
+		}
+	}
+	for key, value := range extra {
+		if _, ok := result[key]; !ok {
+			result[key] = value
+		}
+	}
+	return json.Marshal(result)
+}
+
+// jsonKeys returns the JSON property names of the fields of the struct.
+func jsonKeys(t reflect.Type) map[string]bool {
+	result := map[string]bool{}
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+		name := strings.Split(field.Tag.Get("json"), ",")[0]
+		if name == "-" {
+			continue
+		}
+		if name == "" {
+			name = field.Name
+		}
+		result[name] = true
+	}
+	return result
+}
+
+func isZeroJSON(value json.RawMessage) bool {
+	switch string(bytes.TrimSpace(value)) {
+	case "false", "0", `""`, "null", "[]", "{}":
+		return true
+	}
+	return false
+}
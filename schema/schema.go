@@ -0,0 +1,317 @@
+// Package schema provides a typed model of the schema of content types and
+// global fields, which can be converted to and from the JSON representation
+// used by the Contentstack API without losing any properties.
+package schema
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// Data types of the fields as used by Contentstack.
+const (
+	TypeText        = "text"
+	TypeNumber      = "number"
+	TypeBoolean     = "boolean"
+	TypeDate        = "isodate"
+	TypeFile        = "file"
+	TypeLink        = "link"
+	TypeReference   = "reference"
+	TypeGroup       = "group"
+	TypeBlocks      = "blocks"
+	TypeGlobalField = "global_field"
+	TypeJSON        = "json"
+	TypeTaxonomy    = "taxonomy"
+)
+
+// Schema is the list of fields of a content type, global field, group or
+// block.
+type Schema []Field
+
+// Parse parses the raw schema as returned by the API.
+func Parse(data json.RawMessage) (Schema, error) {
+	result := Schema{}
+	if len(data) == 0 {
+		return result, nil
+	}
+	if err := json.Unmarshal(data, &result); err != nil {
+		return nil, fmt.Errorf("Unable to parse schema: %w", err)
+	}
+	return result, nil
+}
+
+// Raw returns the schema as expected by the API.
+func (s Schema) Raw() (json.RawMessage, error) {
+	if s == nil {
+		s = Schema{}
+	}
+	data, err := json.Marshal(s)
+	if err != nil {
+		return nil, fmt.Errorf("Unable to serialize schema: %w", err)
+	}
+	return json.RawMessage(data), nil
+}
+
+// Field returns the field with the given uid, or nil if there is no such
+// field. Nested fields are not searched.
+func (s Schema) Field(uid string) *Field {
+	for i := range s {
+		if s[i].UID == uid {
+			return &s[i]
+		}
+	}
+	return nil
+}
+
+// Field is a single field of a schema. Properties which are not known by this
+// package are kept in Extra so the field can be sent back to the API as is.
+type Field struct {
+	UID            string         `json:"uid"`
+	DataType       string         `json:"data_type"`
+	DisplayName    string         `json:"display_name"`
+	Mandatory      bool           `json:"mandatory,omitempty"`
+	Unique         bool           `json:"unique,omitempty"`
+	Multiple       bool           `json:"multiple,omitempty"`
+	NonLocalizable bool           `json:"non_localizable,omitempty"`
+	FieldMetadata  *FieldMetadata `json:"field_metadata,omitempty"`
+
+	// DisplayType and Enum are used by select fields.
+	DisplayType string `json:"display_type,omitempty"`
+	Enum        *Enum  `json:"enum,omitempty"`
+
+	// ReferenceTo contains the referenced content types of a reference
+	// field, or the global field of a global field.
+	ReferenceTo StringList `json:"reference_to,omitempty"`
+
+	// Schema contains the fields of a group, or of the referenced global
+	// field when returned by the API.
+	Schema Schema `json:"schema,omitempty"`
+
+	// Blocks contains the blocks of a modular blocks field.
+	Blocks []Block `json:"blocks,omitempty"`
+
+	// Taxonomies contains the taxonomies of a taxonomy field.
+	Taxonomies []Taxonomy `json:"taxonomies,omitempty"`
+
+	// Validation options
+	Format        string            `json:"format,omitempty"`
+	ErrorMessages map[string]string `json:"error_messages,omitempty"`
+	Min           *float64          `json:"min,omitempty"`
+	Max           *float64          `json:"max,omitempty"`
+	MinInstance   *int              `json:"min_instance,omitempty"`
+	MaxInstance   *int              `json:"max_instance,omitempty"`
+	Extensions    []string          `json:"extensions,omitempty"`
+
+	// ExtensionUID and Config are used by custom (extension) fields.
+	ExtensionUID string          `json:"extension_uid,omitempty"`
+	Config       json.RawMessage `json:"config,omitempty"`
+
+	// Extra contains the properties which are not known by this package.
+	Extra map[string]json.RawMessage `json:"-"`
+
+	explicit map[string]json.RawMessage
+}
+
+func (f *Field) UnmarshalJSON(data []byte) error {
+	type field Field
+	v := field{}
+	props, err := unmarshalObject(data, &v)
+	if err != nil {
+		return err
+	}
+	*f = Field(v)
+	f.Extra = props.extra
+	f.explicit = props.explicit
+	return nil
+}
+
+func (f Field) MarshalJSON() ([]byte, error) {
+	type field Field
+	v := field(f)
+	extra := f.Extra
+
+	// The global field of a global field is a string instead of a list
+	if f.DataType == TypeGlobalField && len(f.ReferenceTo) == 1 {
+		ref, err := json.Marshal(f.ReferenceTo[0])
+		if err != nil {
+			return nil, err
+		}
+		extra = make(map[string]json.RawMessage, len(f.Extra)+1)
+		for key, value := range f.Extra {
+			extra[key] = value
+		}
+		extra["reference_to"] = ref
+		v.ReferenceTo = nil
+	}
+	return marshalObject(v, extra, f.explicit)
+}
+
+// FieldMetadata contains the metadata of a field, which mostly describes how
+// the field is edited.
+type FieldMetadata struct {
+	Description  string      `json:"description,omitempty"`
+	Instruction  string      `json:"instruction,omitempty"`
+	Placeholder  string      `json:"placeholder,omitempty"`
+	DefaultValue interface{} `json:"default_value,omitempty"`
+	Version      int         `json:"version,omitempty"`
+
+	// Text fields
+	Multiline     bool   `json:"multiline,omitempty"`
+	Markdown      bool   `json:"markdown,omitempty"`
+	AllowRichText bool   `json:"allow_rich_text,omitempty"`
+	RichTextType  string `json:"rich_text_type,omitempty"`
+
+	// JSON fields
+	AllowJSONRTE bool `json:"allow_json_rte,omitempty"`
+
+	// Reference fields
+	RefMultiple             bool `json:"ref_multiple,omitempty"`
+	RefMultipleContentTypes bool `json:"ref_multiple_content_types,omitempty"`
+
+	// File fields
+	Image bool `json:"image,omitempty"`
+
+	// Custom fields
+	Extension bool `json:"extension,omitempty"`
+
+	// Extra contains the properties which are not known by this package.
+	Extra map[string]json.RawMessage `json:"-"`
+
+	explicit map[string]json.RawMessage
+}
+
+func (m *FieldMetadata) UnmarshalJSON(data []byte) error {
+	type metadata FieldMetadata
+	v := metadata{}
+	props, err := unmarshalObject(data, &v)
+	if err != nil {
+		return err
+	}
+	*m = FieldMetadata(v)
+	m.Extra = props.extra
+	m.explicit = props.explicit
+	return nil
+}
+
+func (m FieldMetadata) MarshalJSON() ([]byte, error) {
+	type metadata FieldMetadata
+	return marshalObject(metadata(m), m.Extra, m.explicit)
+}
+
+// Enum contains the choices of a select field.
+type Enum struct {
+	Advanced bool     `json:"advanced"`
+	Choices  []Choice `json:"choices"`
+
+	// Extra contains the properties which are not known by this package.
+	Extra map[string]json.RawMessage `json:"-"`
+
+	explicit map[string]json.RawMessage
+}
+
+func (e *Enum) UnmarshalJSON(data []byte) error {
+	type enum Enum
+	v := enum{}
+	props, err := unmarshalObject(data, &v)
+	if err != nil {
+		return err
+	}
+	*e = Enum(v)
+	e.Extra = props.extra
+	e.explicit = props.explicit
+	return nil
+}
+
+func (e Enum) MarshalJSON() ([]byte, error) {
+	type enum Enum
+	return marshalObject(enum(e), e.Extra, e.explicit)
+}
+
+// Choice is a single choice of a select field. The key is only used when the
+// enum is advanced.
+type Choice struct {
+	Value interface{} `json:"value"`
+	Key   string      `json:"key,omitempty"`
+}
+
+// Block is a single block of a modular blocks field. A block either has its
+// own schema, or refers to a global field.
+type Block struct {
+	UID         string `json:"uid"`
+	Title       string `json:"title"`
+	Schema      Schema `json:"schema,omitempty"`
+	ReferenceTo string `json:"reference_to,omitempty"`
+
+	// Extra contains the properties which are not known by this package.
+	Extra map[string]json.RawMessage `json:"-"`
+
+	explicit map[string]json.RawMessage
+}
+
+func (b *Block) UnmarshalJSON(data []byte) error {
+	type block Block
+	v := block{}
+	props, err := unmarshalObject(data, &v)
+	if err != nil {
+		return err
+	}
+	*b = Block(v)
+	b.Extra = props.extra
+	b.explicit = props.explicit
+	return nil
+}
+
+func (b Block) MarshalJSON() ([]byte, error) {
+	type block Block
+	return marshalObject(block(b), b.Extra, b.explicit)
+}
+
+// Taxonomy is a taxonomy which can be used in a taxonomy field.
+type Taxonomy struct {
+	TaxonomyUID    string `json:"taxonomy_uid"`
+	MaxTerms       int    `json:"max_terms,omitempty"`
+	Mandatory      bool   `json:"mandatory,omitempty"`
+	NonLocalizable bool   `json:"non_localizable,omitempty"`
+
+	// Extra contains the properties which are not known by this package.
+	Extra map[string]json.RawMessage `json:"-"`
+
+	explicit map[string]json.RawMessage
+}
+
+func (t *Taxonomy) UnmarshalJSON(data []byte) error {
+	type taxonomy Taxonomy
+	v := taxonomy{}
+	props, err := unmarshalObject(data, &v)
+	if err != nil {
+		return err
+	}
+	*t = Taxonomy(v)
+	t.Extra = props.extra
+	t.explicit = props.explicit
+	return nil
+}
+
+func (t Taxonomy) MarshalJSON() ([]byte, error) {
+	type taxonomy Taxonomy
+	return marshalObject(taxonomy(t), t.Extra, t.explicit)
+}
+
+// StringList unmarshals a JSON value that is either a string or a list of
+// strings. Contentstack uses a string for the global field of a global field
+// and a list of content types for a reference field.
+type StringList []string
+
+func (l *StringList) UnmarshalJSON(data []byte) error {
+	var s string
+	if err := json.Unmarshal(data, &s); err == nil {
+		*l = StringList{s}
+		return nil
+	}
+	var values []string
+	if err := json.Unmarshal(data, &values); err != nil {
+		return fmt.Errorf("StringList: cannot unmarshal %s into string or list of strings", data)
+	}
+	*l = values
+	return nil
+}
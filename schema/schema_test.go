@@ -0,0 +1,216 @@
+package schema
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+)
+
+const testSchema = `[
+	{
+		"display_name": "Title",
+		"uid": "title",
+		"data_type": "text",
+		"mandatory": true,
+		"unique": true,
+		"field_metadata": {"_default": true, "version": 3},
+		"multiple": false,
+		"non_localizable": false
+	},
+	{
+		"display_name": "Body",
+		"uid": "body",
+		"data_type": "text",
+		"field_metadata": {"allow_rich_text": true, "rich_text_type": "advanced", "options": [], "description": ""},
+		"reference_to": ["sys_assets"],
+		"unknown_property": {"nested": [1, 2, 3]}
+	},
+	{
+		"display_name": "Rating",
+		"uid": "rating",
+		"data_type": "number",
+		"display_type": "dropdown",
+		"enum": {"advanced": true, "choices": [{"key": "Low", "value": 1}, {"key": "High", "value": 5}]},
+		"min": 1,
+		"max": 5
+	},
+	{
+		"display_name": "Published",
+		"uid": "published",
+		"data_type": "isodate",
+		"startDate": null,
+		"endDate": null
+	},
+	{
+		"display_name": "Image",
+		"uid": "image",
+		"data_type": "file",
+		"extensions": ["png", "jpg"],
+		"field_metadata": {"image": true}
+	},
+	{"display_name": "Link", "uid": "link", "data_type": "link"},
+	{"display_name": "Featured", "uid": "featured", "data_type": "boolean"},
+	{
+		"display_name": "Author",
+		"uid": "author",
+		"data_type": "reference",
+		"reference_to": ["person", "organization"],
+		"field_metadata": {"ref_multiple": true, "ref_multiple_content_types": true}
+	},
+	{
+		"display_name": "SEO",
+		"uid": "seo",
+		"data_type": "global_field",
+		"reference_to": "seo",
+		"schema": [{"display_name": "Description", "uid": "description", "data_type": "text"}]
+	},
+	{
+		"display_name": "Details",
+		"uid": "details",
+		"data_type": "group",
+		"multiple": true,
+		"max_instance": 3,
+		"schema": [{"display_name": "Label", "uid": "label", "data_type": "text", "format": "^[a-z]+$", "error_messages": {"format": "lowercase only"}}]
+	},
+	{
+		"display_name": "Sections",
+		"uid": "sections",
+		"data_type": "blocks",
+		"multiple": true,
+		"blocks": [
+			{"title": "Hero", "uid": "hero", "schema": [{"display_name": "Heading", "uid": "heading", "data_type": "text"}]},
+			{"title": "SEO", "uid": "seo_block", "reference_to": "seo"}
+		]
+	},
+	{
+		"display_name": "Content",
+		"uid": "content",
+		"data_type": "json",
+		"field_metadata": {"allow_json_rte": true, "embed_entry": false},
+		"plugins": []
+	},
+	{
+		"display_name": "Taxonomies",
+		"uid": "taxonomies",
+		"data_type": "taxonomy",
+		"taxonomies": [{"taxonomy_uid": "colors", "max_terms": 5, "mandatory": false, "non_localizable": false}]
+	},
+	{
+		"display_name": "Color",
+		"uid": "color",
+		"data_type": "text",
+		"extension_uid": "blt123",
+		"config": {"theme": "dark"},
+		"field_metadata": {"extension": true}
+	}
+]`
+
+func TestSchema_RoundTrip(t *testing.T) {
+	s, err := Parse(json.RawMessage(testSchema))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	raw, err := s.Raw()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	var got, want interface{}
+	if err := json.Unmarshal(raw, &got); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := json.Unmarshal([]byte(testSchema), &want); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("round trip changed the schema\n got: %s", raw)
+	}
+}
+
+func TestSchema_Parse(t *testing.T) {
+	s, err := Parse(json.RawMessage(testSchema))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	body := s.Field("body")
+	if _, ok := body.Extra["unknown_property"]; !ok {
+		t.Error("unknown property not kept in Extra")
+	}
+
+	author := s.Field("author")
+	if !reflect.DeepEqual([]string(author.ReferenceTo), []string{"person", "organization"}) {
+		t.Errorf("ReferenceTo = %v", author.ReferenceTo)
+	}
+
+	seo := s.Field("seo")
+	if !reflect.DeepEqual([]string(seo.ReferenceTo), []string{"seo"}) {
+		t.Errorf("ReferenceTo = %v", seo.ReferenceTo)
+	}
+
+	rating := s.Field("rating")
+	if rating.Enum == nil || len(rating.Enum.Choices) != 2 || rating.Enum.Choices[1].Key != "High" {
+		t.Errorf("Enum = %+v", rating.Enum)
+	}
+	if rating.Max == nil || *rating.Max != 5 {
+		t.Errorf("Max = %v", rating.Max)
+	}
+}
+
+func TestField_Kind(t *testing.T) {
+	s, err := Parse(json.RawMessage(testSchema))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	want := map[string]Kind{
+		"title":      KindSingleLineText,
+		"body":       KindRichText,
+		"rating":     KindSelect,
+		"published":  KindDate,
+		"image":      KindFile,
+		"link":       KindLink,
+		"featured":   KindBoolean,
+		"author":     KindReference,
+		"seo":        KindGlobalField,
+		"details":    KindGroup,
+		"sections":   KindBlocks,
+		"content":    KindJSONRTE,
+		"taxonomies": KindTaxonomy,
+		"color":      KindExtension,
+	}
+	for uid, kind := range want {
+		if got := s.Field(uid).Kind(); got != kind {
+			t.Errorf("Kind() of %s = %s, want %s", uid, got, kind)
+		}
+	}
+}
+
+func TestWalk(t *testing.T) {
+	s, err := Parse(json.RawMessage(testSchema))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	paths := []string{}
+	err = Walk(s, func(path []string, field *Field) error {
+		if len(path) > 1 {
+			b, _ := json.Marshal(path)
+			paths = append(paths, string(b))
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	want := []string{
+		`["seo","description"]`,
+		`["details","label"]`,
+		`["sections","hero","heading"]`,
+	}
+	if !reflect.DeepEqual(paths, want) {
+		t.Errorf("paths = %v, want %v", paths, want)
+	}
+}
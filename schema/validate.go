@@ -0,0 +1,198 @@
+package schema
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+var uidPattern = regexp.MustCompile(`^[a-z][a-z0-9_]*$`)
+
+// ValidationOptions configures the validation of a schema.
+type ValidationOptions struct {
+	// ContentTypes contains the uids of the content types which can be
+	// referenced. When creating a content type which references itself its
+	// own uid must be included. References are not validated when nil.
+	ContentTypes []string
+
+	// GlobalFields contains the uids of the global fields which can be
+	// used. Global fields are not validated when nil.
+	GlobalFields []string
+
+	// RequireUniqueTitle requires the title of a content type to be unique.
+	// Contentstack itself only requires a mandatory title.
+	RequireUniqueTitle bool
+}
+
+// ValidationError contains all problems found while validating a schema.
+type ValidationError struct {
+	Problems []string
+}
+
+func (e *ValidationError) Error() string {
+	return "invalid schema: " + strings.Join(e.Problems, "; ")
+}
+
+// ValidateContentType validates the schema of a content type. Besides the
+// structural rules checked for every schema, a content type requires a
+// mandatory title field.
+func (s Schema) ValidateContentType(opts ValidationOptions) error {
+	v := newValidator(opts)
+
+	title := s.Field("title")
+	switch {
+	case title == nil:
+		v.addf("a field with uid 'title' is required")
+	case title.DataType != TypeText:
+		v.addf("title: must be a text field")
+	case !title.Mandatory:
+		v.addf("title: must be mandatory")
+	case opts.RequireUniqueTitle && !title.Unique:
+		v.addf("title: must be unique")
+	}
+
+	v.schema(nil, s)
+	return v.err()
+}
+
+// ValidateGlobalField validates the schema of a global field.
+func (s Schema) ValidateGlobalField(opts ValidationOptions) error {
+	v := newValidator(opts)
+	if len(s) == 0 {
+		v.addf("at least one field is required")
+	}
+	v.schema(nil, s)
+	return v.err()
+}
+
+type validator struct {
+	contentTypes map[string]bool
+	globalFields map[string]bool
+	problems     []string
+}
+
+func newValidator(opts ValidationOptions) *validator {
+	return &validator{
+		contentTypes: toSet(opts.ContentTypes),
+		globalFields: toSet(opts.GlobalFields),
+	}
+}
+
+func (v *validator) addf(format string, args ...interface{}) {
+	v.problems = append(v.problems, fmt.Sprintf(format, args...))
+}
+
+func (v *validator) err() error {
+	if len(v.problems) == 0 {
+		return nil
+	}
+	return &ValidationError{Problems: v.problems}
+}
+
+func (v *validator) schema(parent []string, s Schema) {
+	seen := map[string]bool{}
+	for i := range s {
+		field := &s[i]
+		path := strings.Join(append(append([]string{}, parent...), field.UID), ".")
+
+		switch {
+		case field.UID == "":
+			v.addf("%s: field %d has no uid", strings.Join(parent, "."), i)
+			continue
+		case !uidPattern.MatchString(field.UID):
+			v.addf("%s: uid must start with a letter and contain only lowercase letters, digits and underscores", path)
+		case seen[field.UID]:
+			v.addf("%s: uid is not unique", path)
+		}
+		seen[field.UID] = true
+
+		if field.DisplayName == "" {
+			v.addf("%s: display name is required", path)
+		}
+
+		v.field(path, field)
+	}
+}
+
+func (v *validator) field(path string, field *Field) {
+	switch field.DataType {
+	case "":
+		v.addf("%s: data type is required", path)
+
+	case TypeReference:
+		if len(field.ReferenceTo) == 0 {
+			v.addf("%s: at least one content type to reference is required", path)
+		}
+		for _, ct := range field.ReferenceTo {
+			if v.contentTypes != nil && !v.contentTypes[ct] {
+				v.addf("%s: referenced content type %q does not exist", path, ct)
+			}
+		}
+
+	case TypeGlobalField:
+		if len(field.ReferenceTo) != 1 {
+			v.addf("%s: exactly one global field is required", path)
+		} else if v.globalFields != nil && !v.globalFields[field.ReferenceTo[0]] {
+			v.addf("%s: global field %q does not exist", path, field.ReferenceTo[0])
+		}
+
+	case TypeGroup:
+		if len(field.Schema) == 0 {
+			v.addf("%s: a group requires at least one field", path)
+		}
+		v.schema([]string{path}, field.Schema)
+
+	case TypeBlocks:
+		if len(field.Blocks) == 0 {
+			v.addf("%s: at least one block is required", path)
+		}
+		seen := map[string]bool{}
+		for _, block := range field.Blocks {
+			blockPath := path + "." + block.UID
+			switch {
+			case block.UID == "":
+				v.addf("%s: block without uid", path)
+			case seen[block.UID]:
+				v.addf("%s: block uid is not unique", blockPath)
+			}
+			seen[block.UID] = true
+
+			if block.ReferenceTo != "" {
+				if v.globalFields != nil && !v.globalFields[block.ReferenceTo] {
+					v.addf("%s: global field %q does not exist", blockPath, block.ReferenceTo)
+				}
+				continue
+			}
+			if len(block.Schema) == 0 {
+				v.addf("%s: a block requires at least one field", blockPath)
+			}
+			v.schema([]string{blockPath}, block.Schema)
+		}
+
+	case TypeTaxonomy:
+		if len(field.Taxonomies) == 0 {
+			v.addf("%s: at least one taxonomy is required", path)
+		}
+	}
+
+	if field.Enum != nil && len(field.Enum.Choices) == 0 {
+		v.addf("%s: a select field requires at least one choice", path)
+	}
+	if field.MinInstance != nil && field.MaxInstance != nil && *field.MinInstance > *field.MaxInstance {
+		v.addf("%s: min instances exceed max instances", path)
+	}
+	if field.Min != nil && field.Max != nil && *field.Min > *field.Max {
+		v.addf("%s: min exceeds max", path)
+	}
+}
+
+func toSet(values []string) map[string]bool {
+	if values == nil {
+		return nil
+	}
+	result := make(map[string]bool, len(values))
+	for _, value := range values {
+		result[value] = true
+	}
+	return result
+}
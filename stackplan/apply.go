@@ -0,0 +1,95 @@
+package stackplan
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/labd/contentstack-go-sdk/management"
+)
+
+// ApplyOptions configures how the plan is applied.
+type ApplyOptions struct {
+	// DryRun only reports the steps, without changing the stack.
+	DryRun bool
+
+	// Progress is called before every step is applied.
+	Progress func(step Step)
+}
+
+// Apply applies the steps of the plan in order. Applying stops at the first
+// failing step; since every step is applied on its own, computing a new plan
+// afterwards continues where the previous one stopped.
+func (p *Plan) Apply(ctx context.Context, si *management.StackInstance, opts ApplyOptions) error {
+	for _, step := range p.Steps {
+		if opts.Progress != nil {
+			opts.Progress(step)
+		}
+		if opts.DryRun {
+			continue
+		}
+
+		if err := step.apply(ctx, si); err != nil {
+			return fmt.Errorf("unable to %s: %w", step, err)
+		}
+	}
+	return nil
+}
+
+func (s Step) apply(ctx context.Context, si *management.StackInstance) error {
+	var err error
+	switch s.Resource {
+	case ResourceLocale:
+		switch s.Action {
+		case ActionCreate:
+			_, err = si.LocaleCreate(ctx, s.input.(management.LocaleInput))
+		case ActionUpdate:
+			_, err = si.LocaleUpdate(ctx, s.uid, s.input.(management.LocaleInput))
+		case ActionDelete:
+			err = si.LocaleDelete(ctx, s.uid)
+		}
+
+	case ResourceEnvironment:
+		switch s.Action {
+		case ActionCreate:
+			_, err = si.EnvironmentCreate(ctx, s.input.(management.EnvironmentInput))
+		case ActionUpdate:
+			_, err = si.EnvironmentUpdate(ctx, s.uid, s.input.(management.EnvironmentInput))
+		case ActionDelete:
+			err = si.EnvironmentDelete(ctx, s.uid)
+		}
+
+	case ResourceGlobalField:
+		switch s.Action {
+		case ActionCreate:
+			_, err = si.GlobalFieldCreate(ctx, s.input.(management.GlobalFieldInput))
+		case ActionUpdate:
+			_, err = si.GlobalFieldUpdate(ctx, s.uid, s.input.(management.GlobalFieldInput))
+		case ActionDelete:
+			err = si.GlobalFieldDelete(ctx, s.uid)
+		}
+
+	case ResourceContentType:
+		switch s.Action {
+		case ActionCreate:
+			_, err = si.ContentTypeCreate(ctx, s.input.(management.ContentTypeInput))
+		case ActionUpdate:
+			_, err = si.ContentTypeUpdate(ctx, s.uid, s.input.(management.ContentTypeInput))
+		case ActionDelete:
+			err = si.ContentTypeDelete(ctx, s.uid)
+		}
+
+	case ResourceWebHook:
+		switch s.Action {
+		case ActionCreate:
+			_, err = si.WebHookCreate(ctx, s.input.(management.WebHookInput))
+		case ActionUpdate:
+			_, err = si.WebHookUpdate(ctx, s.uid, s.input.(management.WebHookInput))
+		case ActionDelete:
+			err = si.WebHookDelete(ctx, s.uid)
+		}
+
+	default:
+		return fmt.Errorf("unknown resource type %q", s.Resource)
+	}
+	return err
+}
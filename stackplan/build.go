@@ -0,0 +1,561 @@
+package stackplan
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+
+	"github.com/labd/contentstack-go-sdk/management"
+	"github.com/labd/contentstack-go-sdk/schema"
+)
+
+// planner computes the steps to go from the current to the desired state.
+type planner struct {
+	current *state
+	opts    Options
+	steps   []Step
+
+	// deferred contains the updates to apply once all content types exist.
+	deferred []Step
+
+	// pending contains the content types which are created later in the
+	// plan and thus can't be referenced yet.
+	pending map[string]bool
+}
+
+func buildPlan(desired Stack, current *state, opts Options) (*Plan, error) {
+	p := &planner{
+		current: current,
+		opts:    opts,
+		pending: map[string]bool{},
+	}
+
+	for _, input := range desired.ContentTypes {
+		if input.UID == nil {
+			return nil, fmt.Errorf("content type without uid")
+		}
+		if _, ok := current.contentTypes[*input.UID]; !ok {
+			p.pending[*input.UID] = true
+		}
+	}
+
+	if err := p.locales(desired.Locales); err != nil {
+		return nil, err
+	}
+	if err := p.environments(desired.Environments); err != nil {
+		return nil, err
+	}
+	if err := p.globalFields(desired.GlobalFields); err != nil {
+		return nil, err
+	}
+	if err := p.contentTypes(desired.ContentTypes); err != nil {
+		return nil, err
+	}
+	p.steps = append(p.steps, p.deferred...)
+
+	if err := p.webhooks(desired.WebHooks); err != nil {
+		return nil, err
+	}
+
+	if opts.Prune {
+		if err := p.prune(desired); err != nil {
+			return nil, err
+		}
+	}
+
+	return &Plan{Steps: p.steps}, nil
+}
+
+func (p *planner) add(step Step) {
+	p.steps = append(p.steps, step)
+}
+
+func (p *planner) locales(inputs []management.LocaleInput) error {
+	byCode := map[string]management.LocaleInput{}
+	codes := []string{}
+	for _, input := range inputs {
+		if _, ok := byCode[input.Code]; ok {
+			return fmt.Errorf("duplicate locale %q", input.Code)
+		}
+		byCode[input.Code] = input
+		codes = append(codes, input.Code)
+	}
+
+	codes = sortByDependencies(codes, func(code string) []string {
+		return []string{byCode[code].FallbackLocale}
+	})
+	for _, code := range codes {
+		input := byCode[code]
+		step := Step{Resource: ResourceLocale, Key: code, uid: code, input: input}
+
+		current, ok := p.current.locales[code]
+		switch {
+		case !ok:
+			step.Action = ActionCreate
+		case input.Name != "" && input.Name != current.Name,
+			input.FallbackLocale != "" && input.FallbackLocale != current.FallbackLocale:
+			step.Action = ActionUpdate
+		default:
+			continue
+		}
+		p.add(step)
+	}
+	return nil
+}
+
+func (p *planner) environments(inputs []management.EnvironmentInput) error {
+	seen := map[string]bool{}
+	for _, input := range inputs {
+		if seen[input.Name] {
+			return fmt.Errorf("duplicate environment %q", input.Name)
+		}
+		seen[input.Name] = true
+
+		step := Step{Resource: ResourceEnvironment, Key: input.Name, uid: input.Name, input: input}
+
+		current, ok := p.current.environments[input.Name]
+		switch {
+		case !ok:
+			step.Action = ActionCreate
+		case !equalURLs(input.URLs, current.URLs):
+			step.Action = ActionUpdate
+		default:
+			continue
+		}
+		p.add(step)
+	}
+	return nil
+}
+
+func (p *planner) globalFields(inputs []management.GlobalFieldInput) error {
+	byUID := map[string]management.GlobalFieldInput{}
+	schemas := map[string]schema.Schema{}
+	uids := []string{}
+	for _, input := range inputs {
+		if input.UID == nil {
+			return fmt.Errorf("global field without uid")
+		}
+		uid := *input.UID
+		if _, ok := byUID[uid]; ok {
+			return fmt.Errorf("duplicate global field %q", uid)
+		}
+
+		s, err := schema.Parse(input.Schema)
+		if err != nil {
+			return fmt.Errorf("global field %q: %w", uid, err)
+		}
+		byUID[uid] = input
+		schemas[uid] = s
+		uids = append(uids, uid)
+	}
+
+	uids = sortByDependencies(uids, func(uid string) []string {
+		_, globalFields := dependencies(schemas[uid])
+		return globalFields
+	})
+	for _, uid := range uids {
+		input := byUID[uid]
+		step := Step{Resource: ResourceGlobalField, Key: uid, uid: uid}
+
+		var currentSchema schema.Schema
+		current, ok := p.current.globalFields[uid]
+		if ok {
+			s, err := current.ParseSchema()
+			if err != nil {
+				return fmt.Errorf("global field %q: %w", uid, err)
+			}
+			currentSchema = s
+
+			step.Action = ActionUpdate
+			step.Changes = schema.Diff(alignMetadata(currentSchema, schemas[uid]), schemas[uid])
+			if len(step.Changes) == 0 &&
+				!changed(input.Title, current.Title) &&
+				!changed(input.Description, current.Description) {
+				continue
+			}
+		} else {
+			step.Action = ActionCreate
+		}
+
+		stripped, partial, err := p.strip(schemas[uid], currentSchema, uid)
+		if err != nil {
+			return fmt.Errorf("global field %q: %w", uid, err)
+		}
+		step.input = input
+		if partial {
+			partialInput := input
+			partialInput.Schema = stripped
+			step.input = partialInput
+			step.Partial = true
+
+			p.deferred = append(p.deferred, Step{
+				Action:   ActionUpdate,
+				Resource: ResourceGlobalField,
+				Key:      uid,
+				uid:      uid,
+				input:    input,
+			})
+		}
+		p.add(step)
+	}
+	return nil
+}
+
+func (p *planner) contentTypes(inputs []management.ContentTypeInput) error {
+	byUID := map[string]management.ContentTypeInput{}
+	schemas := map[string]schema.Schema{}
+	uids := []string{}
+	for _, input := range inputs {
+		uid := *input.UID
+		if _, ok := byUID[uid]; ok {
+			return fmt.Errorf("duplicate content type %q", uid)
+		}
+
+		s, err := schema.Parse(input.Schema)
+		if err != nil {
+			return fmt.Errorf("content type %q: %w", uid, err)
+		}
+		byUID[uid] = input
+		schemas[uid] = s
+		uids = append(uids, uid)
+	}
+
+	uids = sortByDependencies(uids, func(uid string) []string {
+		contentTypes, _ := dependencies(schemas[uid])
+		return contentTypes
+	})
+	for _, uid := range uids {
+		input := byUID[uid]
+		step := Step{Resource: ResourceContentType, Key: uid, uid: uid}
+
+		var currentSchema schema.Schema
+		current, ok := p.current.contentTypes[uid]
+		if ok {
+			s, err := current.ParseSchema()
+			if err != nil {
+				return fmt.Errorf("content type %q: %w", uid, err)
+			}
+			currentSchema = s
+
+			step.Action = ActionUpdate
+			step.Changes = schema.Diff(alignMetadata(currentSchema, schemas[uid]), schemas[uid])
+			if len(step.Changes) == 0 &&
+				!changed(input.Title, current.Title) &&
+				!changed(input.Description, current.Description) &&
+				!changedOptions(input.Options, current.Options) {
+				continue
+			}
+		} else {
+			step.Action = ActionCreate
+		}
+
+		stripped, partial, err := p.strip(schemas[uid], currentSchema, uid)
+		if err != nil {
+			return fmt.Errorf("content type %q: %w", uid, err)
+		}
+		step.input = input
+		if partial {
+			partialInput := input
+			partialInput.Schema = stripped
+			step.input = partialInput
+			step.Partial = true
+
+			p.deferred = append(p.deferred, Step{
+				Action:   ActionUpdate,
+				Resource: ResourceContentType,
+				Key:      uid,
+				uid:      uid,
+				input:    input,
+			})
+		}
+		p.add(step)
+
+		// The content type can be referenced from now on
+		delete(p.pending, uid)
+	}
+	return nil
+}
+
+// strip removes the references to the content types which are not created yet.
+// A content type referencing itself is only available once it exists.
+func (p *planner) strip(s schema.Schema, current schema.Schema, uid string) (raw []byte, partial bool, err error) {
+	stripped, partial := stripReferences(s, current, func(ref string) bool {
+		return !p.pending[ref]
+	})
+	if !partial {
+		return nil, false, nil
+	}
+
+	raw, err = stripped.Raw()
+	if err != nil {
+		return nil, false, err
+	}
+	return raw, true, nil
+}
+
+func (p *planner) webhooks(inputs []management.WebHookInput) error {
+	seen := map[string]bool{}
+	for _, input := range inputs {
+		if seen[input.Name] {
+			return fmt.Errorf("duplicate webhook %q", input.Name)
+		}
+		seen[input.Name] = true
+
+		step := Step{Resource: ResourceWebHook, Key: input.Name, input: input}
+
+		current, ok := p.current.webhooks[input.Name]
+		switch {
+		case !ok:
+			step.Action = ActionCreate
+		case webhookChanged(input, current):
+			step.Action = ActionUpdate
+			step.uid = current.UID
+		default:
+			continue
+		}
+		p.add(step)
+	}
+	return nil
+}
+
+// prune deletes the resources which are not part of the desired state. Content
+// types and global fields are deleted before the resources they depend on.
+func (p *planner) prune(desired Stack) error {
+	keep := map[ResourceType]map[string]bool{
+		ResourceLocale:      {},
+		ResourceEnvironment: {},
+		ResourceGlobalField: {},
+		ResourceContentType: {},
+		ResourceWebHook:     {},
+	}
+	for _, input := range desired.Locales {
+		keep[ResourceLocale][input.Code] = true
+	}
+	for _, input := range desired.Environments {
+		keep[ResourceEnvironment][input.Name] = true
+	}
+	for _, input := range desired.GlobalFields {
+		keep[ResourceGlobalField][*input.UID] = true
+	}
+	for _, input := range desired.ContentTypes {
+		keep[ResourceContentType][*input.UID] = true
+	}
+	for _, input := range desired.WebHooks {
+		keep[ResourceWebHook][input.Name] = true
+	}
+
+	for _, name := range p.current.webhookOrder {
+		if !keep[ResourceWebHook][name] {
+			p.add(Step{
+				Action:   ActionDelete,
+				Resource: ResourceWebHook,
+				Key:      name,
+				uid:      p.current.webhooks[name].UID,
+			})
+		}
+	}
+
+	contentTypes := map[string][]string{}
+	for uid, current := range p.current.contentTypes {
+		s, err := current.ParseSchema()
+		if err != nil {
+			return fmt.Errorf("content type %q: %w", uid, err)
+		}
+		contentTypes[uid], _ = dependencies(s)
+	}
+	order := sortByDependencies(p.current.contentTypeOrder, func(uid string) []string {
+		return contentTypes[uid]
+	})
+	for _, uid := range reversed(order) {
+		if !keep[ResourceContentType][uid] {
+			p.add(Step{Action: ActionDelete, Resource: ResourceContentType, Key: uid, uid: uid})
+		}
+	}
+
+	globalFields := map[string][]string{}
+	for uid, current := range p.current.globalFields {
+		s, err := current.ParseSchema()
+		if err != nil {
+			return fmt.Errorf("global field %q: %w", uid, err)
+		}
+		_, globalFields[uid] = dependencies(s)
+	}
+	order = sortByDependencies(p.current.globalFieldOrder, func(uid string) []string {
+		return globalFields[uid]
+	})
+	for _, uid := range reversed(order) {
+		if !keep[ResourceGlobalField][uid] {
+			p.add(Step{Action: ActionDelete, Resource: ResourceGlobalField, Key: uid, uid: uid})
+		}
+	}
+
+	for _, name := range p.current.environmentOrder {
+		if !keep[ResourceEnvironment][name] {
+			p.add(Step{Action: ActionDelete, Resource: ResourceEnvironment, Key: name, uid: name})
+		}
+	}
+
+	order = sortByDependencies(p.current.localeOrder, func(code string) []string {
+		return []string{p.current.locales[code].FallbackLocale}
+	})
+	for _, code := range reversed(order) {
+		locale := p.current.locales[code]
+		if keep[ResourceLocale][code] || isMasterLocale(locale) {
+			continue
+		}
+		p.add(Step{Action: ActionDelete, Resource: ResourceLocale, Key: code, uid: code})
+	}
+	return nil
+}
+
+// isMasterLocale returns whether the locale is the master locale of the stack,
+// which is the only locale without a fallback.
+func isMasterLocale(locale management.Locale) bool {
+	return locale.FallbackLocale == "" || locale.FallbackLocale == locale.Code
+}
+
+// changed returns whether the desired value is set and differs from the
+// current value.
+func changed(desired *string, current string) bool {
+	return desired != nil && *desired != current
+}
+
+// changedOptions returns whether the desired options differ from the current
+// options. An empty and a missing list of sub titles are equal.
+func changedOptions(desired *management.ContentTypeOptions, current *management.ContentTypeOptions) bool {
+	if desired == nil {
+		return false
+	}
+	if current == nil {
+		return true
+	}
+	a, b := *desired, *current
+	if len(a.SubTitle) == 0 {
+		a.SubTitle = nil
+	}
+	if len(b.SubTitle) == 0 {
+		b.SubTitle = nil
+	}
+	return !reflect.DeepEqual(a, b)
+}
+
+// alignMetadata returns the current schema with the field metadata limited
+// to the keys set by the desired schema. The API adds keys like _default and
+// version to the metadata, which would otherwise always be reported as a
+// change.
+func alignMetadata(current schema.Schema, desired schema.Schema) schema.Schema {
+	if current == nil {
+		return nil
+	}
+	result := make(schema.Schema, len(current))
+	for i, field := range current {
+		result[i] = field
+		want := desired.Field(field.UID)
+		if want == nil {
+			continue
+		}
+		result[i].FieldMetadata = limitMetadata(field.FieldMetadata, want.FieldMetadata)
+		result[i].Schema = alignMetadata(field.Schema, want.Schema)
+		if field.Blocks != nil {
+			result[i].Blocks = make([]schema.Block, len(field.Blocks))
+			for j, block := range field.Blocks {
+				result[i].Blocks[j] = block
+				for _, wantBlock := range want.Blocks {
+					if wantBlock.UID == block.UID {
+						result[i].Blocks[j].Schema = alignMetadata(block.Schema, wantBlock.Schema)
+						break
+					}
+				}
+			}
+		}
+	}
+	return result
+}
+
+// limitMetadata returns the current metadata with only the keys which are
+// set in the desired metadata.
+func limitMetadata(current *schema.FieldMetadata, desired *schema.FieldMetadata) *schema.FieldMetadata {
+	if current == nil || desired == nil {
+		return nil
+	}
+	keys := map[string]json.RawMessage{}
+	values := map[string]json.RawMessage{}
+	if err := remarshal(desired, &keys); err != nil {
+		return current
+	}
+	if err := remarshal(current, &values); err != nil {
+		return current
+	}
+	for key := range values {
+		if _, ok := keys[key]; !ok {
+			delete(values, key)
+		}
+	}
+
+	result := &schema.FieldMetadata{}
+	if err := remarshal(values, result); err != nil {
+		return current
+	}
+	return result
+}
+
+func remarshal(src interface{}, dst interface{}) error {
+	data, err := json.Marshal(src)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(data, dst)
+}
+
+func equalURLs(a []management.EnvironmentUrl, b []management.EnvironmentUrl) bool {
+	return reflect.DeepEqual(sortedURLs(a), sortedURLs(b))
+}
+
+func sortedURLs(urls []management.EnvironmentUrl) []management.EnvironmentUrl {
+	result := append([]management.EnvironmentUrl{}, urls...)
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].Locale != result[j].Locale {
+			return result[i].Locale < result[j].Locale
+		}
+		return result[i].URL < result[j].URL
+	})
+	return result
+}
+
+// webhookChanged compares the webhook with the desired state. The passwords of
+// the destinations are not returned by the API and thus not compared.
+func webhookChanged(input management.WebHookInput, current management.WebHook) bool {
+	if len(input.Branches) > 0 && !equalSets(input.Branches, current.Branches) {
+		return true
+	}
+	if !equalSets(input.Channels, current.Channels) ||
+		input.RetryPolicy != current.RetryPolicy ||
+		input.Disabled != current.Disabled ||
+		input.ConcisePayload != current.ConcisePayload {
+		return true
+	}
+
+	if len(input.Destinations) != len(current.Destinations) {
+		return true
+	}
+	for i := range input.Destinations {
+		a, b := input.Destinations[i], current.Destinations[i]
+		a.HttpBasicPassword, b.HttpBasicPassword = "", ""
+		if len(a.CustomHeaders) == 0 && len(b.CustomHeaders) == 0 {
+			a.CustomHeaders, b.CustomHeaders = nil, nil
+		}
+		if !reflect.DeepEqual(a, b) {
+			return true
+		}
+	}
+	return false
+}
+
+func equalSets(a []string, b []string) bool {
+	a = append([]string{}, a...)
+	b = append([]string{}, b...)
+	sort.Strings(a)
+	sort.Strings(b)
+	return reflect.DeepEqual(a, b)
+}
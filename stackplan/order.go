@@ -0,0 +1,132 @@
+package stackplan
+
+import (
+	"github.com/labd/contentstack-go-sdk/schema"
+)
+
+// sortByDependencies orders the keys so that every key comes after the keys it
+// depends on. Dependencies on keys which are not in the list are ignored, as
+// are cycles. The order is otherwise kept stable.
+func sortByDependencies(keys []string, dependencies func(key string) []string) []string {
+	known := map[string]bool{}
+	for _, key := range keys {
+		known[key] = true
+	}
+
+	result := make([]string, 0, len(keys))
+	visited := map[string]bool{}
+	var visit func(key string)
+	visit = func(key string) {
+		if visited[key] {
+			return
+		}
+		visited[key] = true
+		for _, dep := range dependencies(key) {
+			if known[dep] {
+				visit(dep)
+			}
+		}
+		result = append(result, key)
+	}
+
+	for _, key := range keys {
+		visit(key)
+	}
+	return result
+}
+
+func reversed(keys []string) []string {
+	result := make([]string, len(keys))
+	for i, key := range keys {
+		result[len(keys)-1-i] = key
+	}
+	return result
+}
+
+// dependencies returns the content types referenced by the schema and the
+// global fields used in the schema.
+func dependencies(s schema.Schema) (contentTypes []string, globalFields []string) {
+	_ = schema.Walk(s, func(path []string, field *schema.Field) error {
+		switch field.DataType {
+		case schema.TypeReference:
+			contentTypes = append(contentTypes, field.ReferenceTo...)
+		case schema.TypeGlobalField:
+			globalFields = append(globalFields, field.ReferenceTo...)
+		case schema.TypeBlocks:
+			for _, block := range field.Blocks {
+				if block.ReferenceTo != "" {
+					globalFields = append(globalFields, block.ReferenceTo)
+				}
+			}
+		}
+		return nil
+	})
+	return contentTypes, globalFields
+}
+
+// stripReferences returns a copy of the schema without references to the
+// content types which are not available. Reference fields without any
+// remaining content type are left out, unless the field is part of the current
+// schema in which case the current definition is kept so no data is lost.
+func stripReferences(s schema.Schema, current schema.Schema, available func(uid string) bool) (schema.Schema, bool) {
+	result := make(schema.Schema, 0, len(s))
+	stripped := false
+
+	for _, field := range s {
+		currentField := current.Field(field.UID)
+
+		switch field.DataType {
+		case schema.TypeReference:
+			refs := schema.StringList{}
+			for _, ref := range field.ReferenceTo {
+				if available(ref) {
+					refs = append(refs, ref)
+				}
+			}
+			if len(refs) == len(field.ReferenceTo) {
+				break
+			}
+
+			stripped = true
+			if len(refs) == 0 {
+				if currentField != nil {
+					result = append(result, *currentField)
+				}
+				continue
+			}
+			field.ReferenceTo = refs
+
+		case schema.TypeGroup:
+			var currentSchema schema.Schema
+			if currentField != nil {
+				currentSchema = currentField.Schema
+			}
+
+			var ok bool
+			field.Schema, ok = stripReferences(field.Schema, currentSchema, available)
+			stripped = stripped || ok
+
+		case schema.TypeBlocks:
+			blocks := make([]schema.Block, len(field.Blocks))
+			for i, block := range field.Blocks {
+				var currentSchema schema.Schema
+				if currentField != nil {
+					for _, b := range currentField.Blocks {
+						if b.UID == block.UID {
+							currentSchema = b.Schema
+						}
+					}
+				}
+
+				var ok bool
+				block.Schema, ok = stripReferences(block.Schema, currentSchema, available)
+				stripped = stripped || ok
+				blocks[i] = block
+			}
+			field.Blocks = blocks
+		}
+
+		result = append(result, field)
+	}
+	return result, stripped
+}
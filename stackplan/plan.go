@@ -0,0 +1,217 @@
+// Package stackplan manages the content types, global fields, locales,
+// environments and webhooks of a stack declaratively. It compares the desired
+// state with the current state of the stack and computes an ordered plan of
+// create, update and delete steps, which can then be applied.
+//
+//	plan, err := stackplan.NewPlan(ctx, instance, desired, stackplan.Options{})
+//	if err != nil {
+//		...
+//	}
+//	fmt.Print(plan.Render())
+//	err = plan.Apply(ctx, instance, stackplan.ApplyOptions{})
+package stackplan
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/labd/contentstack-go-sdk/management"
+	"github.com/labd/contentstack-go-sdk/schema"
+)
+
+// Stack is the desired state of a stack. Locales are identified by their
+// code, environments and webhooks by their name, and global fields and content
+// types by their uid.
+type Stack struct {
+	Locales      []management.LocaleInput
+	Environments []management.EnvironmentInput
+	GlobalFields []management.GlobalFieldInput
+	ContentTypes []management.ContentTypeInput
+	WebHooks     []management.WebHookInput
+}
+
+// Options configures how the plan is computed.
+type Options struct {
+	// Prune deletes the resources which are not part of the desired state.
+	// The master locale is never deleted.
+	Prune bool
+}
+
+type ResourceType string
+
+const (
+	ResourceLocale      ResourceType = "locale"
+	ResourceEnvironment ResourceType = "environment"
+	ResourceGlobalField ResourceType = "global_field"
+	ResourceContentType ResourceType = "content_type"
+	ResourceWebHook     ResourceType = "webhook"
+)
+
+type Action string
+
+const (
+	ActionCreate Action = "create"
+	ActionUpdate Action = "update"
+	ActionDelete Action = "delete"
+)
+
+// Step is a single step of the plan.
+type Step struct {
+	Action   Action
+	Resource ResourceType
+	Key      string
+
+	// Changes contains the schema changes of a global field or content type.
+	Changes schema.Changes
+
+	// Partial is set when references to content types which don't exist yet
+	// are left out of the schema. A later step updates the resource with the
+	// complete schema.
+	Partial bool
+
+	// uid is the identifier used by the API, which differs from the key for
+	// webhooks.
+	uid   string
+	input interface{}
+}
+
+func (s Step) String() string {
+	result := fmt.Sprintf("%s %s %s", s.Action, s.Resource, s.Key)
+	if s.Partial {
+		result += " (without references)"
+	}
+	return result
+}
+
+// Plan is the ordered list of steps to reach the desired state. Resources are
+// created and updated in dependency order (locales before the locales using
+// them as fallback, global fields before the content types using them) and
+// deleted in reverse order.
+type Plan struct {
+	Steps []Step
+}
+
+// Empty returns whether the stack is already in the desired state.
+func (p *Plan) Empty() bool {
+	return len(p.Steps) == 0
+}
+
+// Render returns a human readable representation of the plan.
+func (p *Plan) Render() string {
+	if p.Empty() {
+		return "No changes. The stack is in the desired state.\n"
+	}
+
+	counts := map[Action]int{}
+	b := strings.Builder{}
+	for _, step := range p.Steps {
+		counts[step.Action]++
+
+		symbol := map[Action]string{ActionCreate: "+", ActionUpdate: "~", ActionDelete: "-"}[step.Action]
+		fmt.Fprintf(&b, "%s %s\n", symbol, step)
+		for _, change := range step.Changes {
+			fmt.Fprintf(&b, "    %s\n", change)
+		}
+	}
+	fmt.Fprintf(&b, "\nPlan: %d to create, %d to update, %d to delete.\n",
+		counts[ActionCreate], counts[ActionUpdate], counts[ActionDelete])
+	return b.String()
+}
+
+// NewPlan fetches the current state of the stack and computes the plan to
+// reach the desired state.
+func NewPlan(ctx context.Context, si *management.StackInstance, desired Stack, opts Options) (*Plan, error) {
+	current, err := fetchState(ctx, si)
+	if err != nil {
+		return nil, err
+	}
+	return buildPlan(desired, current, opts)
+}
+
+// Apply computes the plan to reach the desired state and applies it.
+func Apply(ctx context.Context, si *management.StackInstance, desired Stack, opts Options, applyOpts ApplyOptions) (*Plan, error) {
+	plan, err := NewPlan(ctx, si, desired, opts)
+	if err != nil {
+		return nil, err
+	}
+	return plan, plan.Apply(ctx, si, applyOpts)
+}
+
+// state is the current state of the stack.
+type state struct {
+	locales      map[string]management.Locale
+	environments map[string]management.Environment
+	globalFields map[string]management.GlobalField
+	contentTypes map[string]management.ContentType
+	webhooks     map[string]management.WebHook
+
+	// The order in which the resources were returned, used to delete them
+	// in a deterministic order.
+	localeOrder      []string
+	environmentOrder []string
+	globalFieldOrder []string
+	contentTypeOrder []string
+	webhookOrder     []string
+}
+
+func fetchState(ctx context.Context, si *management.StackInstance) (*state, error) {
+	locales, err := si.LocaleFetchAll(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("fetching locales: %w", err)
+	}
+	environments, err := si.EnvironmentFetchAll(ctx, "")
+	if err != nil {
+		return nil, fmt.Errorf("fetching environments: %w", err)
+	}
+	globalFields, err := si.GlobalFieldFetchAll(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("fetching global fields: %w", err)
+	}
+	contentTypes, err := si.ContentTypeFetchAll(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("fetching content types: %w", err)
+	}
+	webhooks, err := si.WebHookFetchAll(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("fetching webhooks: %w", err)
+	}
+	return newState(locales, environments, globalFields, contentTypes, webhooks), nil
+}
+
+func newState(
+	locales []management.Locale,
+	environments []management.Environment,
+	globalFields []management.GlobalField,
+	contentTypes []management.ContentType,
+	webhooks []management.WebHook,
+) *state {
+	s := &state{
+		locales:      map[string]management.Locale{},
+		environments: map[string]management.Environment{},
+		globalFields: map[string]management.GlobalField{},
+		contentTypes: map[string]management.ContentType{},
+		webhooks:     map[string]management.WebHook{},
+	}
+	for _, l := range locales {
+		s.locales[l.Code] = l
+		s.localeOrder = append(s.localeOrder, l.Code)
+	}
+	for _, e := range environments {
+		s.environments[e.Name] = e
+		s.environmentOrder = append(s.environmentOrder, e.Name)
+	}
+	for _, g := range globalFields {
+		s.globalFields[g.UID] = g
+		s.globalFieldOrder = append(s.globalFieldOrder, g.UID)
+	}
+	for _, c := range contentTypes {
+		s.contentTypes[c.UID] = c
+		s.contentTypeOrder = append(s.contentTypeOrder, c.UID)
+	}
+	for _, w := range webhooks {
+		s.webhooks[w.Name] = w
+		s.webhookOrder = append(s.webhookOrder, w.Name)
+	}
+	return s
+}
@@ -0,0 +1,330 @@
+package stackplan
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/labd/contentstack-go-sdk/management"
+	"github.com/labd/contentstack-go-sdk/schema"
+)
+
+func contentType(t *testing.T, uid string, fields ...*schema.FieldBuilder) management.ContentTypeInput {
+	t.Helper()
+	raw, err := schema.Fields(append([]*schema.FieldBuilder{schema.Text("title").Mandatory().Unique()}, fields...)...).Raw()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	return management.ContentTypeInput{UID: management.StringRef(uid), Title: management.StringRef(uid), Schema: raw}
+}
+
+func globalField(t *testing.T, uid string, fields ...*schema.FieldBuilder) management.GlobalFieldInput {
+	t.Helper()
+	raw, err := schema.Fields(fields...).Raw()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	return management.GlobalFieldInput{UID: management.StringRef(uid), Title: management.StringRef(uid), Schema: raw}
+}
+
+func steps(plan *Plan) []string {
+	result := []string{}
+	for _, step := range plan.Steps {
+		result = append(result, step.String())
+	}
+	return result
+}
+
+func TestBuildPlan_Create(t *testing.T) {
+	desired := Stack{
+		Locales: []management.LocaleInput{
+			{Code: "nl-be", FallbackLocale: "nl-nl"},
+			{Code: "nl-nl", FallbackLocale: "en-us"},
+		},
+		Environments: []management.EnvironmentInput{{Name: "production"}},
+		GlobalFields: []management.GlobalFieldInput{
+			globalField(t, "seo", schema.Text("meta_title"), schema.GlobalField("social", "social")),
+			globalField(t, "social", schema.Text("handle")),
+		},
+		ContentTypes: []management.ContentTypeInput{
+			contentType(t, "blog", schema.Reference("author", "author"), schema.GlobalField("seo", "seo")),
+			contentType(t, "author", schema.Reference("related", "author")),
+		},
+		WebHooks: []management.WebHookInput{{Name: "deploy"}},
+	}
+	current := newState([]management.Locale{{Code: "en-us"}}, nil, nil, nil, nil)
+
+	plan, err := buildPlan(desired, current, Options{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	want := []string{
+		"create locale nl-nl",
+		"create locale nl-be",
+		"create environment production",
+		"create global_field social",
+		"create global_field seo",
+		"create content_type author (without references)",
+		"create content_type blog",
+		"update content_type author",
+		"create webhook deploy",
+	}
+	if got := steps(plan); !reflect.DeepEqual(got, want) {
+		t.Errorf("steps = %v, want %v", got, want)
+	}
+
+	// The self reference is left out when creating the content type
+	partial, err := schema.Parse(plan.Steps[5].input.(management.ContentTypeInput).Schema)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if partial.Field("related") != nil {
+		t.Error("partial schema contains the self reference")
+	}
+}
+
+func TestBuildPlan_Update(t *testing.T) {
+	blog := contentType(t, "blog", schema.Text("body"))
+	current := newState(
+		[]management.Locale{{Code: "en-us"}},
+		[]management.Environment{{Name: "production", URLs: []management.EnvironmentUrl{{Locale: "en-us", URL: "https://example.com"}}}},
+		nil,
+		[]management.ContentType{{UID: "blog", Title: "blog", Schema: blog.Schema}},
+		[]management.WebHook{{UID: "wh1", Name: "deploy", Destinations: []management.WebhookDestination{{TargetURL: "https://example.com", HttpBasicPassword: "***"}}}},
+	)
+
+	desired := Stack{
+		Environments: []management.EnvironmentInput{{Name: "production", URLs: []management.EnvironmentUrl{{Locale: "en-us", URL: "https://example.com"}}}},
+		ContentTypes: []management.ContentTypeInput{blog},
+		WebHooks:     []management.WebHookInput{{Name: "deploy", Destinations: []management.WebhookDestination{{TargetURL: "https://example.com", HttpBasicPassword: "secret"}}}},
+	}
+	plan, err := buildPlan(desired, current, Options{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !plan.Empty() {
+		t.Fatalf("plan is not empty: %v", steps(plan))
+	}
+
+	desired.ContentTypes = []management.ContentTypeInput{contentType(t, "blog", schema.Text("body"), schema.Number("rating"))}
+	desired.WebHooks[0].Disabled = true
+	plan, err = buildPlan(desired, current, Options{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	want := []string{"update content_type blog", "update webhook deploy"}
+	if got := steps(plan); !reflect.DeepEqual(got, want) {
+		t.Fatalf("steps = %v, want %v", got, want)
+	}
+	if len(plan.Steps[0].Changes) != 1 || plan.Steps[0].Changes[0].Path != "rating" {
+		t.Errorf("unexpected changes %v", plan.Steps[0].Changes)
+	}
+	if plan.Steps[1].uid != "wh1" {
+		t.Errorf("uid = %q, want %q", plan.Steps[1].uid, "wh1")
+	}
+
+	// Only the options differ
+	current.contentTypes["blog"] = management.ContentType{
+		UID:     "blog",
+		Title:   "blog",
+		Schema:  blog.Schema,
+		Options: &management.ContentTypeOptions{Title: "title", SubTitle: []string{}},
+	}
+	blog.Options = &management.ContentTypeOptions{Title: "title"}
+	desired = Stack{ContentTypes: []management.ContentTypeInput{blog}}
+	plan, err = buildPlan(desired, current, Options{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !plan.Empty() {
+		t.Fatalf("plan is not empty: %v", steps(plan))
+	}
+
+	blog.Options = &management.ContentTypeOptions{Title: "title", Singleton: true}
+	desired = Stack{ContentTypes: []management.ContentTypeInput{blog}}
+	plan, err = buildPlan(desired, current, Options{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got := steps(plan); !reflect.DeepEqual(got, []string{"update content_type blog"}) {
+		t.Errorf("steps = %v, want %v", got, []string{"update content_type blog"})
+	}
+}
+
+// serverShaped returns the schema as returned by the API, which adds keys to
+// the metadata of the fields.
+func serverShaped(t *testing.T, raw json.RawMessage) json.RawMessage {
+	t.Helper()
+	var fields []interface{}
+	if err := json.Unmarshal(raw, &fields); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	var walk func(v interface{})
+	walk = func(v interface{}) {
+		switch value := v.(type) {
+		case []interface{}:
+			for _, item := range value {
+				walk(item)
+			}
+		case map[string]interface{}:
+			if _, ok := value["data_type"]; ok {
+				metadata, _ := value["field_metadata"].(map[string]interface{})
+				if metadata == nil {
+					metadata = map[string]interface{}{}
+				}
+				metadata["_default"] = true
+				metadata["version"] = 3
+				value["field_metadata"] = metadata
+			}
+			for _, item := range value {
+				walk(item)
+			}
+		}
+	}
+	walk(fields)
+
+	data, err := json.Marshal(fields)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	return data
+}
+
+func TestBuildPlan_ServerMetadata(t *testing.T) {
+	seo := globalField(t, "seo", schema.Text("meta_title").Description("Shown in search results"))
+	blog := contentType(t, "blog",
+		schema.Text("summary").Description("Short summary"),
+		schema.Group("author", schema.Text("name").Placeholder("Name")),
+		schema.Blocks("sections", schema.NewBlock("hero", "Hero", schema.Text("heading").Instruction("Keep it short"))),
+		schema.GlobalField("seo", "seo"),
+	)
+	current := newState(
+		[]management.Locale{{Code: "en-us"}},
+		nil,
+		[]management.GlobalField{{UID: "seo", Title: "seo", Schema: serverShaped(t, seo.Schema)}},
+		[]management.ContentType{{UID: "blog", Title: "blog", Schema: serverShaped(t, blog.Schema)}},
+		nil,
+	)
+
+	desired := Stack{
+		GlobalFields: []management.GlobalFieldInput{seo},
+		ContentTypes: []management.ContentTypeInput{blog},
+	}
+	plan, err := buildPlan(desired, current, Options{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !plan.Empty() {
+		t.Fatalf("plan is not empty: %v", steps(plan))
+	}
+
+	// A change of metadata set by the desired schema is still detected
+	desired.ContentTypes = []management.ContentTypeInput{contentType(t, "blog",
+		schema.Text("summary").Description("Summary"),
+		schema.Group("author", schema.Text("name").Placeholder("Name")),
+		schema.Blocks("sections", schema.NewBlock("hero", "Hero", schema.Text("heading").Instruction("Keep it short"))),
+		schema.GlobalField("seo", "seo"),
+	)}
+	plan, err = buildPlan(desired, current, Options{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got := steps(plan); !reflect.DeepEqual(got, []string{"update content_type blog"}) {
+		t.Fatalf("steps = %v, want %v", got, []string{"update content_type blog"})
+	}
+	if changes := plan.Steps[0].Changes; len(changes) != 1 || changes[0].Path != "summary" {
+		t.Errorf("unexpected changes %v", changes)
+	}
+}
+
+func TestBuildPlan_Prune(t *testing.T) {
+	author := contentType(t, "author")
+	blog := contentType(t, "blog", schema.Reference("author", "author"))
+	current := newState(
+		[]management.Locale{{Code: "en-us"}, {Code: "nl-be", FallbackLocale: "nl-nl"}, {Code: "nl-nl", FallbackLocale: "en-us"}},
+		[]management.Environment{{Name: "staging"}},
+		[]management.GlobalField{{UID: "seo", Schema: json.RawMessage(`[]`)}},
+		[]management.ContentType{{UID: "author", Schema: author.Schema}, {UID: "blog", Schema: blog.Schema}},
+		[]management.WebHook{{UID: "wh1", Name: "deploy"}},
+	)
+
+	plan, err := buildPlan(Stack{}, current, Options{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !plan.Empty() {
+		t.Fatalf("plan without prune is not empty: %v", steps(plan))
+	}
+
+	plan, err = buildPlan(Stack{}, current, Options{Prune: true})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := []string{
+		"delete webhook deploy",
+		"delete content_type blog",
+		"delete content_type author",
+		"delete global_field seo",
+		"delete environment staging",
+		"delete locale nl-be",
+		"delete locale nl-nl",
+	}
+	if got := steps(plan); !reflect.DeepEqual(got, want) {
+		t.Errorf("steps = %v, want %v", got, want)
+	}
+}
+
+func TestPlan_Apply(t *testing.T) {
+	var mu sync.Mutex
+	requests := []string{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		requests = append(requests, r.Method+" "+r.URL.Path)
+		mu.Unlock()
+		_, _ = w.Write([]byte(`{}`))
+	}))
+	t.Cleanup(server.Close)
+
+	client, err := management.NewClient(management.ClientConfig{BaseURL: server.URL, AuthToken: "token"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	stack, err := client.Stack(&management.StackAuth{ApiKey: "api-key"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	plan, err := buildPlan(Stack{
+		ContentTypes: []management.ContentTypeInput{contentType(t, "page", schema.Reference("parent", "page"))},
+	}, newState(nil, nil, nil, nil, []management.WebHook{{UID: "wh1", Name: "deploy"}}), Options{Prune: true})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	progress := []string{}
+	err = plan.Apply(context.Background(), stack, ApplyOptions{
+		Progress: func(step Step) { progress = append(progress, step.String()) },
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	want := []string{
+		"POST /v3/content_types/",
+		"PUT /v3/content_types/page",
+		"DELETE /v3/webhooks/wh1",
+	}
+	if !reflect.DeepEqual(requests, want) {
+		t.Errorf("requests = %v, want %v", requests, want)
+	}
+	if len(progress) != 3 || !strings.HasPrefix(progress[0], "create content_type page") {
+		t.Errorf("progress = %v", progress)
+	}
+}